@@ -0,0 +1,146 @@
+package category
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+// Activity masukan generik untuk menghitung emisi sebuah kategori
+type Activity struct {
+	Type     string            // sub-jenis, mis. fuel_type, waste_stream, cabin_class
+	Quantity float64           // besaran utama, mis. km, kWh, kg
+	Attrs    map[string]string // atribut tambahan khusus kategori
+}
+
+// Estimate hasil perhitungan emisi (kg CO2e, disimpan di kolom carbon_emission_g)
+type Estimate struct {
+	Quantity float64 // besaran yang disimpan, mis. jarak total penerbangan
+	Emission float64
+	Avoided  float64
+}
+
+// Query potongan SQL beserta argumennya. Placeholder $1 selalu user_id,
+// argumen tambahan dimulai dari $2.
+type Query struct {
+	SQL  string
+	Args []any
+}
+
+// Window rentang waktu aktivitas yang dihitung untuk progres misi. Nil berarti tak terbatas.
+type Window struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// Filter menghasilkan kondisi SQL tambahan untuk kolom waktu, placeholder dimulai dari $next
+func (w Window) Filter(column string, next int) (string, []any) {
+	var cond string
+	var args []any
+	if w.From != nil {
+		cond += fmt.Sprintf(" AND %s >= $%d", column, next)
+		args = append(args, *w.From)
+		next++
+	}
+	if w.To != nil {
+		cond += fmt.Sprintf(" AND %s < $%d", column, next)
+		args = append(args, *w.To)
+	}
+	return cond, args
+}
+
+// Contains true kalau waktu t masuk ke dalam window
+func (w Window) Contains(t time.Time) bool {
+	if w.From != nil && t.Before(*w.From) {
+		return false
+	}
+	if w.To != nil && !t.Before(*w.To) {
+		return false
+	}
+	return true
+}
+
+// Category satu jenis aktivitas yang menghasilkan emisi karbon.
+// Kategori baru cukup mengimplementasikan interface ini lalu memanggil Register.
+type Category interface {
+	Name() string
+	Validate(a Activity) error
+	Estimate(a Activity) (*Estimate, error)
+
+	// EmissionSource subquery dengan kolom user_id, carbon_emission_g, logged_at
+	EmissionSource() string
+
+	Criteria() []models.MissionCriteriaType
+	// ReductionQuery total emisi untuk criteria tertentu di dalam window
+	ReductionQuery(criteria models.MissionCriteriaType, w Window) Query
+	// ProgressQuery progres misi custom untuk criteria tertentu (jarak, jam, kg, ...) di dalam window
+	ProgressQuery(criteria models.MissionCriteriaType, w Window) Query
+	// LogCountQuery jumlah log untuk criteria tertentu di dalam window
+	LogCountQuery(criteria models.MissionCriteriaType, w Window) Query
+	// CumulativeProgress true kalau ProgressQuery menjumlah seluruh riwayat,
+	// sehingga progres bisa ditambah langsung dari Quantity sebuah event
+	CumulativeProgress() bool
+}
+
+var (
+	registry   []Category
+	byName     = map[string]Category{}
+	byCriteria = map[models.MissionCriteriaType]Category{}
+)
+
+// Register mendaftarkan kategori. Nama dan criteria harus unik.
+func Register(c Category) {
+	if _, exists := byName[c.Name()]; exists {
+		panic(fmt.Sprintf("category %q already registered", c.Name()))
+	}
+	for _, criteria := range c.Criteria() {
+		if other, exists := byCriteria[criteria]; exists {
+			panic(fmt.Sprintf("criteria %q already handled by category %q", criteria, other.Name()))
+		}
+	}
+
+	registry = append(registry, c)
+	byName[c.Name()] = c
+	for _, criteria := range c.Criteria() {
+		byCriteria[criteria] = c
+	}
+}
+
+// All mengembalikan semua kategori sesuai urutan pendaftaran
+func All() []Category {
+	return registry
+}
+
+func Get(name string) (Category, bool) {
+	c, ok := byName[name]
+	return c, ok
+}
+
+// ForCriteria mencari kategori yang menangani criteria misi
+func ForCriteria(criteria models.MissionCriteriaType) (Category, bool) {
+	c, ok := byCriteria[criteria]
+	return c, ok
+}
+
+// Calculate memvalidasi lalu menghitung emisi aktivitas untuk kategori tertentu
+func Calculate(name string, a Activity) (*Estimate, error) {
+	c, ok := Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown carbon category: %s", name)
+	}
+	if err := c.Validate(a); err != nil {
+		return nil, err
+	}
+	return c.Estimate(a)
+}
+
+// EmissionUnion menggabungkan sumber emisi semua kategori (user_id, carbon_emission_g, logged_at)
+func EmissionUnion() string {
+	sources := make([]string, 0, len(registry))
+	for _, c := range registry {
+		sources = append(sources, c.EmissionSource())
+	}
+	return strings.Join(sources, "\nUNION ALL\n")
+}
@@ -0,0 +1,85 @@
+package category
+
+import (
+	"errors"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+const Electronics = "electronics"
+
+// Faktor emisi jaringan listrik PLN (kg CO2e per kWh)
+const GridEmissionFactor = 0.475
+
+type electronicsCategory struct{}
+
+func init() {
+	Register(electronicsCategory{})
+}
+
+func (electronicsCategory) Name() string { return Electronics }
+
+// Activity: Type = device_type, Quantity = energi (kWh)
+func (electronicsCategory) Validate(a Activity) error {
+	if a.Quantity < 0 {
+		return errors.New("energy usage must not be negative")
+	}
+	return nil
+}
+
+func (electronicsCategory) Estimate(a Activity) (*Estimate, error) {
+	return &Estimate{Quantity: a.Quantity, Emission: a.Quantity * GridEmissionFactor}, nil
+}
+
+func (electronicsCategory) EmissionSource() string {
+	return `SELECT ce.user_id, cel.carbon_emission_g, cel.logged_at
+		FROM carbon_electronics_logs cel
+		JOIN carbon_electronics ce ON cel.device_id = ce.id`
+}
+
+func (electronicsCategory) Criteria() []models.MissionCriteriaType {
+	return []models.MissionCriteriaType{
+		models.CriteriaLaptop, models.CriteriaDesktop, models.CriteriaTV,
+		models.CriteriaAC, models.CriteriaFridge, models.CriteriaFan,
+		models.CriteriaWashingMachine, models.CriteriaOther,
+	}
+}
+
+func (electronicsCategory) ReductionQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cel.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(carbon_emission_g), 0)
+			FROM carbon_electronics_logs cel
+			JOIN carbon_electronics ce ON cel.device_id = ce.id
+			WHERE ce.user_id = $1 AND ce.device_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
+
+// Progres misi custom: total jam penggunaan elektronik tertentu
+func (electronicsCategory) ProgressQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cel.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(duration_hours), 0)
+			FROM carbon_electronics_logs cel
+			JOIN carbon_electronics ce ON cel.device_id = ce.id
+			WHERE ce.user_id = $1 AND ce.device_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
+
+func (electronicsCategory) CumulativeProgress() bool { return true }
+
+func (electronicsCategory) LogCountQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cel.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COUNT(*)
+			FROM carbon_electronics_logs cel
+			JOIN carbon_electronics ce ON cel.device_id = ce.id
+			WHERE ce.user_id = $1 AND ce.device_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
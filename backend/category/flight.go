@@ -0,0 +1,109 @@
+package category
+
+import (
+	"errors"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+const Flight = "flight"
+
+// Tambahan jarak untuk rute yang tidak lurus dan holding (8%)
+const flightDistanceUplift = 1.08
+
+// Pengali kelas kabin terhadap economy
+var cabinClassMultipliers = map[models.CabinClass]float64{
+	models.CabinEconomy:        1.0,
+	models.CabinPremiumEconomy: 1.6,
+	models.CabinBusiness:       2.9,
+	models.CabinFirst:          4.0,
+}
+
+// flightHaulFactor faktor emisi per penumpang-km (kg CO2e) berdasarkan jarak tempuh satu arah
+func flightHaulFactor(distanceKm float64) float64 {
+	switch {
+	case distanceKm < 1500:
+		return 0.158 // short haul
+	case distanceKm < 4000:
+		return 0.137 // medium haul
+	default:
+		return 0.150 // long haul
+	}
+}
+
+type flightCategory struct{}
+
+func init() {
+	Register(flightCategory{})
+}
+
+func (flightCategory) Name() string { return Flight }
+
+// Activity: Type = cabin_class, Quantity = jarak great-circle satu arah (km),
+// Attrs["round_trip"] = "true" untuk pulang-pergi
+func (flightCategory) Validate(a Activity) error {
+	if a.Quantity <= 0 {
+		return errors.New("flight distance must be greater than 0")
+	}
+	if _, ok := cabinClassMultipliers[models.CabinClass(a.Type)]; !ok {
+		return errors.New("invalid cabin class")
+	}
+	return nil
+}
+
+func (flightCategory) Estimate(a Activity) (*Estimate, error) {
+	oneWayKm := a.Quantity * flightDistanceUplift
+	carbon := oneWayKm * flightHaulFactor(oneWayKm) * cabinClassMultipliers[models.CabinClass(a.Type)]
+
+	distance := oneWayKm
+	if a.Attrs["round_trip"] == "true" {
+		distance *= 2
+		carbon *= 2
+	}
+
+	return &Estimate{Quantity: distance, Emission: carbon}, nil
+}
+
+func (flightCategory) EmissionSource() string {
+	return `SELECT user_id, carbon_emission_g, logged_at FROM carbon_flight_logs`
+}
+
+func (flightCategory) Criteria() []models.MissionCriteriaType {
+	return []models.MissionCriteriaType{models.CriteriaFlight}
+}
+
+func (flightCategory) ReductionQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("logged_at", 2)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(carbon_emission_g), 0)
+			FROM carbon_flight_logs
+			WHERE user_id = $1` + cond,
+		Args: args,
+	}
+}
+
+// Progres misi custom: total jarak penerbangan
+func (flightCategory) ProgressQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("logged_at", 2)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(distance_km), 0)
+			FROM carbon_flight_logs
+			WHERE user_id = $1` + cond,
+		Args: args,
+	}
+}
+
+func (flightCategory) CumulativeProgress() bool { return true }
+
+func (flightCategory) LogCountQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("logged_at", 2)
+	return Query{
+		SQL: `
+			SELECT COUNT(*)
+			FROM carbon_flight_logs
+			WHERE user_id = $1` + cond,
+		Args: args,
+	}
+}
@@ -0,0 +1,96 @@
+package category
+
+import (
+	"errors"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+const Vehicle = "vehicle"
+
+// Faktor emisi kendaraan (kg CO2e per km) per jenis bahan bakar
+var vehicleFuelFactors = map[models.FuelType]float64{
+	models.FuelPetrol:   0.161,
+	models.FuelDiesel:   0.162,
+	models.FuelElectric: 0.095,
+	models.FuelNone:     0,
+}
+
+// Faktor untuk bahan bakar yang tidak dikenal
+const vehicleDefaultFactor = 0.16
+
+type vehicleCategory struct{}
+
+func init() {
+	Register(vehicleCategory{})
+}
+
+func (vehicleCategory) Name() string { return Vehicle }
+
+// Activity: Type = fuel_type, Quantity = jarak (km)
+func (vehicleCategory) Validate(a Activity) error {
+	if a.Quantity < 0 {
+		return errors.New("distance_km must not be negative")
+	}
+	return nil
+}
+
+func (vehicleCategory) Estimate(a Activity) (*Estimate, error) {
+	factor, ok := vehicleFuelFactors[models.FuelType(a.Type)]
+	if !ok {
+		factor = vehicleDefaultFactor
+	}
+	return &Estimate{Quantity: a.Quantity, Emission: a.Quantity * factor}, nil
+}
+
+func (vehicleCategory) EmissionSource() string {
+	return `SELECT cv.user_id, cvl.carbon_emission_g, cvl.logged_at
+		FROM carbon_vehicle_logs cvl
+		JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id`
+}
+
+func (vehicleCategory) Criteria() []models.MissionCriteriaType {
+	return []models.MissionCriteriaType{
+		models.CriteriaCar, models.CriteriaMotorcycle, models.CriteriaBicycle,
+		models.CriteriaPublicTransport, models.CriteriaWalk,
+	}
+}
+
+func (vehicleCategory) ReductionQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cvl.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(carbon_emission_g), 0)
+			FROM carbon_vehicle_logs cvl
+			JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
+			WHERE cv.user_id = $1 AND cv.vehicle_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
+
+// Progres misi custom: total jarak kendaraan tertentu
+func (vehicleCategory) ProgressQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cvl.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(distance_km), 0)
+			FROM carbon_vehicle_logs cvl
+			JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
+			WHERE cv.user_id = $1 AND cv.vehicle_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
+
+func (vehicleCategory) CumulativeProgress() bool { return true }
+
+func (vehicleCategory) LogCountQuery(criteria models.MissionCriteriaType, w Window) Query {
+	cond, args := w.Filter("cvl.logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COUNT(*)
+			FROM carbon_vehicle_logs cvl
+			JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
+			WHERE cv.user_id = $1 AND cv.vehicle_type = $2` + cond,
+		Args: append([]any{string(criteria)}, args...),
+	}
+}
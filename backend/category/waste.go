@@ -0,0 +1,136 @@
+package category
+
+import (
+	"errors"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+const Waste = "waste"
+
+// Faktor emisi (kg CO2e per kg sampah) untuk tiap kombinasi jenis sampah dan jalur pembuangan.
+// Kombinasi yang tidak ada di tabel dianggap tidak didukung.
+var wasteEmissionFactors = map[models.WasteStream]map[models.DisposalRoute]float64{
+	models.WasteOrganic: {
+		models.DisposalLandfill:  0.58,
+		models.DisposalComposted: 0.01,
+	},
+	models.WastePlastic: {
+		models.DisposalLandfill:   0.04,
+		models.DisposalRecycled:   0.21,
+		models.DisposalBankSampah: 0.21,
+	},
+	models.WastePaper: {
+		models.DisposalLandfill:   1.04,
+		models.DisposalRecycled:   0.21,
+		models.DisposalComposted:  0.01,
+		models.DisposalBankSampah: 0.21,
+	},
+	models.WasteResidual: {
+		models.DisposalLandfill: 0.45,
+	},
+}
+
+// Kredit daur ulang (kg CO2e per kg) karena menggantikan bahan baku baru
+var wasteRecyclingCredits = map[models.WasteStream]float64{
+	models.WastePlastic: 1.02,
+	models.WastePaper:   0.68,
+}
+
+type wasteCategory struct{}
+
+func init() {
+	Register(wasteCategory{})
+}
+
+func (wasteCategory) Name() string { return Waste }
+
+// Activity: Type = waste_stream, Quantity = berat (kg), Attrs["disposal_route"]
+func (wasteCategory) Validate(a Activity) error {
+	if a.Quantity <= 0 {
+		return errors.New("weight_kg must be greater than 0")
+	}
+	routes, ok := wasteEmissionFactors[models.WasteStream(a.Type)]
+	if !ok {
+		return errors.New("invalid waste stream")
+	}
+	if _, ok := routes[models.DisposalRoute(a.Attrs["disposal_route"])]; !ok {
+		return errors.New("disposal route not supported for this waste stream")
+	}
+	return nil
+}
+
+// Estimate mengembalikan emisi dan emisi yang dihindari dibanding dibuang ke TPA
+func (wasteCategory) Estimate(a Activity) (*Estimate, error) {
+	stream := models.WasteStream(a.Type)
+	route := models.DisposalRoute(a.Attrs["disposal_route"])
+	routes := wasteEmissionFactors[stream]
+	factor := routes[route]
+
+	est := &Estimate{Quantity: a.Quantity, Emission: a.Quantity * factor}
+	if !route.IsDiverted() {
+		return est, nil
+	}
+
+	avoided := a.Quantity * (routes[models.DisposalLandfill] - factor)
+	if route == models.DisposalRecycled || route == models.DisposalBankSampah {
+		avoided += a.Quantity * wasteRecyclingCredits[stream]
+	}
+	if avoided > 0 {
+		est.Avoided = avoided
+	}
+
+	return est, nil
+}
+
+func (wasteCategory) EmissionSource() string {
+	return `SELECT user_id, carbon_emission_g, logged_at FROM carbon_waste_logs`
+}
+
+func (wasteCategory) Criteria() []models.MissionCriteriaType {
+	return []models.MissionCriteriaType{
+		models.CriteriaWasteOrganic, models.CriteriaWastePlastic,
+		models.CriteriaWastePaper, models.CriteriaWasteResidual,
+	}
+}
+
+func (wasteCategory) ReductionQuery(criteria models.MissionCriteriaType, w Window) Query {
+	stream, _ := criteria.WasteStream()
+	cond, args := w.Filter("logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(carbon_emission_g), 0)
+			FROM carbon_waste_logs
+			WHERE user_id = $1 AND waste_stream = $2` + cond,
+		Args: append([]any{string(stream)}, args...),
+	}
+}
+
+// Progres misi custom: kg sampah yang tidak dibuang ke TPA di dalam window
+func (wasteCategory) ProgressQuery(criteria models.MissionCriteriaType, w Window) Query {
+	stream, _ := criteria.WasteStream()
+	cond, args := w.Filter("logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COALESCE(SUM(weight_kg), 0)
+			FROM carbon_waste_logs
+			WHERE user_id = $1 AND waste_stream = $2
+			  AND disposal_route IN ('recycled', 'composted', 'bank_sampah')` + cond,
+		Args: append([]any{string(stream)}, args...),
+	}
+}
+
+// Log yang dibuang ke TPA tidak menambah progres, jadi harus dihitung ulang
+func (wasteCategory) CumulativeProgress() bool { return false }
+
+func (wasteCategory) LogCountQuery(criteria models.MissionCriteriaType, w Window) Query {
+	stream, _ := criteria.WasteStream()
+	cond, args := w.Filter("logged_at", 3)
+	return Query{
+		SQL: `
+			SELECT COUNT(*)
+			FROM carbon_waste_logs
+			WHERE user_id = $1 AND waste_stream = $2` + cond,
+		Args: append([]any{string(stream)}, args...),
+	}
+}
@@ -18,7 +18,7 @@ func InitBadgeController(app *fiber.App, svc service.BadgeService, mw *middlewar
 	ctrl := &BadgeController{badgeService: svc}
 
 	public := app.Group("/api/badges")
-	private := public.Group("/", mw.JWT)
+	private := public.Group("/", mw.JWT, mw.Locale)
 
 	private.Get("/", ctrl.GetUserBadges)
 }
@@ -39,7 +39,20 @@ func InitCarbonController(app *fiber.App, svc service.CarbonServiceInterface, mw
 	public.Post("/electronics-log", ctrl.AddElectronicsLog)
 	public.Get("/electronic/:id/logs", ctrl.GetElectronicsLogs)
 	public.Get("/electronics/logs", ctrl.GetAllElectronicLogs)
-	
+
+	public.Post("/waste-log", ctrl.AddWasteLog)
+	public.Get("/waste/logs", ctrl.GetAllWasteLogs)
+	public.Get("/waste/summary", ctrl.GetWasteSummary)
+
+	public.Post("/electricity/reading", ctrl.AddMeterReading)
+	public.Get("/electricity/summary", ctrl.GetElectricitySummary)
+
+	public.Post("/flight-log", ctrl.AddFlightLog)
+	public.Get("/flight/logs", ctrl.GetAllFlightLogs)
+
+	public.Post("/onboarding", ctrl.SubmitQuestionnaire)
+	public.Get("/baseline", ctrl.GetBaseline)
+
 }
 
 func (c *CarbonController) CreateVehicle(ctx *fiber.Ctx) error {
@@ -291,3 +304,155 @@ func (c *CarbonController) GetAllElectronicLogs(ctx *fiber.Ctx) error {
 
 	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "all electronic logs retrieved successfully", logs))
 }
+
+func (c *CarbonController) AddWasteLog(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	req := new(dto.AddWasteLogDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	log, err := c.carbonService.AddWasteLog(ctx.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLoggedAt) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "waste log berhasil ditambahkan", log))
+}
+
+func (c *CarbonController) GetAllWasteLogs(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	logs, err := c.carbonService.GetAllWasteLogs(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "all waste logs retrieved successfully", logs))
+}
+
+func (c *CarbonController) GetWasteSummary(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	summary, err := c.carbonService.GetWasteSummary(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "waste summary retrieved successfully", summary))
+}
+
+func (c *CarbonController) AddMeterReading(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	req := new(dto.AddMeterReadingDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	reading, err := c.carbonService.AddMeterReading(ctx.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReadAt) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "meter reading berhasil ditambahkan", reading))
+}
+
+func (c *CarbonController) GetElectricitySummary(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	summary, err := c.carbonService.GetElectricitySummary(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "electricity summary retrieved successfully", summary))
+}
+
+func (c *CarbonController) AddFlightLog(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	req := new(dto.AddFlightLogDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	log, err := c.carbonService.AddFlightLog(ctx.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLoggedAt) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "flight log berhasil ditambahkan", log))
+}
+
+func (c *CarbonController) GetAllFlightLogs(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	logs, err := c.carbonService.GetAllFlightLogs(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "all flight logs retrieved successfully", logs))
+}
+
+func (c *CarbonController) SubmitQuestionnaire(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	req := new(dto.OnboardingQuestionnaireDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	baseline, err := c.carbonService.SubmitQuestionnaire(ctx.Context(), userID, req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "baseline jejak karbon berhasil disimpan", baseline))
+}
+
+func (c *CarbonController) GetBaseline(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	userID, _ := strconv.ParseInt(claims.UserID, 10, 64)
+
+	baseline, err := c.carbonService.GetBaseline(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+	if baseline == nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(helpers.BasicResponse(false, "baseline not found, please complete the onboarding questionnaire"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "baseline retrieved successfully", baseline))
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	"github.com/Qodarrz/fiber-app/middleware"
+	"github.com/Qodarrz/fiber-app/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CheckInController struct {
+	checkInService service.CheckInServiceInterface
+}
+
+func InitCheckInController(app *fiber.App, svc service.CheckInServiceInterface, mw *middleware.Middlewares) {
+	ctrl := &CheckInController{checkInService: svc}
+
+	private := app.Group("/api/user/checkin", mw.JWT)
+	private.Get("/", ctrl.GetStatus)
+	private.Post("/", ctrl.CheckIn)
+}
+
+func (c *CheckInController) GetStatus(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(helpers.BasicResponse(false, "token tidak valid"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "user ID tidak valid"))
+	}
+
+	status, err := c.checkInService.GetStatus(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, "gagal mengambil status check-in"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "status check-in ditemukan", status))
+}
+
+func (c *CheckInController) CheckIn(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(helpers.BasicResponse(false, "token tidak valid"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "user ID tidak valid"))
+	}
+
+	result, err := c.checkInService.CheckIn(ctx.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrAlreadyCheckedIn) {
+			return ctx.Status(fiber.StatusConflict).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, "gagal check-in"))
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "check-in berhasil", result))
+}
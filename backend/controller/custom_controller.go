@@ -29,6 +29,7 @@ func InitUserCustomEndpointController(app *fiber.App, svc service.UserCustomEndp
 	private.Get("/user-data/:id", ctrl.GetUserCustomData)
 	private.Get("/my-data", ctrl.GetMyCustomData)
 	private.Get("/mission-progress", ctrl.GetMissionProgress)
+	private.Post("/mission-progress/recompute", ctrl.RecomputeMissionProgress)
 	private.Get("/notifications", ctrl.GetNotifications)
 }
 
@@ -133,3 +134,27 @@ func (c *UserCustomEndpointController) GetLeaderboard(ctx *fiber.Ctx) error {
 
 	return ctx.Status(http.StatusOK).JSON(helper.SuccessResponseWithData(true, "Leaderboard retrieved successfully", leaderboard))
 }
+
+// RecomputeMissionProgress menghitung ulang seluruh progres misi dari data mentah
+func (c *UserCustomEndpointController) RecomputeMissionProgress(ctx *fiber.Ctx) error {
+	claims := helper.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helper.BasicResponse(false, "Unauthorized"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helper.BasicResponse(false, "Invalid user ID"))
+	}
+
+	if err := c.userCustomService.RecomputeMissionProgress(ctx.Context(), userID); err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helper.BasicResponse(false, err.Error()))
+	}
+
+	progressList, err := c.userCustomService.GetAllMissionProgress(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helper.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helper.SuccessResponseWithData(true, "Mission progress recomputed successfully", progressList))
+}
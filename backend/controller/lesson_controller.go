@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	"github.com/Qodarrz/fiber-app/middleware"
+	"github.com/Qodarrz/fiber-app/repository"
+	"github.com/Qodarrz/fiber-app/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LessonController struct {
+	lessonService service.LessonServiceInterface
+}
+
+func InitLessonController(app *fiber.App, svc service.LessonServiceInterface, mw *middleware.Middlewares) {
+	ctrl := &LessonController{lessonService: svc}
+
+	public := app.Group("/api/lessons")
+	public.Get("/", ctrl.GetLessons)
+
+	private := app.Group("/api/lessons", mw.JWT)
+	private.Get("/:id<int>", ctrl.GetLesson)
+	private.Post("/:id<int>/attempts", ctrl.SubmitQuiz)
+	private.Get("/:id<int>/attempts", ctrl.GetMyAttempts)
+
+	// Admin routes
+	private.Get("/admin", mw.Admin, ctrl.GetAllLessons)
+	private.Get("/admin/:id<int>", mw.Admin, ctrl.GetLessonForAdmin)
+	private.Post("/", mw.Admin, ctrl.CreateLesson)
+	private.Put("/:id<int>", mw.Admin, ctrl.UpdateLesson)
+	private.Delete("/:id<int>", mw.Admin, ctrl.DeleteLesson)
+}
+
+func lessonUserID(ctx *fiber.Ctx) (int64, error) {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return 0, errors.New("Invalid token")
+	}
+	return strconv.ParseInt(claims.UserID, 10, 64)
+}
+
+// GetLessons daftar lesson yang sudah dipublikasikan
+func (c *LessonController) GetLessons(ctx *fiber.Ctx) error {
+	return c.listLessons(ctx, false)
+}
+
+// GetAllLessons termasuk draft
+func (c *LessonController) GetAllLessons(ctx *fiber.Ctx) error {
+	return c.listLessons(ctx, true)
+}
+
+func (c *LessonController) listLessons(ctx *fiber.Ctx, includeDrafts bool) error {
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	limit, _ := strconv.Atoi(ctx.Query("limit", "10"))
+	if limit > 100 {
+		limit = 100
+	}
+
+	lessons, err := c.lessonService.GetLessons(ctx.Context(), includeDrafts, page, limit)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Lessons retrieved successfully", lessons))
+}
+
+func (c *LessonController) GetLesson(ctx *fiber.Ctx) error {
+	userID, err := lessonUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	lesson, err := c.lessonService.GetLesson(ctx.Context(), userID, lessonID)
+	if err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Lesson retrieved successfully", lesson))
+}
+
+// SubmitQuiz body: {"answers": [{"question_id": 1, "option": 0}, ...]}
+func (c *LessonController) SubmitQuiz(ctx *fiber.Ctx) error {
+	userID, err := lessonUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	req := new(dto.SubmitQuizDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	result, err := c.lessonService.SubmitQuiz(ctx.Context(), userID, lessonID, req)
+	if err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	message := "Quiz not passed"
+	if result.Passed {
+		message = "Quiz passed"
+	}
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, message, result))
+}
+
+func (c *LessonController) GetMyAttempts(ctx *fiber.Ctx) error {
+	userID, err := lessonUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	attempts, err := c.lessonService.GetMyAttempts(ctx.Context(), userID, lessonID)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Quiz attempts retrieved successfully", attempts))
+}
+
+func (c *LessonController) GetLessonForAdmin(ctx *fiber.Ctx) error {
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	lesson, err := c.lessonService.GetLessonForAdmin(ctx.Context(), lessonID)
+	if err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Lesson retrieved successfully", lesson))
+}
+
+func (c *LessonController) CreateLesson(ctx *fiber.Ctx) error {
+	req := new(dto.LessonRequestDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	lesson, err := c.lessonService.CreateLesson(ctx.Context(), req)
+	if err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "Lesson created successfully", lesson))
+}
+
+func (c *LessonController) UpdateLesson(ctx *fiber.Ctx) error {
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	req := new(dto.LessonRequestDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	lesson, err := c.lessonService.UpdateLesson(ctx.Context(), lessonID, req)
+	if err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Lesson updated successfully", lesson))
+}
+
+func (c *LessonController) DeleteLesson(ctx *fiber.Ctx) error {
+	lessonID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid lesson ID"))
+	}
+
+	if err := c.lessonService.DeleteLesson(ctx.Context(), lessonID); err != nil {
+		return ctx.Status(lessonErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Lesson deleted successfully"))
+}
+
+func lessonErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrLessonNotFound), errors.Is(err, service.ErrMissionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrQuizAlreadyPassed), errors.Is(err, repository.ErrQuizAttemptLimit):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrQuizCooldown):
+		return http.StatusTooManyRequests
+	case errors.Is(err, service.ErrLessonMissionNotQuiz), errors.Is(err, service.ErrInvalidQuizQuestion),
+		errors.Is(err, service.ErrInvalidQuizAnswers):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
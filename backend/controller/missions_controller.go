@@ -2,15 +2,21 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	dto "github.com/Qodarrz/fiber-app/dto"
 	helpers "github.com/Qodarrz/fiber-app/helper"
 	"github.com/Qodarrz/fiber-app/middleware"
+	"github.com/Qodarrz/fiber-app/repository"
+	"github.com/Qodarrz/fiber-app/rule"
 	service "github.com/Qodarrz/fiber-app/service"
 	"github.com/gofiber/fiber/v2"
 )
@@ -24,16 +30,40 @@ func InitMissionController(app *fiber.App, svc service.MissionServiceInterface,
 
 	// Public routes
 	public := app.Group("/api/missions")
-	public.Get("/", ctrl.GetAllMissions)
-	public.Get("/active", ctrl.GetActiveMissions)
-	public.Get("/:id", ctrl.GetMissionByID)
+	public.Get("/", mw.Locale, ctrl.GetAllMissions)
+	public.Get("/active", mw.OptionalJWT, mw.Locale, ctrl.GetActiveMissions)
+	public.Get("/rules/metrics", ctrl.GetRuleMetrics)
+	public.Get("/:id<int>", mw.Locale, ctrl.GetMissionByID)
 
 	// Private routes (require authentication)
-	private := app.Group("/api/missions", mw.JWT)
-	private.Post("/", ctrl.CreateMission)
+	private := app.Group("/api/missions", mw.JWT, mw.Locale)
+	private.Post("/", mw.Admin, ctrl.CreateMission)
 	private.Get("/my-missions", ctrl.GetUserMissions)
-	private.Post("/with-badge", ctrl.CreateMissionWithBadge)
+	private.Post("/with-badge", mw.Admin, ctrl.CreateMissionWithBadge)
 	private.Get("/:id/check-completion", ctrl.CheckMissionCompletion)
+	private.Get("/available", ctrl.GetAvailableMissions)
+	private.Get("/recommended", ctrl.GetRecommendedMissions)
+	private.Post("/:id/join", ctrl.JoinMission)
+	private.Post("/:id/leave", ctrl.LeaveMission)
+	private.Get("/:id/collective", ctrl.GetCollectiveProgress)
+	private.Get("/:id<int>/timeline", ctrl.GetProgressTimeline)
+	private.Post("/:id<int>/proofs", ctrl.SubmitProof)
+	private.Get("/:id<int>/proofs", ctrl.GetMyProofs)
+
+	// Admin routes
+	private.Get("/archived", mw.Admin, ctrl.GetArchivedMissions)
+	private.Post("/dry-run", mw.Admin, ctrl.DryRunMission)
+	private.Post("/import", mw.Admin, ctrl.ImportMissions)
+	private.Get("/jobs", mw.Admin, ctrl.GetJobRuns)
+	private.Put("/:id<int>", mw.Admin, ctrl.UpdateMission)
+	private.Delete("/:id<int>", mw.Admin, ctrl.DeleteMission)
+	private.Post("/:id<int>/archive", mw.Admin, ctrl.ArchiveMission)
+	private.Post("/:id<int>/restore", mw.Admin, ctrl.RestoreMission)
+	private.Post("/:id<int>/duplicate", mw.Admin, ctrl.DuplicateMission)
+	private.Get("/:id<int>/users/:userId<int>/timeline", mw.Admin, ctrl.GetUserProgressTimeline)
+	private.Get("/proofs", mw.Admin, ctrl.GetProofQueue)
+	private.Post("/proofs/:proofId<int>/approve", mw.Admin, ctrl.ApproveProof)
+	private.Post("/proofs/:proofId<int>/reject", mw.Admin, ctrl.RejectProof)
 }
 
 func (c *MissionController) CreateMission(ctx *fiber.Ctx) error {
@@ -80,7 +110,15 @@ func (c *MissionController) GetAllMissions(ctx *fiber.Ctx) error {
 }
 
 func (c *MissionController) GetActiveMissions(ctx *fiber.Ctx) error {
-	missions, err := c.missionService.GetActiveMissions(ctx.Context())
+	// Token opsional: kalau ada, misi yang masih terkunci ditandai untuk user ini
+	var userID *int64
+	if claims := helpers.GetUserClaims(ctx); claims != nil {
+		if id, err := strconv.ParseInt(claims.UserID, 10, 64); err == nil {
+			userID = &id
+		}
+	}
+
+	missions, err := c.missionService.GetActiveMissions(ctx.Context(), userID)
 	if err != nil {
 		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
 	}
@@ -179,3 +217,526 @@ func (c *MissionController) CheckMissionCompletion(ctx *fiber.Ctx) error {
 		"completed": completed,
 	}))
 }
+
+
+func (c *MissionController) GetAvailableMissions(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missions, err := c.missionService.GetAvailableMissions(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Available missions retrieved successfully", missions))
+}
+
+func (c *MissionController) JoinMission(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	userMission, err := c.missionService.JoinMission(ctx.Context(), userID, missionID)
+	if err != nil {
+		return ctx.Status(missionStateErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "Mission joined successfully", userMission))
+}
+
+func (c *MissionController) LeaveMission(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	if err := c.missionService.LeaveMission(ctx.Context(), userID, missionID); err != nil {
+		return ctx.Status(missionStateErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Mission left successfully"))
+}
+
+func (c *MissionController) GetCollectiveProgress(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	progress, err := c.missionService.GetCollectiveProgress(ctx.Context(), userID, missionID)
+	if err != nil {
+		return ctx.Status(missionStateErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Collective progress retrieved successfully", progress))
+}
+
+// GetRecommendedMissions misi aktif yang diurutkan untuk user; ?limit= (default 5)
+func (c *MissionController) GetRecommendedMissions(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit", "5"))
+
+	missions, err := c.missionService.GetRecommendedMissions(ctx.Context(), userID, limit)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Recommended missions retrieved successfully", missions))
+}
+
+// GetProgressTimeline riwayat progres user sendiri; ?limit= membatasi jumlah entri
+func (c *MissionController) GetProgressTimeline(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	return c.progressTimeline(ctx, userID)
+}
+
+// GetUserProgressTimeline riwayat progres user lain untuk admin/support
+func (c *MissionController) GetUserProgressTimeline(ctx *fiber.Ctx) error {
+	userID, err := strconv.ParseInt(ctx.Params("userId"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	return c.progressTimeline(ctx, userID)
+}
+
+func (c *MissionController) progressTimeline(ctx *fiber.Ctx, userID int64) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+	limit, _ := strconv.Atoi(ctx.Query("limit", "50"))
+
+	timeline, err := c.missionService.GetProgressTimeline(ctx.Context(), userID, missionID, limit)
+	if err != nil {
+		return ctx.Status(missionStateErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Progress timeline retrieved successfully", timeline))
+}
+
+func (c *MissionController) UpdateMission(ctx *fiber.Ctx) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	req := new(dto.UpdateMissionDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	res, err := c.missionService.UpdateMission(ctx.Context(), missionID, req)
+	if err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Mission updated successfully", res))
+}
+
+func (c *MissionController) ArchiveMission(ctx *fiber.Ctx) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	if err := c.missionService.ArchiveMission(ctx.Context(), missionID); err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Mission archived successfully"))
+}
+
+func (c *MissionController) RestoreMission(ctx *fiber.Ctx) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	if err := c.missionService.RestoreMission(ctx.Context(), missionID); err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Mission restored successfully"))
+}
+
+func (c *MissionController) DeleteMission(ctx *fiber.Ctx) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	if err := c.missionService.DeleteMission(ctx.Context(), missionID); err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Mission deleted successfully"))
+}
+
+func (c *MissionController) DuplicateMission(ctx *fiber.Ctx) error {
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	req := new(dto.DuplicateMissionDTO)
+	if len(ctx.Body()) > 0 {
+		if err := helpers.BindAndValidate(ctx, req); err != nil {
+			if vErr, ok := err.(*helpers.ValidationError); ok {
+				return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+			}
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+	}
+
+	mission, err := c.missionService.DuplicateMission(ctx.Context(), missionID, req)
+	if err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "Mission duplicated successfully", mission))
+}
+
+func (c *MissionController) GetArchivedMissions(ctx *fiber.Ctx) error {
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	limit, _ := strconv.Atoi(ctx.Query("limit", "10"))
+
+	missions, err := c.missionService.GetArchivedMissions(ctx.Context(), page, limit)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Archived missions retrieved successfully", missions))
+}
+
+// maxProofPhotoSize batas ukuran foto bukti misi
+const maxProofPhotoSize = 5 * 1024 * 1024
+
+// SubmitProof multipart: photo (wajib) dan note
+func (c *MissionController) SubmitProof(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	note := ctx.FormValue("note")
+	if len(note) > 500 {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "note must be at most 500 characters"))
+	}
+
+	fileHeader, err := ctx.FormFile("photo")
+	if err != nil || fileHeader == nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "photo is required"))
+	}
+	if fileHeader.Size > maxProofPhotoSize {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "photo is too large"))
+	}
+
+	allowedTypes := map[string]bool{
+		"image/jpeg": true,
+		"image/jpg":  true,
+		"image/png":  true,
+		"image/webp": true,
+	}
+	if !allowedTypes[fileHeader.Header.Get("Content-Type")] {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "unsupported photo type"))
+	}
+
+	uniqueFilename := fmt.Sprintf("%d_proof_%d_%d%s", time.Now().UnixNano(), missionID, userID, filepath.Ext(fileHeader.Filename))
+	tempPath := filepath.Join(os.TempDir(), uniqueFilename)
+	if err := ctx.SaveFile(fileHeader, tempPath); err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, "failed to save photo"))
+	}
+	defer os.Remove(tempPath)
+
+	photoURL, err := helpers.UploadFile(tempPath)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, "failed to upload photo"))
+	}
+
+	proof, err := c.missionService.SubmitProof(ctx.Context(), userID, missionID, photoURL, note)
+	if err != nil {
+		return ctx.Status(missionProofErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "Proof submitted for review", proof))
+}
+
+func (c *MissionController) GetMyProofs(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	missionID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid mission ID"))
+	}
+
+	proofs, err := c.missionService.GetMyProofs(ctx.Context(), userID, missionID)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Proofs retrieved successfully", proofs))
+}
+
+// GetProofQueue antrean moderasi; ?status=pending|approved|rejected|all
+func (c *MissionController) GetProofQueue(ctx *fiber.Ctx) error {
+	page, _ := strconv.Atoi(ctx.Query("page", "1"))
+	limit, _ := strconv.Atoi(ctx.Query("limit", "10"))
+
+	proofs, err := c.missionService.GetProofQueue(ctx.Context(), ctx.Query("status"), page, limit)
+	if err != nil {
+		return ctx.Status(missionProofErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Proof queue retrieved successfully", proofs))
+}
+
+func (c *MissionController) ApproveProof(ctx *fiber.Ctx) error {
+	return c.reviewProof(ctx, true)
+}
+
+func (c *MissionController) RejectProof(ctx *fiber.Ctx) error {
+	return c.reviewProof(ctx, false)
+}
+
+func (c *MissionController) reviewProof(ctx *fiber.Ctx, approve bool) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	reviewerID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid user ID"))
+	}
+
+	proofID, err := strconv.ParseInt(ctx.Params("proofId"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid proof ID"))
+	}
+
+	req := new(dto.ReviewProofDTO)
+	if len(ctx.Body()) > 0 {
+		if err := helpers.BindAndValidate(ctx, req); err != nil {
+			if vErr, ok := err.(*helpers.ValidationError); ok {
+				return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+			}
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+	}
+
+	res, err := c.missionService.ReviewProof(ctx.Context(), reviewerID, proofID, approve, req)
+	if err != nil {
+		return ctx.Status(missionProofErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	message := "Proof rejected"
+	if approve {
+		message = "Proof approved"
+	}
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, message, res))
+}
+
+func missionProofErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrProofPending), errors.Is(err, repository.ErrProofAlreadyCompleted),
+		errors.Is(err, repository.ErrProofAlreadyReviewed):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrProofNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrMissionNotProof), errors.Is(err, service.ErrInvalidProofStatus),
+		errors.Is(err, service.ErrRejectNoteRequired):
+		return http.StatusBadRequest
+	}
+	return missionStateErrorStatus(err)
+}
+
+// DryRunMission evaluasi misi draft ke semua user (atau sample_size user) tanpa menyimpan apa pun
+func (c *MissionController) DryRunMission(ctx *fiber.Ctx) error {
+	req := new(dto.DryRunMissionDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	res, err := c.missionService.DryRunMission(ctx.Context(), req)
+	if err != nil {
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Mission dry run completed", res))
+}
+
+// maxImportFileSize batas ukuran file import misi
+const maxImportFileSize = 2 * 1024 * 1024
+
+// ImportMissions multipart: file (YAML/CSV), format (opsional, default dari ekstensi file)
+// dan ?dry_run=true untuk melihat diff tanpa menyimpan
+func (c *MissionController) ImportMissions(ctx *fiber.Ctx) error {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil || fileHeader == nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "file is required"))
+	}
+	if fileHeader.Size > maxImportFileSize {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "file is too large"))
+	}
+
+	format := ctx.FormValue("format")
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(fileHeader.Filename), ".")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "failed to read file"))
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "failed to read file"))
+	}
+
+	dryRun := ctx.QueryBool("dry_run")
+	res, err := c.missionService.ImportMissions(ctx.Context(), format, data, dryRun)
+	if err != nil {
+		var importErr *service.MissionImportError
+		if errors.As(err, &importErr) {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, err.Error(), importErr.Errors))
+		}
+		return ctx.Status(missionAdminErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	message := "Missions imported successfully"
+	if dryRun {
+		message = "Mission import dry run completed"
+	}
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, message, res))
+}
+
+// GetJobRuns riwayat run scheduler misi; ?job=<nama job>&limit=50
+func (c *MissionController) GetJobRuns(ctx *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(ctx.Query("limit", "50"))
+
+	runs, err := c.missionService.GetJobRuns(ctx.Context(), ctx.Query("job"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownSchedulerJob) {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Scheduler job runs retrieved successfully", runs))
+}
+
+// missionAdminErrorStatus validasi input admin dianggap 400
+func missionAdminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrMissionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrMissionInUse), errors.Is(err, repository.ErrPrerequisiteCycle):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// GetRuleMetrics daftar metric, filter dan agregasi untuk menyusun aturan misi
+func (c *MissionController) GetRuleMetrics(ctx *fiber.Ctx) error {
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Rule metrics retrieved successfully", rule.Catalog()))
+}
+
+// missionStateErrorStatus memetakan error state machine misi ke status HTTP
+func missionStateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrMissionAlreadyJoined), errors.Is(err, repository.ErrActiveMissionLimit):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrMissionNotJoined), errors.Is(err, repository.ErrMissionClosed):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrMissionLocked):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrMissionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrMissionNotCollective):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -22,10 +22,10 @@ func InitStoreController(app *fiber.App, svc service.StoreServiceInterface, mw *
 	ctrl := &StoreController{storeService: svc}
 
 	public := app.Group("/api/store")
-	public.Get("/items", ctrl.GetAllStoreItems)
-	public.Get("/items/:id", ctrl.GetStoreItemByID)
+	public.Get("/items", mw.Locale, ctrl.GetAllStoreItems)
+	public.Get("/items/:id", mw.Locale, ctrl.GetStoreItemByID)
 
-	private := app.Group("/api/store", mw.JWT)
+	private := app.Group("/api/store", mw.JWT, mw.Locale)
 	private.Post("/items", ctrl.CreateStoreItem)
 	private.Put("/items/:id", ctrl.UpdateStoreItem)
 	private.Delete("/items/:id", ctrl.DeleteStoreItem)
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	"github.com/Qodarrz/fiber-app/middleware"
+	"github.com/Qodarrz/fiber-app/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TeamController struct {
+	teamService service.TeamServiceInterface
+}
+
+func InitTeamController(app *fiber.App, svc service.TeamServiceInterface, mw *middleware.Middlewares) {
+	ctrl := &TeamController{teamService: svc}
+
+	private := app.Group("/api/teams", mw.JWT)
+	private.Post("/", ctrl.CreateTeam)
+	private.Get("/mine", ctrl.GetMyTeams)
+	private.Post("/join", ctrl.JoinTeam)
+	private.Post("/:id<int>/leave", ctrl.LeaveTeam)
+}
+
+func teamUserID(ctx *fiber.Ctx) (int64, error) {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return 0, errors.New("Invalid token")
+	}
+	return strconv.ParseInt(claims.UserID, 10, 64)
+}
+
+func (c *TeamController) CreateTeam(ctx *fiber.Ctx) error {
+	userID, err := teamUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	req := new(dto.CreateTeamDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	team, err := c.teamService.CreateTeam(ctx.Context(), userID, req)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(helpers.SuccessResponseWithData(true, "Team created successfully", team))
+}
+
+func (c *TeamController) JoinTeam(ctx *fiber.Ctx) error {
+	userID, err := teamUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	req := new(dto.JoinTeamDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	team, err := c.teamService.JoinTeam(ctx.Context(), userID, req)
+	if err != nil {
+		return ctx.Status(teamErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Team joined successfully", team))
+}
+
+func (c *TeamController) LeaveTeam(ctx *fiber.Ctx) error {
+	userID, err := teamUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	teamID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, "Invalid team ID"))
+	}
+
+	if err := c.teamService.LeaveTeam(ctx.Context(), userID, teamID); err != nil {
+		return ctx.Status(teamErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Team left successfully"))
+}
+
+func (c *TeamController) GetMyTeams(ctx *fiber.Ctx) error {
+	userID, err := teamUserID(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusUnauthorized).JSON(helpers.BasicResponse(false, "Invalid token"))
+	}
+
+	teams, err := c.teamService.GetMyTeams(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Teams retrieved successfully", teams))
+}
+
+func teamErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTeamNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTeamAlreadyMember):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTeamNotMember):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	"github.com/Qodarrz/fiber-app/middleware"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+	"github.com/Qodarrz/fiber-app/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TranslationController struct {
+	translationService service.TranslationServiceInterface
+}
+
+// InitTranslationController endpoint admin untuk mengelola terjemahan konten;
+// :entity salah satu dari missions, badges atau store-items
+func InitTranslationController(app *fiber.App, svc service.TranslationServiceInterface, mw *middleware.Middlewares) {
+	ctrl := &TranslationController{translationService: svc}
+
+	private := app.Group("/api/translations", mw.JWT, mw.Admin)
+	private.Get("/:entity/:id<int>", ctrl.GetTranslations)
+	private.Put("/:entity/:id<int>/:locale", ctrl.UpsertTranslation)
+	private.Delete("/:entity/:id<int>/:locale", ctrl.DeleteTranslation)
+}
+
+func translationTarget(ctx *fiber.Ctx) (model.TranslationEntity, int64, error) {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return "", 0, errors.New("Invalid content ID")
+	}
+	return model.TranslationEntity(ctx.Params("entity")), id, nil
+}
+
+func (c *TranslationController) GetTranslations(ctx *fiber.Ctx) error {
+	entity, id, err := translationTarget(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	translations, err := c.translationService.GetTranslations(ctx.Context(), entity, id)
+	if err != nil {
+		return ctx.Status(translationErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Translations retrieved successfully", translations))
+}
+
+func (c *TranslationController) UpsertTranslation(ctx *fiber.Ctx) error {
+	entity, id, err := translationTarget(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	req := new(dto.UpsertTranslationDTO)
+	if err := helpers.BindAndValidate(ctx, req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	translation, err := c.translationService.UpsertTranslation(ctx.Context(), entity, id, ctx.Params("locale"), req)
+	if err != nil {
+		return ctx.Status(translationErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.SuccessResponseWithData(true, "Translation saved successfully", translation))
+}
+
+func (c *TranslationController) DeleteTranslation(ctx *fiber.Ctx) error {
+	entity, id, err := translationTarget(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	if err := c.translationService.DeleteTranslation(ctx.Context(), entity, id, ctx.Params("locale")); err != nil {
+		return ctx.Status(translationErrorStatus(err)).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(helpers.BasicResponse(true, "Translation deleted successfully"))
+}
+
+func translationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrContentNotFound), errors.Is(err, service.ErrTranslationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrUnknownTranslationEntity), errors.Is(err, service.ErrUnsupportedLocale):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -26,6 +26,8 @@ func InitUserProfileController(app *fiber.App, svc service.UserProfileServiceInt
 	private := app.Group("/api/user", mw.JWT)
 	private.Get("/profile", ctrl.GetProfile)
 	private.Patch("/profile", ctrl.UpdateProfile)
+	private.Get("/streak", ctrl.GetStreak)
+	private.Put("/streak/timezone", ctrl.UpdateStreakTimezone)
 }
 
 func (c *UserProfileController) GetProfile(ctx *fiber.Ctx) error {
@@ -67,6 +69,13 @@ func (c *UserProfileController) UpdateProfile(ctx *fiber.Ctx) error {
 	if v := ctx.FormValue("gender"); v != "" {
 		req.Gender = &v
 	}
+	if v := ctx.FormValue("language"); v != "" {
+		locale, ok := helpers.NormalizeLocale(v)
+		if !ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "bahasa tidak didukung, gunakan id atau en"))
+		}
+		req.Language = &locale
+	}
 	if v := ctx.FormValue("birthdate"); v != "" {
 		t, parseErr := time.Parse("2006-01-02", v)
 		if parseErr != nil {
@@ -116,7 +125,7 @@ func (c *UserProfileController) UpdateProfile(ctx *fiber.Ctx) error {
 	}
 
 	// Validasi minimal ada satu field yang diupdate (TANPA username)
-	if req.FullName == nil && req.Gender == nil && req.Birthdate == nil && req.AvatarURL == nil {
+	if req.FullName == nil && req.Gender == nil && req.Birthdate == nil && req.AvatarURL == nil && req.Language == nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "tidak ada data yang diupdate"))
 	}
 
@@ -127,4 +136,50 @@ func (c *UserProfileController) UpdateProfile(ctx *fiber.Ctx) error {
 	}
 
 	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "profil berhasil diperbarui", updatedProfile))
-}
\ No newline at end of file
+}
+
+func (c *UserProfileController) GetStreak(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(helpers.BasicResponse(false, "token tidak valid"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "user ID tidak valid"))
+	}
+
+	streak, err := c.userProfileService.GetStreak(ctx.Context(), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(helpers.BasicResponse(false, "gagal mengambil streak"))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "streak ditemukan", streak))
+}
+
+func (c *UserProfileController) UpdateStreakTimezone(ctx *fiber.Ctx) error {
+	claims := helpers.GetUserClaims(ctx)
+	if claims == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(helpers.BasicResponse(false, "token tidak valid"))
+	}
+
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, "user ID tidak valid"))
+	}
+
+	var req dto.UpdateStreakTimezoneDTO
+	if err := helpers.BindAndValidate(ctx, &req); err != nil {
+		if vErr, ok := err.(*helpers.ValidationError); ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(helpers.ErrorResponseRequest(false, vErr.Message, vErr.Errors))
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	streak, err := c.userProfileService.UpdateStreakTimezone(ctx.Context(), userID, &req)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(helpers.BasicResponse(false, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(helpers.SuccessResponseWithData(true, "timezone streak diperbarui", streak))
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CheckInDTO struct {
+	ID           int64     `json:"id"`
+	Date         string    `json:"date"` // tanggal kalender di zona waktu streak, YYYY-MM-DD
+	StreakDay    int       `json:"streak_day"`
+	CycleDay     int       `json:"cycle_day"`
+	RewardPoints int       `json:"reward_points"`
+	BonusPoints  int       `json:"bonus_points"`
+	TotalPoints  int       `json:"total_points"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CheckInRewardDTO satu baris tabel hadiah siklus 7 hari
+type CheckInRewardDTO struct {
+	Day    int `json:"day"`
+	Points int `json:"points"`
+	Bonus  int `json:"bonus,omitempty"`
+}
+
+type CheckInStatusDTO struct {
+	Timezone       string             `json:"timezone"`
+	Today          string             `json:"today"`
+	CheckedInToday bool               `json:"checked_in_today"`
+	StreakDay      int                `json:"streak_day"`     // 0 kalau rangkaian check-in sudah putus
+	NextCycleDay   int                `json:"next_cycle_day"` // hari siklus check-in berikutnya
+	NextReward     int                `json:"next_reward"`
+	Rewards        []CheckInRewardDTO `json:"rewards"`
+	Recent         []CheckInDTO       `json:"recent"`
+}
+
+type CheckInResultDTO struct {
+	CheckIn     CheckInDTO `json:"check_in"`
+	TotalPoints int        `json:"total_points"` // saldo poin setelah hadiah masuk
+}
@@ -18,6 +18,9 @@ type UserCustomDataResponseDTO struct {
 	Orders        []CustomOrderDTO        `json:"orders,omitempty"`
 	MonthlyVehicleCarbon    []MonthlyCarbonDTO    `json:"monthly_vehicle_carbon,omitempty"`    // Tambahan baru
 	MonthlyElectronicCarbon []MonthlyCarbonDTO    `json:"monthly_electronic_carbon,omitempty"` // Tambahan baru
+	Flights                 []CustomFlightDTO     `json:"flights,omitempty"`
+	MonthlyFlightCarbon     []MonthlyCarbonDTO    `json:"monthly_flight_carbon,omitempty"`
+	MonthlyCategoryCarbon   map[string][]MonthlyCarbonDTO `json:"monthly_category_carbon,omitempty"`
 }
 
 // MonthlyCarbonDTO untuk data karbon bulanan
@@ -60,6 +63,17 @@ type CustomElectronicDTO struct {
 	TotalCarbon  float64   `json:"total_carbon_emission_g"`
 }
 
+type CustomFlightDTO struct {
+	ID          int64     `json:"id"`
+	Origin      string    `json:"origin"`
+	Destination string    `json:"destination"`
+	CabinClass  string    `json:"cabin_class"`
+	RoundTrip   bool      `json:"round_trip"`
+	DistanceKm  float64   `json:"distance_km"`
+	TotalCarbon float64   `json:"total_carbon_emission_g"`
+	LoggedAt    time.Time `json:"logged_at"`
+}
+
 type CustomMissionProgressDTO struct {
 	ID           int64      `json:"id"`
 	Title        string     `json:"title"`
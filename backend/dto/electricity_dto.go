@@ -0,0 +1,33 @@
+// dto/electricity.go
+package dto
+
+import "time"
+
+type AddMeterReadingDTO struct {
+	ReadingType string     `json:"reading_type" validate:"required,oneof=meter token"`
+	ReadingKwh  float64    `json:"reading_kwh" validate:"gte=0"`
+	TokenKwh    float64    `json:"token_kwh" validate:"gte=0"`
+	TokenPrice  float64    `json:"token_price" validate:"gte=0"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// ElectricityPeriodDTO konsumsi listrik di antara dua pembacaan
+type ElectricityPeriodDTO struct {
+	ReadingID          int64     `json:"reading_id"`
+	ReadingType        string    `json:"reading_type"`
+	StartAt            time.Time `json:"start_at"`
+	EndAt              time.Time `json:"end_at"`
+	ConsumptionKwh     float64   `json:"consumption_kwh"`
+	CarbonEmission     float64   `json:"carbon_emission_g"`
+	AttributedKwh      float64   `json:"attributed_kwh"`
+	UnattributedKwh    float64   `json:"unattributed_kwh"`
+	UnattributedCarbon float64   `json:"unattributed_carbon_emission_g"`
+}
+
+type ElectricitySummaryDTO struct {
+	Periods             []ElectricityPeriodDTO `json:"periods"`
+	TotalConsumptionKwh float64                `json:"total_consumption_kwh"`
+	TotalCarbon         float64                `json:"total_carbon_emission_g"`
+	TotalAttributedKwh  float64                `json:"total_attributed_kwh"`
+	TotalUnattributed   float64                `json:"total_unattributed_kwh"`
+}
@@ -0,0 +1,12 @@
+// dto/flight.go
+package dto
+
+import "time"
+
+type AddFlightLogDTO struct {
+	Origin      string     `json:"origin" validate:"required,len=3"`
+	Destination string     `json:"destination" validate:"required,len=3"`
+	CabinClass  string     `json:"cabin_class" validate:"omitempty,oneof=economy premium_economy business first"`
+	RoundTrip   bool       `json:"round_trip"`
+	LoggedAt    *time.Time `json:"logged_at,omitempty"`
+}
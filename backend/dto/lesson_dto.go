@@ -0,0 +1,97 @@
+// dto/lesson.go
+package dto
+
+import "time"
+
+// LessonRequestDTO membuat atau mengganti seluruh isi lesson beserta soalnya
+type LessonRequestDTO struct {
+	MissionID       *int64               `json:"mission_id" validate:"omitempty,gt=0"` // misi quiz yang diselesaikan lewat kuis ini
+	Title           string               `json:"title" validate:"required,max=255"`
+	Content         string               `json:"content"`
+	PassScore       int                  `json:"pass_score" validate:"omitempty,min=1,max=100"` // default 70
+	MaxAttempts     int                  `json:"max_attempts" validate:"min=0"`                 // 0 berarti tidak dibatasi
+	CooldownMinutes int                  `json:"cooldown_minutes" validate:"min=0"`
+	Published       bool                 `json:"published"`
+	Questions       []QuizQuestionReqDTO `json:"questions" validate:"required,min=1,max=50,dive"`
+}
+
+type QuizQuestionReqDTO struct {
+	Prompt        string   `json:"prompt" validate:"required"`
+	Options       []string `json:"options" validate:"required,min=2,max=6,dive,required"`
+	CorrectOption int      `json:"correct_option" validate:"min=0"` // indeks di options, mulai dari 0
+	Explanation   string   `json:"explanation"`
+}
+
+type LessonResponseDTO struct {
+	ID              int64                     `json:"id"`
+	MissionID       *int64                    `json:"mission_id,omitempty"`
+	Title           string                    `json:"title"`
+	Content         string                    `json:"content,omitempty"`
+	PassScore       int                       `json:"pass_score"`
+	MaxAttempts     int                       `json:"max_attempts"`
+	CooldownMinutes int                       `json:"cooldown_minutes"`
+	Published       bool                      `json:"published"`
+	QuestionCount   int                       `json:"question_count"`
+	Questions       []QuizQuestionResponseDTO `json:"questions,omitempty"`
+	Progress        *LessonProgressDTO        `json:"progress,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+}
+
+// QuizQuestionResponseDTO kunci jawaban hanya diisi untuk admin
+type QuizQuestionResponseDTO struct {
+	ID            int64    `json:"id"`
+	Prompt        string   `json:"prompt"`
+	Options       []string `json:"options"`
+	CorrectOption *int     `json:"correct_option,omitempty"`
+	Explanation   string   `json:"explanation,omitempty"`
+}
+
+// LessonProgressDTO status percobaan user di periode berjalan
+type LessonProgressDTO struct {
+	Attempts      int        `json:"attempts"`
+	AttemptsLeft  *int       `json:"attempts_left"` // null berarti tidak dibatasi
+	Passed        bool       `json:"passed"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+type SubmitQuizDTO struct {
+	Answers []QuizAnswerDTO `json:"answers" validate:"required,min=1,dive"`
+}
+
+type QuizAnswerDTO struct {
+	QuestionID int64 `json:"question_id" validate:"required,gt=0"`
+	Option     int   `json:"option" validate:"min=0"`
+}
+
+type QuizResultDTO struct {
+	AttemptID        int64                   `json:"attempt_id"`
+	LessonID         int64                   `json:"lesson_id"`
+	Correct          int                     `json:"correct"`
+	Total            int                     `json:"total"`
+	Score            float64                 `json:"score"`
+	PassScore        int                     `json:"pass_score"`
+	Passed           bool                    `json:"passed"`
+	MissionCompleted bool                    `json:"mission_completed"`
+	Results          []QuizQuestionResultDTO `json:"results"`
+	Progress         LessonProgressDTO       `json:"progress"`
+}
+
+// QuizQuestionResultDTO jawaban benar dan penjelasan baru ditampilkan setelah lulus
+// atau percobaan habis
+type QuizQuestionResultDTO struct {
+	QuestionID    int64  `json:"question_id"`
+	Selected      int    `json:"selected"`
+	Correct       bool   `json:"correct"`
+	CorrectOption *int   `json:"correct_option,omitempty"`
+	Explanation   string `json:"explanation,omitempty"`
+}
+
+type QuizAttemptDTO struct {
+	ID        int64     `json:"id"`
+	Correct   int       `json:"correct"`
+	Total     int       `json:"total"`
+	Score     float64   `json:"score"`
+	Passed    bool      `json:"passed"`
+	CreatedAt time.Time `json:"created_at"`
+}
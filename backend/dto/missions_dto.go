@@ -2,6 +2,7 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -12,6 +13,8 @@ const (
 	MissionTypeStreak          MissionType = "streak"
 	MissionTypeActivity        MissionType = "activity"
 	MissionTypeCustom          MissionType = "custom"
+	MissionTypeProof           MissionType = "proof"
+	MissionTypeQuiz            MissionType = "quiz"
 )
 
 type CriteriaType string
@@ -32,19 +35,42 @@ const (
 	CriteriaFan            CriteriaType = "fan"
 	CriteriaWashingMachine CriteriaType = "washing_machine"
 	CriteriaOther          CriteriaType = "other"
+
+	CriteriaWasteOrganic  CriteriaType = "waste_organic"
+	CriteriaWastePlastic  CriteriaType = "waste_plastic"
+	CriteriaWastePaper    CriteriaType = "waste_paper"
+	CriteriaWasteResidual CriteriaType = "waste_residual"
+
+	CriteriaFlight CriteriaType = "flight"
+
+	// Baseline (carbon_reduction: persen penurunan emisi bulan ini terhadap baseline onboarding)
+	CriteriaBaselineReduction CriteriaType = "baseline_reduction"
 )
 
 type CreateMissionDTO struct {
 	Title            string        `json:"title" validate:"required"`
 	Description      string        `json:"description"`
-	MissionType      MissionType   `json:"mission_type" validate:"required,oneof=carbon_reduction streak activity custom"`
+	MissionType      MissionType   `json:"mission_type" validate:"required,oneof=carbon_reduction streak activity custom proof quiz"`
 	CriteriaType     *CriteriaType `json:"criteria_type,omitempty"` // ✅ baru ditambahkan
 	PointsReward     int           `json:"points_reward" validate:"required,min=0"`
 	GivesBadge       bool          `json:"gives_badge"`
 	BadgeID          *int64        `json:"badge_id"`
 	CarbonReductionG *float64      `json:"carbon_reduction_g"`
-	TargetValue      float64       `json:"target_value" validate:"required"`
+	TargetValue      float64       `json:"target_value" validate:"required_without=Rule"`
 	ExpiredAt        *time.Time    `json:"expired_at"`
+	StartsAt         *time.Time    `json:"starts_at"`
+	ProgressWindow   string        `json:"progress_window" validate:"omitempty,oneof=lifetime mission enrollment"`
+	Recurrence       string        `json:"recurrence" validate:"omitempty,oneof=none daily weekly monthly custom"`
+	RecurrenceRule   string        `json:"recurrence_rule,omitempty"`
+	AutoJoin         *bool         `json:"auto_join"`
+	Prerequisites    []int64       `json:"prerequisites" validate:"omitempty,dive,gt=0"`
+	PrerequisiteMode string        `json:"prerequisite_mode" validate:"omitempty,oneof=all any"`
+	Scope            string        `json:"scope" validate:"omitempty,oneof=individual team community"`
+	MinContribution  float64       `json:"min_contribution" validate:"omitempty,min=0"`
+	Comparator       string        `json:"comparator" validate:"omitempty,oneof=gte lte between"`
+	TargetMax        *float64      `json:"target_max"`
+	MinActivityLogs  int           `json:"min_activity_logs" validate:"omitempty,min=0"`
+	Rule             json.RawMessage `json:"rule,omitempty"` // menggantikan target_value/comparator/target_max
 }
 
 type MissionResponseDTO struct {
@@ -60,28 +86,60 @@ type MissionResponseDTO struct {
 	TargetValue      interface{}   `json:"target_value,omitempty"`
 	ExpiredAt        *time.Time    `json:"expired_at,omitempty"`
 	CreatedAt        time.Time     `json:"created_at"`
+	StartsAt         *time.Time    `json:"starts_at,omitempty"`
+	ProgressWindow   string        `json:"progress_window"`
+	Recurrence       string        `json:"recurrence"`
+	RecurrenceRule   string        `json:"recurrence_rule,omitempty"`
+	AutoJoin         bool          `json:"auto_join"`
+	PrerequisiteMode string        `json:"prerequisite_mode"`
+	Prerequisites    []int64       `json:"prerequisites,omitempty"`
+	Locked           bool          `json:"locked"` // hanya dihitung kalau request membawa token user
+	Scope            string        `json:"scope"`
+	MinContribution  float64       `json:"min_contribution,omitempty"`
+	Comparator       string        `json:"comparator"`
+	TargetMax        *float64      `json:"target_max,omitempty"`
+	MinActivityLogs  int           `json:"min_activity_logs,omitempty"`
+	Rule             json.RawMessage `json:"rule,omitempty"`
+	ArchivedAt       *time.Time    `json:"archived_at,omitempty"`
 }
 
 type UserMissionResponseDTO struct {
-	ID          int64              `json:"id"`
-	UserID      int64              `json:"user_id"`
-	Mission     MissionResponseDTO `json:"mission"`
-	CompletedAt *time.Time         `json:"completed_at,omitempty"`
-	CreatedAt   time.Time          `json:"created_at"`
+	ID              int64              `json:"id"`
+	UserID          int64              `json:"user_id"`
+	Mission         MissionResponseDTO `json:"mission"`
+	Status          string             `json:"status"`
+	JoinedVia       string             `json:"joined_via"`
+	StatusChangedAt time.Time          `json:"status_changed_at"`
+	EnrolledAt      time.Time          `json:"enrolled_at"`
+	CompletedAt     *time.Time         `json:"completed_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
 }
 
 type CreateMissionWithBadgeDTO struct {
 	Title            string       `json:"title" validate:"required"`
 	Description      string       `json:"description"`
-	MissionType      MissionType  `json:"mission_type" validate:"required,oneof=carbon_reduction streak activity custom"`
+	MissionType      MissionType  `json:"mission_type" validate:"required,oneof=carbon_reduction streak activity custom proof quiz"`
 	CriteriaType     CriteriaType `json:"criteria_type,omitempty"`
 	PointsReward     int          `json:"points_reward" validate:"required,min=0"`
 	GivesBadge       bool         `json:"gives_badge" validate:"required"`
 	BadgeName        string       `json:"badge_name,omitempty"`
 	BadgeImageURL    string       `json:"badge_image_url,omitempty"`
 	BadgeDescription string       `json:"badge_description,omitempty"`
-	TargetValue      float64      `json:"target_value" validate:"required"`
+	TargetValue      float64      `json:"target_value" validate:"required_without=Rule"`
 	ExpiredAt        *time.Time   `json:"expired_at"`
+	StartsAt         *time.Time   `json:"starts_at"`
+	ProgressWindow   string       `json:"progress_window" validate:"omitempty,oneof=lifetime mission enrollment"`
+	Recurrence       string       `json:"recurrence" validate:"omitempty,oneof=none daily weekly monthly custom"`
+	RecurrenceRule   string       `json:"recurrence_rule,omitempty"`
+	AutoJoin         *bool        `json:"auto_join"`
+	Prerequisites    []int64      `json:"prerequisites" validate:"omitempty,dive,gt=0"`
+	PrerequisiteMode string       `json:"prerequisite_mode" validate:"omitempty,oneof=all any"`
+	Scope            string       `json:"scope" validate:"omitempty,oneof=individual team community"`
+	MinContribution  float64      `json:"min_contribution" validate:"omitempty,min=0"`
+	Comparator       string       `json:"comparator" validate:"omitempty,oneof=gte lte between"`
+	TargetMax        *float64     `json:"target_max"`
+	MinActivityLogs  int          `json:"min_activity_logs" validate:"omitempty,min=0"`
+	Rule             json.RawMessage `json:"rule,omitempty"` // menggantikan target_value/comparator/target_max
 }
 
 type MissionWithBadgeResponseDTO struct {
@@ -100,3 +158,211 @@ type MissionProgressResponse struct {
 	GivesBadge   bool    `json:"gives_badge"`
 	BadgeID      *int64  `json:"badge_id,omitempty"`
 }
+
+type MissionContributorDTO struct {
+	UserID       int64   `json:"user_id"`
+	Username     string  `json:"username"`
+	Contribution float64 `json:"contribution"`
+	Qualifies    bool    `json:"qualifies"` // memenuhi kontribusi minimum untuk hadiah
+}
+
+// CollectiveProgressDTO progress bar bersama untuk satu tim atau komunitas
+type CollectiveProgressDTO struct {
+	MissionID       int64                   `json:"mission_id"`
+	Scope           string                  `json:"scope"`
+	TeamID          *int64                  `json:"team_id,omitempty"`
+	TeamName        string                  `json:"team_name,omitempty"`
+	TargetValue     float64                 `json:"target_value"`
+	Progress        float64                 `json:"progress"`
+	Percent         float64                 `json:"percent"`
+	CompletedAt     *time.Time              `json:"completed_at,omitempty"`
+	MinContribution float64                 `json:"min_contribution"`
+	MyContribution  float64                 `json:"my_contribution"`
+	Contributors    []MissionContributorDTO `json:"contributors"`
+}
+
+// UpdateMissionDTO hanya field yang dikirim yang diubah. Tipe, criteria, scope, comparator,
+// window, pengulangan dan metric aturan tidak bisa diubah; duplikasi misi untuk itu.
+type UpdateMissionDTO struct {
+	Title            *string    `json:"title" validate:"omitempty,min=1"`
+	Description      *string    `json:"description"`
+	PointsReward     *int       `json:"points_reward" validate:"omitempty,min=0"`
+	GivesBadge       *bool      `json:"gives_badge"`
+	BadgeID          *int64     `json:"badge_id"`
+	TargetValue      *float64   `json:"target_value" validate:"omitempty,min=0"`
+	TargetMax        *float64   `json:"target_max"`
+	ExpiredAt        *time.Time `json:"expired_at"`
+	StartsAt         *time.Time `json:"starts_at"`
+	AutoJoin         *bool      `json:"auto_join"`
+	Prerequisites    *[]int64   `json:"prerequisites"`
+	PrerequisiteMode *string    `json:"prerequisite_mode" validate:"omitempty,oneof=all any"`
+	MinContribution  *float64   `json:"min_contribution" validate:"omitempty,min=0"`
+	MinActivityLogs  *int       `json:"min_activity_logs" validate:"omitempty,min=0"`
+}
+
+type UpdateMissionResponseDTO struct {
+	Mission        MissionResponseDTO `json:"mission"`
+	TargetChanged  bool               `json:"target_changed"`
+	NewlyCompleted int64              `json:"newly_completed"` // peserta (grup untuk misi kolektif) yang langsung selesai
+}
+
+type DuplicateMissionDTO struct {
+	Title     *string    `json:"title" validate:"omitempty,min=1"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiredAt *time.Time `json:"expired_at"`
+}
+
+// MissionTimelineDTO riwayat progres satu misi untuk satu user, terbaru dulu
+type MissionTimelineDTO struct {
+	MissionID       int64                     `json:"mission_id"`
+	UserID          int64                     `json:"user_id"`
+	Title           string                    `json:"title"`
+	TargetValue     float64                   `json:"target_value"`
+	CurrentProgress float64                   `json:"current_progress"`
+	Entries         []MissionProgressEntryDTO `json:"entries"`
+}
+
+type MissionProgressEntryDTO struct {
+	ProgressValue float64    `json:"progress_value"`
+	Delta         float64    `json:"delta"`
+	Trigger       string     `json:"trigger"`
+	EventType     string     `json:"event_type,omitempty"`
+	SourceType    string     `json:"source_type,omitempty"` // tabel log yang memicu, mis. carbon_vehicle_logs
+	SourceID      *int64     `json:"source_id,omitempty"`
+	PeriodStart   *time.Time `json:"period_start,omitempty"`
+	RecordedAt    time.Time  `json:"recorded_at"`
+}
+
+// ProofSubmissionDTO bukti foto untuk misi proof
+type ProofSubmissionDTO struct {
+	ID           int64      `json:"id"`
+	MissionID    int64      `json:"mission_id"`
+	MissionTitle string     `json:"mission_title,omitempty"`
+	UserID       int64      `json:"user_id"`
+	Username     string     `json:"username,omitempty"`
+	PhotoURL     string     `json:"photo_url"`
+	Note         string     `json:"note"`
+	Status       string     `json:"status"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type ReviewProofDTO struct {
+	Note string `json:"note" validate:"omitempty,max=500"`
+}
+
+// MissionJobRunDTO satu run job scheduler misi
+type MissionJobRunDTO struct {
+	ID         int64      `json:"id"`
+	Job        string     `json:"job"`
+	Instance   string     `json:"instance"`
+	Status     string     `json:"status"`
+	Affected   int64      `json:"affected"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type ProofReviewResponseDTO struct {
+	Proof            ProofSubmissionDTO `json:"proof"`
+	MissionCompleted bool               `json:"mission_completed"`
+}
+
+// RecommendedMissionDTO misi yang direkomendasikan beserta alasan singkatnya
+type RecommendedMissionDTO struct {
+	Mission         MissionResponseDTO `json:"mission"`
+	Score           float64            `json:"score"`
+	Reason          string             `json:"reason"`
+	Joined          bool               `json:"joined"`
+	Progress        float64            `json:"progress"`
+	ProgressPercent float64            `json:"progress_percent"`
+	EstimatedDays   *float64           `json:"estimated_days,omitempty"` // perkiraan hari selesai dengan kecepatan 30 hari terakhir
+}
+
+// DryRunMissionDTO definisi misi draft yang dievaluasi tanpa disimpan
+type DryRunMissionDTO struct {
+	Mission    *CreateMissionDTO `json:"mission" validate:"required"`
+	SampleSize int               `json:"sample_size" validate:"omitempty,min=1,max=10000"` // kosong berarti semua user
+	At         *time.Time        `json:"at"`                                               // waktu publish yang disimulasikan, default sekarang
+}
+
+type MissionDryRunResultDTO struct {
+	UsersEvaluated     int                  `json:"users_evaluated"`
+	Sampled            bool                 `json:"sampled"`
+	EligibleUsers      int                  `json:"eligible_users"` // prasyarat terpenuhi
+	UsersWithProgress  int                  `json:"users_with_progress"`
+	WouldComplete      int                  `json:"would_complete"`
+	CompletionRate     float64              `json:"completion_rate"` // persen dari eligible_users
+	SettlesAtWindowEnd bool                 `json:"settles_at_window_end"`
+	PointsLiability    int                  `json:"points_liability"`
+	BadgesAwarded      int                  `json:"badges_awarded"`
+	WindowFrom         *time.Time           `json:"window_from,omitempty"`
+	WindowTo           *time.Time           `json:"window_to,omitempty"`
+	Percentiles        DryRunPercentilesDTO `json:"percentiles"`
+	Distribution       []DryRunBucketDTO    `json:"distribution"`
+
+	// true untuk misi enrollment: progres dihitung seolah user ikut sejak window_from,
+	// jadi would_complete adalah batas atas. Setelah publish, progres mulai dari saat ikut.
+	EnrollmentFromWindowStart bool `json:"enrollment_from_window_start"`
+}
+
+type DryRunPercentilesDTO struct {
+	Min  float64 `json:"min"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+// DryRunBucketDTO jumlah user per rentang persen target
+type DryRunBucketDTO struct {
+	Label string `json:"label"`
+	Users int    `json:"users"`
+}
+
+// ImportMissionDTO satu definisi misi pada import massal (YAML/CSV). key dipakai untuk
+// upsert, jadi import ulang mengubah misi yang sama alih-alih membuat duplikat.
+type ImportMissionDTO struct {
+	Key string `json:"key" validate:"required,max=100"`
+	CreateMissionDTO
+	PrerequisiteKeys []string `json:"prerequisite_keys" validate:"omitempty,dive,required"` // key misi lain di file atau yang sudah diimport
+	BadgeName        string   `json:"badge_name,omitempty"`
+	BadgeImageURL    string   `json:"badge_image_url,omitempty"`
+	BadgeDescription string   `json:"badge_description,omitempty"`
+}
+
+type MissionImportResultDTO struct {
+	DryRun    bool                   `json:"dry_run"`
+	Created   int                    `json:"created"`
+	Updated   int                    `json:"updated"`
+	Unchanged int                    `json:"unchanged"`
+	Items     []MissionImportItemDTO `json:"items"`
+}
+
+type MissionImportItemDTO struct {
+	Row            int                      `json:"row"`
+	Key            string                   `json:"key"`
+	Action         string                   `json:"action"`               // create, update, unchanged
+	MissionID      *int64                   `json:"mission_id,omitempty"` // kosong untuk misi baru saat dry run
+	Changes        []MissionImportChangeDTO `json:"changes,omitempty"`
+	NewlyCompleted int64                    `json:"newly_completed,omitempty"` // peserta yang langsung selesai karena target turun
+}
+
+type MissionImportChangeDTO struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// MissionImportErrorDTO kesalahan validasi per baris; row mengikuti nomor baris CSV
+// atau urutan misi di YAML
+type MissionImportErrorDTO struct {
+	Row     int    `json:"row"`
+	Key     string `json:"key,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
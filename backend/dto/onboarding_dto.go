@@ -0,0 +1,39 @@
+// dto/onboarding.go
+package dto
+
+import "time"
+
+type ApplianceAnswerDTO struct {
+	DeviceType  string  `json:"device_type" validate:"required,oneof=laptop desktop tv ac fridge fan washing_machine other"`
+	PowerWatts  int     `json:"power_watts" validate:"gte=0"`
+	HoursPerDay float64 `json:"hours_per_day" validate:"gte=0,lte=24"`
+}
+
+type OnboardingQuestionnaireDTO struct {
+	CommuteMode        string               `json:"commute_mode" validate:"required,oneof=car motorcycle bicycle public_transport walk"`
+	FuelType           string               `json:"fuel_type" validate:"omitempty,oneof=petrol diesel electric none"`
+	CommuteKmPerDay    float64              `json:"commute_km_per_day" validate:"gte=0"`
+	CommuteDaysPerWeek int                  `json:"commute_days_per_week" validate:"gte=0,lte=7"`
+	HouseholdSize      int                  `json:"household_size" validate:"required,gte=1"`
+	Appliances         []ApplianceAnswerDTO `json:"appliances" validate:"dive"`
+	Diet               string               `json:"diet" validate:"required,oneof=vegan vegetarian pescatarian low_meat medium_meat high_meat"`
+}
+
+// BaselineDTO ringkasan jejak karbon tahunan (kg CO2e)
+type BaselineDTO struct {
+	ID                  int64     `json:"id"`
+	TransportEmission   float64   `json:"transport_emission_g"`
+	ElectricityEmission float64   `json:"electricity_emission_g"`
+	DietEmission        float64   `json:"diet_emission_g"`
+	TotalEmission       float64   `json:"total_emission_g"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// BaselineComparisonDTO baseline terbaru dibanding pengisian sebelumnya
+type BaselineComparisonDTO struct {
+	Current       *BaselineDTO  `json:"current"`
+	Previous      *BaselineDTO  `json:"previous,omitempty"`
+	ChangeKg      float64       `json:"change_g"`
+	ChangePercent float64       `json:"change_percent"`
+	History       []BaselineDTO `json:"history"`
+}
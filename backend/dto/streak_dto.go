@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type UpdateStreakTimezoneDTO struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
+
+type StreakResponseDTO struct {
+	Timezone         string     `json:"timezone"`
+	CurrentStreak    int        `json:"current_streak"`
+	LongestStreak    int        `json:"longest_streak"`
+	LastActiveDate   *time.Time `json:"last_active_date"`
+	FreezesAvailable int        `json:"freezes_available"`
+	FreezesUsed      int        `json:"freezes_used"`
+	ActiveToday      bool       `json:"active_today"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type CreateTeamDTO struct {
+	Name string `json:"name" validate:"required,min=3,max=100"`
+	Kind string `json:"kind" validate:"omitempty,oneof=team class"`
+}
+
+type JoinTeamDTO struct {
+	JoinCode string `json:"join_code" validate:"required"`
+}
+
+type TeamResponseDTO struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	JoinCode    string    `json:"join_code"`
+	CreatedBy   int64     `json:"created_by"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+// dto/translation.go
+package dto
+
+import "time"
+
+type UpsertTranslationDTO struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type TranslationDTO struct {
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ContentTranslationsDTO semua terjemahan satu misi, badge atau item toko
+type ContentTranslationsDTO struct {
+	Entity           string           `json:"entity"`
+	ID               int64            `json:"id"`
+	FallbackLocale   string           `json:"fallback_locale"`
+	SupportedLocales []string         `json:"supported_locales"`
+	Translations     []TranslationDTO `json:"translations"`
+}
@@ -11,6 +11,7 @@ type UserProfileUpdateDTO struct {
 	AvatarURL *string    `json:"avatar_url,omitempty" validate:"omitempty,url"`
 	Birthdate *time.Time `json:"birthdate,omitempty"`
 	Gender    *string    `json:"gender,omitempty" validate:"omitempty,oneof=male female other"`
+	Language  *string    `json:"language,omitempty" validate:"omitempty,oneof=id en"`
 }
 
 type UserProfileResponseDTO struct {
@@ -20,6 +21,7 @@ type UserProfileResponseDTO struct {
 	AvatarURL *string     `json:"avatar_url"`
 	Birthdate *time.Time  `json:"birthdate"`
 	Gender    *string     `json:"gender"`	
+	Language  *string     `json:"language"`
 	CreatedAt time.Time  `json:"created_at"`
 }
 
@@ -29,4 +31,5 @@ type UserWithProfileResponseDTO struct {
 	Email    string                 `json:"email"`
 	Role     string                 `json:"role"`
 	Profile  UserProfileResponseDTO `json:"profile"`
+	Streak   StreakResponseDTO      `json:"streak"`
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+// dto/waste.go
+package dto
+
+import "time"
+
+type AddWasteLogDTO struct {
+	WasteStream   string     `json:"waste_stream" validate:"required,oneof=organic plastic paper residual"`
+	DisposalRoute string     `json:"disposal_route" validate:"required,oneof=landfill recycled composted bank_sampah"`
+	WeightKg      float64    `json:"weight_kg" validate:"required,gt=0"`
+	LoggedAt      *time.Time `json:"logged_at,omitempty"`
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed airports.csv
+var airportsCSV string
+
+type Airport struct {
+	IATA    string  `json:"iata"`
+	Name    string  `json:"name"`
+	City    string  `json:"city"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+var (
+	airports     map[string]*Airport
+	airportsErr  error
+	airportsOnce sync.Once
+)
+
+func loadAirports() {
+	records, err := csv.NewReader(strings.NewReader(airportsCSV)).ReadAll()
+	if err != nil {
+		airportsErr = fmt.Errorf("gagal baca data bandara: %w", err)
+		return
+	}
+
+	airports = make(map[string]*Airport, len(records))
+	for i, rec := range records {
+		// Lewati header
+		if i == 0 {
+			continue
+		}
+		lat, err := strconv.ParseFloat(rec[4], 64)
+		if err != nil {
+			airportsErr = fmt.Errorf("latitude bandara %s tidak valid: %w", rec[0], err)
+			return
+		}
+		lon, err := strconv.ParseFloat(rec[5], 64)
+		if err != nil {
+			airportsErr = fmt.Errorf("longitude bandara %s tidak valid: %w", rec[0], err)
+			return
+		}
+		airports[rec[0]] = &Airport{
+			IATA:    rec[0],
+			Name:    rec[1],
+			City:    rec[2],
+			Country: rec[3],
+			Lat:     lat,
+			Lon:     lon,
+		}
+	}
+}
+
+// FindAirport mencari bandara berdasarkan kode IATA (tidak case sensitive)
+func FindAirport(iata string) (*Airport, error) {
+	airportsOnce.Do(loadAirports)
+	if airportsErr != nil {
+		return nil, airportsErr
+	}
+
+	airport, ok := airports[strings.ToUpper(strings.TrimSpace(iata))]
+	if !ok {
+		return nil, fmt.Errorf("bandara dengan kode %s tidak ditemukan", iata)
+	}
+	return airport, nil
+}
+
+// GreatCircleDistanceKm menghitung jarak lingkaran besar (haversine) dalam kilometer
+func GreatCircleDistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
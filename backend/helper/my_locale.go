@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	LocaleIndonesian = "id"
+	LocaleEnglish    = "en"
+)
+
+// SupportedLocales locale yang punya terjemahan konten
+var SupportedLocales = []string{LocaleIndonesian, LocaleEnglish}
+
+type localeKey struct{}
+
+// NormalizeLocale mengubah tag bahasa (mis. "en-US", "id_ID") ke locale yang didukung
+func NormalizeLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	// "in" kode lama untuk Bahasa Indonesia
+	if tag == "in" {
+		tag = LocaleIndonesian
+	}
+	for _, locale := range SupportedLocales {
+		if tag == locale {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// DefaultLocale locale fallback dari DEFAULT_LOCALE, default Bahasa Indonesia
+func DefaultLocale() string {
+	if locale, ok := NormalizeLocale(os.Getenv("DEFAULT_LOCALE")); ok {
+		return locale
+	}
+	return LocaleIndonesian
+}
+
+// ParseAcceptLanguage locale didukung dengan bobot q tertinggi dari header Accept-Language
+func ParseAcceptLanguage(header string) (string, bool) {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale, ok := NormalizeLocale(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale, true
+}
+
+// SetLocale menyimpan locale request; service membacanya lewat LocaleFromContext(ctx.Context())
+func SetLocale(c *fiber.Ctx, locale string) {
+	c.Locals(localeKey{}, locale)
+}
+
+// WithLocale untuk context di luar request fiber
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext locale request, atau locale fallback kalau tidak diset
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale()
+}
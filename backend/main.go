@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/Qodarrz/fiber-app/middleware"
+	"github.com/Qodarrz/fiber-app/repository"
 	"github.com/Qodarrz/fiber-app/routes"
+	"github.com/Qodarrz/fiber-app/service"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	_ "github.com/jackc/pgx/v4/stdlib"
@@ -65,6 +69,9 @@ func main() {
 	mw := middleware.InitMiddlewares(db)
 	routes.Setup(app, db, mw)
 
+	// Scheduler siklus hidup misi (rollover, penutupan, pengingat, rekonsiliasi)
+	service.StartMissionScheduler(context.Background(), repository.CheckMissionRepository(db), repository.NewSchedulerRepository(db), 15*time.Minute)
+
 	// Port default :8080 (bisa override via .env PORT)
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -17,9 +17,12 @@ import (
 )
 
 type Middlewares struct {
-	KeyApi fiber.Handler
-	JWT    fiber.Handler
-	DB     *sql.DB
+	KeyApi      fiber.Handler
+	JWT         fiber.Handler
+	OptionalJWT fiber.Handler
+	Admin       fiber.Handler
+	Locale      fiber.Handler
+	DB          *sql.DB
 }
 
 func InitRateLimiterConfig() fiber.Handler {
@@ -53,9 +56,24 @@ func AuthKeyMiddleware(apiKey string) fiber.Handler {
 }
 
 func InitMiddlewares(db *sql.DB) *Middlewares {
+	jwtHandler := initJWTMiddleware()
 	return &Middlewares{
-		JWT: initJWTMiddleware(),
-		DB:  db,
+		JWT:         jwtHandler,
+		OptionalJWT: optionalJWT(jwtHandler),
+		Admin:       AdminMiddleware(db),
+		Locale:      LocaleMiddleware(db),
+		DB:          db,
+	}
+}
+
+// optionalJWT validasi token hanya kalau header Authorization dikirim;
+// tanpa header request tetap lanjut sebagai anonim
+func optionalJWT(jwtHandler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			return c.Next()
+		}
+		return jwtHandler(c)
 	}
 }
 
@@ -125,26 +143,18 @@ func VerifyEmailMiddleware(db *sql.DB) fiber.Handler {
 	}
 }
 
-
-
+// AdminMiddleware dipasang setelah JWT; role dibaca dari database supaya
+// perubahan role langsung berlaku tanpa menunggu token baru
 func AdminMiddleware(db *sql.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		userToken := c.Locals("user")
-		if userToken == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(
-				helpers.BasicResponse(false, "unauthorized"),
-			)
-		}
-
-		claims, ok := userToken.(*helpers.JWTClaims)
-		if !ok {
+		claims := helpers.GetUserClaims(c)
+		if claims == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(
 				helpers.BasicResponse(false, "invalid token claims"),
 			)
 		}
 
-		userIDStr := claims.ID
-		userID, err := strconv.ParseInt(userIDStr, 10, 64)
+		userID, err := strconv.ParseInt(claims.UserID, 10, 64)
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(
 				helpers.BasicResponse(false, "invalid user id in token"),
@@ -174,3 +184,32 @@ func AdminMiddleware(db *sql.DB) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// LocaleMiddleware menentukan bahasa konten: pengaturan bahasa di profil (kalau token sudah
+// divalidasi), lalu Accept-Language, lalu DEFAULT_LOCALE. Pasang setelah JWT/OptionalJWT
+// supaya pengaturan profil terbaca.
+func LocaleMiddleware(db *sql.DB) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		locale, ok := helpers.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+		if !ok {
+			locale = helpers.DefaultLocale()
+		}
+
+		if claims := helpers.GetUserClaims(c); claims != nil {
+			if userID, err := strconv.ParseInt(claims.UserID, 10, 64); err == nil {
+				var language sql.NullString
+				err := db.QueryRowContext(c.Context(), "SELECT language FROM user_profiles WHERE user_id = $1", userID).Scan(&language)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					fmt.Println("DB error:", err)
+				}
+				if preferred, ok := helpers.NormalizeLocale(language.String); ok {
+					locale = preferred
+				}
+			}
+		}
+
+		helpers.SetLocale(c, locale)
+		c.Set(fiber.HeaderContentLanguage, locale)
+		return c.Next()
+	}
+}
@@ -0,0 +1,14 @@
+-- Waste and recycling logs
+CREATE TABLE IF NOT EXISTS carbon_waste_logs (
+    id                 BIGSERIAL PRIMARY KEY,
+    user_id            BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    waste_stream       VARCHAR(20) NOT NULL CHECK (waste_stream IN ('organic', 'plastic', 'paper', 'residual')),
+    disposal_route     VARCHAR(20) NOT NULL CHECK (disposal_route IN ('landfill', 'recycled', 'composted', 'bank_sampah')),
+    weight_kg          DOUBLE PRECISION NOT NULL CHECK (weight_kg > 0),
+    carbon_emission_g  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    avoided_emission_g DOUBLE PRECISION NOT NULL DEFAULT 0,
+    logged_at          TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_carbon_waste_logs_user_logged ON carbon_waste_logs (user_id, logged_at);
+CREATE INDEX IF NOT EXISTS idx_carbon_waste_logs_user_stream ON carbon_waste_logs (user_id, waste_stream);
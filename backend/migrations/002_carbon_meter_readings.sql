@@ -0,0 +1,14 @@
+-- Household electricity meter readings and PLN token purchases
+CREATE TABLE IF NOT EXISTS carbon_meter_readings (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reading_type      VARCHAR(10) NOT NULL CHECK (reading_type IN ('meter', 'token')),
+    reading_kwh       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    token_kwh         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    token_price       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    consumption_kwh   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    carbon_emission_g DOUBLE PRECISION NOT NULL DEFAULT 0,
+    read_at           TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_carbon_meter_readings_user_type_read ON carbon_meter_readings (user_id, reading_type, read_at);
@@ -0,0 +1,14 @@
+-- Flight emissions logs
+CREATE TABLE IF NOT EXISTS carbon_flight_logs (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    origin            CHAR(3) NOT NULL,
+    destination       CHAR(3) NOT NULL,
+    cabin_class       VARCHAR(20) NOT NULL DEFAULT 'economy' CHECK (cabin_class IN ('economy', 'premium_economy', 'business', 'first')),
+    round_trip        BOOLEAN NOT NULL DEFAULT FALSE,
+    distance_km       DOUBLE PRECISION NOT NULL,
+    carbon_emission_g DOUBLE PRECISION NOT NULL DEFAULT 0,
+    logged_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_carbon_flight_logs_user_logged ON carbon_flight_logs (user_id, logged_at);
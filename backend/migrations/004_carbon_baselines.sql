@@ -0,0 +1,19 @@
+-- Onboarding questionnaire baselines (annual footprint estimate)
+CREATE TABLE IF NOT EXISTS carbon_baselines (
+    id                     BIGSERIAL PRIMARY KEY,
+    user_id                BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    commute_mode           VARCHAR(30) NOT NULL,
+    fuel_type              VARCHAR(20) NOT NULL,
+    commute_km_per_day     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    commute_days_per_week  INT NOT NULL DEFAULT 0 CHECK (commute_days_per_week BETWEEN 0 AND 7),
+    household_size         INT NOT NULL DEFAULT 1 CHECK (household_size >= 1),
+    appliances             JSONB NOT NULL DEFAULT '[]',
+    diet                   VARCHAR(20) NOT NULL,
+    transport_emission_g   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    electricity_emission_g DOUBLE PRECISION NOT NULL DEFAULT 0,
+    diet_emission_g        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_emission_g       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at             TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_carbon_baselines_user_created ON carbon_baselines (user_id, created_at DESC);
@@ -0,0 +1,18 @@
+-- Mission start date, per-mission progress window and per-user enrollment
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS progress_window VARCHAR(20) NOT NULL DEFAULT 'enrollment'
+        CHECK (progress_window IN ('lifetime', 'mission', 'enrollment'));
+
+ALTER TABLE user_missions
+    ADD COLUMN IF NOT EXISTS enrolled_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE user_missions SET enrolled_at = created_at WHERE enrolled_at > created_at;
+
+-- Progres lama dihitung dari seluruh riwayat; hapus untuk misi yang belum selesai
+-- supaya dihitung ulang sesuai window pada evaluasi berikutnya
+DELETE FROM user_mission_progress ump
+WHERE NOT EXISTS (
+    SELECT 1 FROM user_missions um
+    WHERE um.user_id = ump.user_id AND um.mission_id = ump.mission_id AND um.completed_at IS NOT NULL
+);
@@ -0,0 +1,21 @@
+-- Structured login events and consecutive-day streak tracking
+CREATE TABLE IF NOT EXISTS user_login_events (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source       VARCHAR(20) NOT NULL,
+    logged_in_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    local_date   DATE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_login_events_user_date ON user_login_events (user_id, local_date);
+
+CREATE TABLE IF NOT EXISTS user_streaks (
+    user_id           BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone          VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    current_streak    INT NOT NULL DEFAULT 0,
+    longest_streak    INT NOT NULL DEFAULT 0,
+    last_active_date  DATE,
+    freezes_available INT NOT NULL DEFAULT 0 CHECK (freezes_available >= 0),
+    freezes_used      INT NOT NULL DEFAULT 0,
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
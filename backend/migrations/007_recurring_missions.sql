@@ -0,0 +1,26 @@
+-- Recurring missions: recurrence rule, per-period progress and completion records
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS recurrence VARCHAR(20) NOT NULL DEFAULT 'none'
+        CHECK (recurrence IN ('none', 'daily', 'weekly', 'monthly', 'custom')),
+    ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(100);
+
+-- Periode progres yang sedang berjalan (NULL untuk misi sekali jalan)
+ALTER TABLE user_mission_progress
+    ADD COLUMN IF NOT EXISTS period_start TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS period_end TIMESTAMPTZ;
+
+-- Riwayat per periode: penyelesaian (poin sekali per periode) dan progres yang diarsipkan
+CREATE TABLE IF NOT EXISTS user_mission_periods (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mission_id     BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    period_start   TIMESTAMPTZ NOT NULL,
+    period_end     TIMESTAMPTZ NOT NULL,
+    progress_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    completed_at   TIMESTAMP,
+    points_awarded INT NOT NULL DEFAULT 0,
+    archived_at    TIMESTAMP,
+    UNIQUE (user_id, mission_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mission_periods_mission ON user_mission_periods (mission_id, period_start);
@@ -0,0 +1,18 @@
+-- Explicit mission enrollment and per-user mission state
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS auto_join BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE user_missions
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'completed', 'failed', 'expired', 'abandoned')),
+    ADD COLUMN IF NOT EXISTS joined_via VARCHAR(10) NOT NULL DEFAULT 'auto'
+        CHECK (joined_via IN ('auto', 'manual')),
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Misi sekali jalan yang sudah selesai; misi berulang tetap aktif
+UPDATE user_missions um
+SET status = 'completed', status_changed_at = um.completed_at
+FROM missions m
+WHERE m.id = um.mission_id AND um.completed_at IS NOT NULL AND m.recurrence = 'none';
+
+CREATE INDEX IF NOT EXISTS idx_user_missions_user_status ON user_missions (user_id, status);
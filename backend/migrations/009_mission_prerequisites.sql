@@ -0,0 +1,13 @@
+-- Mission chains: prerequisite missions (all-of / any-of)
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS prerequisite_mode VARCHAR(3) NOT NULL DEFAULT 'all'
+        CHECK (prerequisite_mode IN ('all', 'any'));
+
+CREATE TABLE IF NOT EXISTS mission_prerequisites (
+    mission_id      BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    prerequisite_id BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    PRIMARY KEY (mission_id, prerequisite_id),
+    CHECK (mission_id <> prerequisite_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mission_prerequisites_prerequisite ON mission_prerequisites (prerequisite_id);
@@ -0,0 +1,49 @@
+-- Team / community missions: shared collective target with per-member contributions
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS scope VARCHAR(10) NOT NULL DEFAULT 'individual'
+        CHECK (scope IN ('individual', 'team', 'community')),
+    ADD COLUMN IF NOT EXISTS min_contribution DOUBLE PRECISION NOT NULL DEFAULT 0
+        CHECK (min_contribution >= 0);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    kind       VARCHAR(10) NOT NULL DEFAULT 'team' CHECK (kind IN ('team', 'class')),
+    join_code  VARCHAR(16) NOT NULL UNIQUE,
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id   BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role      VARCHAR(10) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members (user_id);
+
+-- team_id NULL = misi komunitas
+CREATE TABLE IF NOT EXISTS mission_collective_progress (
+    id             BIGSERIAL PRIMARY KEY,
+    mission_id     BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    team_id        BIGINT REFERENCES teams(id) ON DELETE CASCADE,
+    progress_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    completed_at   TIMESTAMP,
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mission_collective_progress
+    ON mission_collective_progress (mission_id, (COALESCE(team_id, 0)));
+
+CREATE TABLE IF NOT EXISTS mission_contributions (
+    mission_id   BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    team_id      BIGINT REFERENCES teams(id) ON DELETE CASCADE,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contribution DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_mission_contributions
+    ON mission_contributions (mission_id, (COALESCE(team_id, 0)), user_id);
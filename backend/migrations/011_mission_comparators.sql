@@ -0,0 +1,11 @@
+-- "Stay below" missions: comparator, optional upper bound, minimum logged activity
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS comparator VARCHAR(7) NOT NULL DEFAULT 'gte'
+        CHECK (comparator IN ('gte', 'lte', 'between')),
+    ADD COLUMN IF NOT EXISTS target_max DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS min_activity_logs INTEGER NOT NULL DEFAULT 0
+        CHECK (min_activity_logs >= 0);
+
+-- Periode misi berulang yang sudah dinilai saat window ditutup
+ALTER TABLE user_mission_periods
+    ADD COLUMN IF NOT EXISTS settled_at TIMESTAMPTZ;
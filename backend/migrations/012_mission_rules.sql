@@ -0,0 +1,3 @@
+-- Declarative mission rules (metric, filters, aggregation, window, comparator, target)
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS rule JSONB;
@@ -0,0 +1,6 @@
+-- Admin mission management: archive (hidden, history kept) and edit tracking
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_missions_archived_at ON missions (archived_at);
@@ -0,0 +1,7 @@
+-- Idempotent mission rewards: a completion can be paid out only once per mission
+-- (or once per period for recurring missions)
+ALTER TABLE point_transactions
+    ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_point_transactions_idempotency_key
+    ON point_transactions (idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Mission progress history: one snapshot per progress change, with the event
+-- (and source log) that moved it
+CREATE TABLE IF NOT EXISTS mission_progress_snapshots (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mission_id     BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    progress_value DOUBLE PRECISION NOT NULL,
+    delta          DOUBLE PRECISION NOT NULL,
+    trigger        VARCHAR(20) NOT NULL,
+    event_type     VARCHAR(30),
+    source_type    VARCHAR(50),
+    source_id      BIGINT,
+    period_start   TIMESTAMPTZ,
+    recorded_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mission_progress_snapshots_user_mission
+    ON mission_progress_snapshots (user_id, mission_id, recorded_at DESC);
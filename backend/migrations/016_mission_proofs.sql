@@ -0,0 +1,21 @@
+-- Proof-of-action missions: users submit a photo and note, admins approve or reject
+CREATE TABLE IF NOT EXISTS mission_proof_submissions (
+    id           BIGSERIAL PRIMARY KEY,
+    mission_id   BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    photo_url    TEXT NOT NULL,
+    note         TEXT NOT NULL DEFAULT '',
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    review_note  TEXT NOT NULL DEFAULT '',
+    reviewed_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at  TIMESTAMP,
+    period_start TIMESTAMPTZ,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- At most one pending submission per user and mission
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mission_proof_submissions_pending
+    ON mission_proof_submissions (user_id, mission_id) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_mission_proof_submissions_status
+    ON mission_proof_submissions (status, created_at);
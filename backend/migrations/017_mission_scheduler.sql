@@ -0,0 +1,17 @@
+-- Mission lifecycle scheduler: job run history and expiry reminders
+CREATE TABLE IF NOT EXISTS mission_job_runs (
+    id          BIGSERIAL PRIMARY KEY,
+    job         VARCHAR(50) NOT NULL,
+    instance    VARCHAR(100) NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    affected    BIGINT NOT NULL DEFAULT 0,
+    error       TEXT,
+    started_at  TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mission_job_runs_job_started ON mission_job_runs (job, started_at DESC);
+
+-- Set once the "expires within 24h" reminder has been sent for an enrollment
+ALTER TABLE user_missions
+    ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP;
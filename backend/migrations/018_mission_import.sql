@@ -0,0 +1,7 @@
+-- Bulk mission import: stable external key so re-imports update instead of duplicating
+ALTER TABLE missions
+    ADD COLUMN IF NOT EXISTS external_key VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_missions_external_key
+    ON missions (external_key)
+    WHERE external_key IS NOT NULL;
@@ -0,0 +1,31 @@
+-- Localisation: per-locale names and descriptions for missions, badges and store items.
+-- The original columns stay the source text used when no translation exists.
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+CREATE TABLE IF NOT EXISTS mission_translations (
+    mission_id  BIGINT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    locale      VARCHAR(8) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (mission_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS badge_translations (
+    badge_id    BIGINT NOT NULL REFERENCES badges(id) ON DELETE CASCADE,
+    locale      VARCHAR(8) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (badge_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS store_item_translations (
+    store_item_id BIGINT NOT NULL REFERENCES store_items(id) ON DELETE CASCADE,
+    locale        VARCHAR(8) NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (store_item_id, locale)
+);
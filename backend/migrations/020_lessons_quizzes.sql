@@ -0,0 +1,47 @@
+-- Educational micro-lessons with multiple-choice quizzes. A lesson can be linked to a
+-- quiz mission; passing its quiz counts towards that mission.
+CREATE TABLE IF NOT EXISTS lessons (
+    id               BIGSERIAL PRIMARY KEY,
+    mission_id       BIGINT REFERENCES missions(id) ON DELETE SET NULL,
+    title            VARCHAR(255) NOT NULL,
+    content          TEXT NOT NULL DEFAULT '',
+    pass_score       INT NOT NULL DEFAULT 70 CHECK (pass_score BETWEEN 1 AND 100),
+    max_attempts     INT NOT NULL DEFAULT 0 CHECK (max_attempts >= 0),
+    cooldown_minutes INT NOT NULL DEFAULT 0 CHECK (cooldown_minutes >= 0),
+    published        BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_lessons_mission ON lessons (mission_id) WHERE mission_id IS NOT NULL;
+
+-- options is a JSON array of answer texts; correct_option is its zero-based index
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id             BIGSERIAL PRIMARY KEY,
+    lesson_id      BIGINT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    position       INT NOT NULL,
+    prompt         TEXT NOT NULL,
+    options        JSONB NOT NULL,
+    correct_option INT NOT NULL,
+    explanation    TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_lesson ON quiz_questions (lesson_id, position);
+
+-- One row per scored submission. mission_id is the lesson's mission at submission time.
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id           BIGSERIAL PRIMARY KEY,
+    lesson_id    BIGINT NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mission_id   BIGINT REFERENCES missions(id) ON DELETE SET NULL,
+    answers      JSONB NOT NULL,
+    correct      INT NOT NULL,
+    total        INT NOT NULL,
+    score        DOUBLE PRECISION NOT NULL,
+    passed       BOOLEAN NOT NULL,
+    period_start TIMESTAMPTZ,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_lesson ON quiz_attempts (user_id, lesson_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_mission ON quiz_attempts (mission_id, user_id) WHERE passed;
@@ -0,0 +1,18 @@
+-- Daily check-ins. One row per user per calendar day in the user's streak timezone;
+-- streak_day counts consecutive check-ins and drives the 7-day reward cycle.
+-- rewarded_at is set once the points have been credited, so a failed credit can be
+-- retried by checking in again on the same day.
+CREATE TABLE IF NOT EXISTS daily_checkins (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    local_date    DATE NOT NULL,
+    streak_day    INT NOT NULL CHECK (streak_day >= 1),
+    cycle_day     INT NOT NULL CHECK (cycle_day BETWEEN 1 AND 7),
+    reward_points INT NOT NULL DEFAULT 0 CHECK (reward_points >= 0),
+    bonus_points  INT NOT NULL DEFAULT 0 CHECK (bonus_points >= 0),
+    rewarded_at   TIMESTAMP,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, local_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_checkins_user_date ON daily_checkins (user_id, local_date DESC);
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type DietType string
+
+const (
+	DietVegan       DietType = "vegan"
+	DietVegetarian  DietType = "vegetarian"
+	DietPescatarian DietType = "pescatarian"
+	DietLowMeat     DietType = "low_meat"
+	DietMediumMeat  DietType = "medium_meat"
+	DietHighMeat    DietType = "high_meat"
+)
+
+// ApplianceUsage jawaban kuesioner untuk satu peralatan rumah
+type ApplianceUsage struct {
+	DeviceType  string  `json:"device_type"`
+	PowerWatts  int     `json:"power_watts"`
+	HoursPerDay float64 `json:"hours_per_day"`
+}
+
+// CarbonBaseline estimasi jejak karbon tahunan (kg CO2e) dari kuesioner onboarding.
+// Setiap pengisian ulang disimpan sebagai baris baru.
+type CarbonBaseline struct {
+	ID                  int64            `db:"id" json:"id"`
+	UserID              int64            `db:"user_id" json:"user_id"`
+	CommuteMode         VehicleType      `db:"commute_mode" json:"commute_mode"`
+	FuelType            FuelType         `db:"fuel_type" json:"fuel_type"`
+	CommuteKmPerDay     float64          `db:"commute_km_per_day" json:"commute_km_per_day"`
+	CommuteDaysPerWeek  int              `db:"commute_days_per_week" json:"commute_days_per_week"`
+	HouseholdSize       int              `db:"household_size" json:"household_size"`
+	Appliances          []ApplianceUsage `db:"appliances" json:"appliances"`
+	Diet                DietType         `db:"diet" json:"diet"`
+	TransportEmission   float64          `db:"transport_emission_g" json:"transport_emission_g"`
+	ElectricityEmission float64          `db:"electricity_emission_g" json:"electricity_emission_g"`
+	DietEmission        float64          `db:"diet_emission_g" json:"diet_emission_g"`
+	TotalEmission       float64          `db:"total_emission_g" json:"total_emission_g"`
+	CreatedAt           time.Time        `db:"created_at" json:"created_at"`
+}
+
+// TrackedEmission bagian baseline yang juga bisa dicatat lewat log karbon
+func (b *CarbonBaseline) TrackedEmission() float64 {
+	return b.TransportEmission + b.ElectricityEmission
+}
+
+func (CarbonBaseline) TableName() string {
+	return "carbon_baselines"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type CabinClass string
+
+const (
+	CabinEconomy        CabinClass = "economy"
+	CabinPremiumEconomy CabinClass = "premium_economy"
+	CabinBusiness       CabinClass = "business"
+	CabinFirst          CabinClass = "first"
+)
+
+type CarbonFlightLog struct {
+	ID             int64      `db:"id" json:"id"`
+	UserID         int64      `db:"user_id" json:"user_id"`
+	Origin         string     `db:"origin" json:"origin"`
+	Destination    string     `db:"destination" json:"destination"`
+	CabinClass     CabinClass `db:"cabin_class" json:"cabin_class"`
+	RoundTrip      bool       `db:"round_trip" json:"round_trip"`
+	DistanceKm     float64    `db:"distance_km" json:"distance_km"`
+	CarbonEmission float64    `db:"carbon_emission_g" json:"carbon_emission_g"`
+	LoggedAt       time.Time  `db:"logged_at" json:"logged_at"`
+}
+
+func (CarbonFlightLog) TableName() string {
+	return "carbon_flight_logs"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type MeterReadingType string
+
+const (
+	// Meter pascabayar: reading_kwh adalah angka kumulatif di meteran
+	MeterReadingPostpaid MeterReadingType = "meter"
+	// Token prabayar: reading_kwh adalah sisa kWh sebelum isi ulang, token_kwh adalah kWh yang dibeli
+	MeterReadingToken MeterReadingType = "token"
+)
+
+type CarbonMeterReading struct {
+	ID             int64            `db:"id" json:"id"`
+	UserID         int64            `db:"user_id" json:"user_id"`
+	ReadingType    MeterReadingType `db:"reading_type" json:"reading_type"`
+	ReadingKwh     float64          `db:"reading_kwh" json:"reading_kwh"`
+	TokenKwh       float64          `db:"token_kwh" json:"token_kwh"`
+	TokenPrice     float64          `db:"token_price" json:"token_price"`
+	ConsumptionKwh float64          `db:"consumption_kwh" json:"consumption_kwh"`
+	CarbonEmission float64          `db:"carbon_emission_g" json:"carbon_emission_g"`
+	ReadAt         time.Time        `db:"read_at" json:"read_at"`
+}
+
+func (CarbonMeterReading) TableName() string {
+	return "carbon_meter_readings"
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type WasteStream string
+type DisposalRoute string
+
+const (
+	WasteOrganic  WasteStream = "organic"
+	WastePlastic  WasteStream = "plastic"
+	WastePaper    WasteStream = "paper"
+	WasteResidual WasteStream = "residual"
+)
+
+const (
+	DisposalLandfill   DisposalRoute = "landfill"
+	DisposalRecycled   DisposalRoute = "recycled"
+	DisposalComposted  DisposalRoute = "composted"
+	DisposalBankSampah DisposalRoute = "bank_sampah"
+)
+
+// IsDiverted true kalau sampah tidak berakhir di TPA
+func (d DisposalRoute) IsDiverted() bool {
+	return d == DisposalRecycled || d == DisposalComposted || d == DisposalBankSampah
+}
+
+type CarbonWasteLog struct {
+	ID              int64         `db:"id" json:"id"`
+	UserID          int64         `db:"user_id" json:"user_id"`
+	WasteStream     WasteStream   `db:"waste_stream" json:"waste_stream"`
+	DisposalRoute   DisposalRoute `db:"disposal_route" json:"disposal_route"`
+	WeightKg        float64       `db:"weight_kg" json:"weight_kg"`
+	CarbonEmission  float64       `db:"carbon_emission_g" json:"carbon_emission_g"`
+	AvoidedEmission float64       `db:"avoided_emission_g" json:"avoided_emission_g"`
+	LoggedAt        time.Time     `db:"logged_at" json:"logged_at"`
+}
+
+// Ringkasan per jalur pembuangan
+type WasteRouteSummary struct {
+	DisposalRoute DisposalRoute `json:"disposal_route"`
+	TotalLogs     int           `json:"total_logs"`
+	TotalWeightKg float64       `json:"total_weight_kg"`
+	TotalEmission float64       `json:"total_carbon_emission_g"`
+	TotalAvoided  float64       `json:"total_avoided_emission_g"`
+}
+
+func (CarbonWasteLog) TableName() string {
+	return "carbon_waste_logs"
+}
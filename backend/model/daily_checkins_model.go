@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CheckInRewards poin check-in untuk hari ke-1 sampai ke-7 dalam satu siklus
+var CheckInRewards = [7]int{5, 10, 15, 20, 25, 30, 40}
+
+const (
+	// CheckInWeeklyBonus bonus tambahan setiap menuntaskan satu siklus 7 hari berturut-turut
+	CheckInWeeklyBonus = 50
+	// CheckInPointSource source di point_transactions untuk hadiah check-in
+	CheckInPointSource = "daily_checkin"
+)
+
+// DailyCheckIn check-in harian user, satu per tanggal kalender di zona waktu streak
+type DailyCheckIn struct {
+	ID           int64        `db:"id" json:"id"`
+	UserID       int64        `db:"user_id" json:"user_id"`
+	LocalDate    time.Time    `db:"local_date" json:"local_date"`
+	StreakDay    int          `db:"streak_day" json:"streak_day"` // check-in berturut-turut ke-berapa
+	CycleDay     int          `db:"cycle_day" json:"cycle_day"`
+	RewardPoints int          `db:"reward_points" json:"reward_points"`
+	BonusPoints  int          `db:"bonus_points" json:"bonus_points"`
+	RewardedAt   sql.NullTime `db:"rewarded_at" json:"rewarded_at"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+}
+
+func (DailyCheckIn) TableName() string {
+	return "daily_checkins"
+}
+
+// CheckInCycleDay posisi streakDay di siklus hadiah (1..7)
+func CheckInCycleDay(streakDay int) int {
+	return (streakDay-1)%len(CheckInRewards) + 1
+}
+
+// CheckInReward hadiah dan bonus untuk check-in berturut-turut ke-streakDay
+func CheckInReward(streakDay int) (reward, bonus int) {
+	cycleDay := CheckInCycleDay(streakDay)
+	reward = CheckInRewards[cycleDay-1]
+	if cycleDay == len(CheckInRewards) {
+		bonus = CheckInWeeklyBonus
+	}
+	return reward, bonus
+}
+
+// NewDailyCheckIn check-in untuk tanggal day. previous adalah check-in terakhir user
+// (boleh nil); streak berlanjut hanya kalau check-in terakhir tepat kemarin.
+func NewDailyCheckIn(userID int64, day time.Time, previous *DailyCheckIn, now time.Time) *DailyCheckIn {
+	streakDay := 1
+	if previous != nil && daysBetween(previous.LocalDate, day) == 1 {
+		streakDay = previous.StreakDay + 1
+	}
+
+	reward, bonus := CheckInReward(streakDay)
+	return &DailyCheckIn{
+		UserID:       userID,
+		LocalDate:    day,
+		StreakDay:    streakDay,
+		CycleDay:     CheckInCycleDay(streakDay),
+		RewardPoints: reward,
+		BonusPoints:  bonus,
+		CreatedAt:    now,
+	}
+}
+
+// TotalPoints hadiah harian ditambah bonus mingguan
+func (c *DailyCheckIn) TotalPoints() int {
+	return c.RewardPoints + c.BonusPoints
+}
+
+// RewardKey idempotency_key hadiah check-in di point_transactions, satu per user per tanggal
+func (c *DailyCheckIn) RewardKey() string {
+	return fmt.Sprintf("checkin:%d:%s", c.UserID, c.LocalDate.Format("2006-01-02"))
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Lesson materi edukasi singkat dengan kuis pilihan ganda
+type Lesson struct {
+	ID              int64           `json:"id"`
+	MissionID       sql.NullInt64   `json:"mission_id"`
+	Title           string          `json:"title"`
+	Content         string          `json:"content"`
+	PassScore       int             `json:"pass_score"` // persen jawaban benar minimal untuk lulus
+	MaxAttempts     int             `json:"max_attempts"`
+	CooldownMinutes int             `json:"cooldown_minutes"`
+	Published       bool            `json:"published"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	Questions       []*QuizQuestion `json:"questions,omitempty"`
+	// Diisi juga saat daftar lesson dimuat tanpa soal
+	QuestionCount int `json:"question_count"`
+}
+
+type QuizQuestion struct {
+	ID            int64    `json:"id"`
+	LessonID      int64    `json:"lesson_id"`
+	Position      int      `json:"position"`
+	Prompt        string   `json:"prompt"`
+	Options       []string `json:"options"`
+	CorrectOption int      `json:"correct_option"`
+	Explanation   string   `json:"explanation"`
+}
+
+// QuizAnswer jawaban user untuk satu soal, Option indeks pilihan mulai dari 0
+type QuizAnswer struct {
+	QuestionID int64 `json:"question_id"`
+	Option     int   `json:"option"`
+}
+
+type QuizAttempt struct {
+	ID          int64         `json:"id"`
+	LessonID    int64         `json:"lesson_id"`
+	UserID      int64         `json:"user_id"`
+	MissionID   sql.NullInt64 `json:"mission_id"`
+	Answers     []QuizAnswer  `json:"answers"`
+	Correct     int           `json:"correct"`
+	Total       int           `json:"total"`
+	Score       float64       `json:"score"`
+	Passed      bool          `json:"passed"`
+	PeriodStart sql.NullTime  `json:"period_start"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// QuizAttemptStats ringkasan percobaan user untuk satu kuis di periode berjalan
+type QuizAttemptStats struct {
+	Attempts      int
+	Passed        bool
+	LastAttemptAt sql.NullTime
+}
+
+// AttemptsLeft sisa percobaan di periode berjalan, -1 kalau tidak dibatasi
+func (l *Lesson) AttemptsLeft(stats *QuizAttemptStats) int {
+	if l.MaxAttempts == 0 {
+		return -1
+	}
+	if left := l.MaxAttempts - stats.Attempts; left > 0 {
+		return left
+	}
+	return 0
+}
+
+// CooldownUntil akhir jeda setelah percobaan terakhir; false kalau tidak sedang jeda
+func (l *Lesson) CooldownUntil(stats *QuizAttemptStats, now time.Time) (time.Time, bool) {
+	if l.CooldownMinutes == 0 || !stats.LastAttemptAt.Valid {
+		return time.Time{}, false
+	}
+	until := stats.LastAttemptAt.Time.Add(time.Duration(l.CooldownMinutes) * time.Minute)
+	return until, until.After(now)
+}
+
+// QuizAttemptsSince awal periode penghitungan percobaan kuis: periode berjalan misi berulang
+// yang terhubung, nil (seluruh riwayat) untuk lainnya
+func QuizAttemptsSince(mission *Mission, now time.Time) *time.Time {
+	if mission == nil {
+		return nil
+	}
+	if period, ok := mission.Period(now); ok {
+		return &period.Start
+	}
+	return nil
+}
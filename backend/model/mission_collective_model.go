@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type MissionScope string
+
+const (
+	MissionScopeIndividual MissionScope = "individual"
+	// Target dicapai gabungan progres anggota satu tim/kelas
+	MissionScopeTeam MissionScope = "team"
+	// Target dicapai gabungan progres seluruh user
+	MissionScopeCommunity MissionScope = "community"
+)
+
+func (m *Mission) IsCollective() bool {
+	return m.Scope == MissionScopeTeam || m.Scope == MissionScopeCommunity
+}
+
+// MissionContribution kontribusi satu user ke progres kolektif. TeamID kosong untuk misi komunitas.
+type MissionContribution struct {
+	MissionID    int64         `db:"mission_id" json:"mission_id"`
+	TeamID       sql.NullInt64 `db:"team_id" json:"team_id"`
+	UserID       int64         `db:"user_id" json:"user_id"`
+	Username     string        `db:"-" json:"username"`
+	Contribution float64       `db:"contribution" json:"contribution"`
+	UpdatedAt    time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// CollectiveProgress total progres satu grup (tim atau komunitas) untuk satu misi
+type CollectiveProgress struct {
+	ID            int64         `db:"id" json:"id"`
+	MissionID     int64         `db:"mission_id" json:"mission_id"`
+	TeamID        sql.NullInt64 `db:"team_id" json:"team_id"`
+	ProgressValue float64       `db:"progress_value" json:"progress_value"`
+	CompletedAt   sql.NullTime  `db:"completed_at" json:"completed_at"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+func (MissionContribution) TableName() string {
+	return "mission_contributions"
+}
+
+func (CollectiveProgress) TableName() string {
+	return "mission_collective_progress"
+}
+
+// CollectiveStanding posisi satu grup yang diikuti user: total progres dan kontributor teratas
+type CollectiveStanding struct {
+	Progress       CollectiveProgress
+	TeamName       string
+	MyContribution float64
+	Contributors   []*MissionContribution
+}
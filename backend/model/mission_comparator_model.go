@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// MissionComparator cara membandingkan progres dengan target
+type MissionComparator string
+
+const (
+	// Selesai begitu progres >= target (perilaku lama)
+	ComparatorAtLeast MissionComparator = "gte"
+	// Progres harus tetap <= target sampai window ditutup
+	ComparatorAtMost MissionComparator = "lte"
+	// Progres harus berada di antara target_value dan target_max saat window ditutup
+	ComparatorBetween MissionComparator = "between"
+)
+
+// Meets true kalau progres memenuhi target sesuai comparator
+func (m *Mission) Meets(progress float64) bool {
+	switch m.Comparator {
+	case ComparatorAtMost:
+		return progress <= m.TargetValue
+	case ComparatorBetween:
+		return progress >= m.TargetValue && m.TargetMax.Valid && progress <= m.TargetMax.Float64
+	}
+	return progress >= m.TargetValue
+}
+
+// SettlesAtWindowEnd misi dengan batas atas baru bisa dinilai setelah window-nya ditutup,
+// karena progres masih bisa naik melewati batas sebelum itu
+func (m *Mission) SettlesAtWindowEnd() bool {
+	return m.Comparator == ComparatorAtMost || m.Comparator == ComparatorBetween
+}
+
+// RequiredActivityLogs minimal satu log, supaya user yang tidak mencatat apa pun
+// tidak otomatis lolos misi "di bawah target"
+func (m *Mission) RequiredActivityLogs() int {
+	if m.MinActivityLogs < 1 {
+		return 1
+	}
+	return m.MinActivityLogs
+}
+
+// ClosedWindow window terakhir yang sudah ditutup pada waktu at. Misi berulang memakai
+// periode sebelum periode berjalan, misi biasa memakai expired_at. Start selalu berada
+// di dalam window, jadi bisa dipakai sebagai waktu evaluasi untuk Window dan Period.
+func (m *Mission) ClosedWindow(at time.Time) (MissionPeriod, bool) {
+	if current, ok := m.Period(at); ok {
+		return m.Period(current.Start.Add(-time.Nanosecond))
+	}
+	if m.ExpiredAt.Valid && !at.Before(m.ExpiredAt.Time) {
+		start := m.CreatedAt
+		if m.StartsAt.Valid {
+			start = m.StartsAt.Time
+		}
+		return MissionPeriod{Start: start, End: m.ExpiredAt.Time}, true
+	}
+	return MissionPeriod{}, false
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type MissionEventType string
+
+const (
+	EventCarbonLogged    MissionEventType = "carbon_logged"
+	EventBaselineUpdated MissionEventType = "baseline_updated"
+	EventUserLoggedIn    MissionEventType = "user_logged_in"
+	EventOrderPlaced     MissionEventType = "order_placed"
+	EventPointsEarned    MissionEventType = "points_earned"
+	EventProofApproved   MissionEventType = "proof_approved"
+	EventQuizPassed      MissionEventType = "quiz_passed"
+	EventDailyCheckIn    MissionEventType = "daily_check_in"
+)
+
+// MissionEvent kejadian domain yang bisa memengaruhi progres misi user
+type MissionEvent struct {
+	Type       MissionEventType
+	UserID     int64
+	Category   string              // kategori karbon (untuk carbon_logged)
+	Criteria   MissionCriteriaType // sub-jenis aktivitas, mis. car, fridge, waste_plastic
+	Emission   float64             // emisi yang dicatat (kg CO2e)
+	Quantity   float64             // km, jam, kg, ... sesuai kategori
+	Points     float64             // poin yang masuk (untuk points_earned)
+	SourceType string              // tabel asal event, mis. carbon_vehicle_logs (untuk riwayat progres)
+	SourceID   int64               // id baris di SourceType, 0 kalau tidak ada
+	OccurredAt time.Time           // waktu event dicatat
+	ActivityAt time.Time           // waktu aktivitas terjadi (bisa backdate), kosong berarti OccurredAt
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// MissionJobRun satu kali eksekusi job scheduler misi
+type MissionJobRun struct {
+	ID         int64          `json:"id"`
+	Job        string         `json:"job"`
+	Instance   string         `json:"instance"`
+	Status     JobRunStatus   `json:"status"`
+	Affected   int64          `json:"affected"`
+	Error      sql.NullString `json:"error"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt sql.NullTime   `json:"finished_at"`
+}
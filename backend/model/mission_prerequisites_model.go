@@ -0,0 +1,43 @@
+package models
+
+type PrerequisiteMode string
+
+const (
+	// Semua misi prasyarat harus selesai
+	PrerequisiteAll PrerequisiteMode = "all"
+	// Cukup salah satu misi prasyarat selesai
+	PrerequisiteAny PrerequisiteMode = "any"
+)
+
+// MissionPrerequisite relasi misi dengan misi yang harus diselesaikan lebih dulu
+type MissionPrerequisite struct {
+	MissionID      int64 `db:"mission_id" json:"mission_id"`
+	PrerequisiteID int64 `db:"prerequisite_id" json:"prerequisite_id"`
+}
+
+func (MissionPrerequisite) TableName() string {
+	return "mission_prerequisites"
+}
+
+// PrerequisitesMet cek prasyarat terhadap kumpulan misi yang sudah diselesaikan user
+func (m *Mission) PrerequisitesMet(completed map[int64]bool) bool {
+	if len(m.Prerequisites) == 0 {
+		return true
+	}
+
+	if m.PrerequisiteMode == PrerequisiteAny {
+		for _, id := range m.Prerequisites {
+			if completed[id] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, id := range m.Prerequisites {
+		if !completed[id] {
+			return false
+		}
+	}
+	return true
+}
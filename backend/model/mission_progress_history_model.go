@@ -0,0 +1,31 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProgressTrigger penyebab progres misi berubah
+type ProgressTrigger string
+
+const (
+	// Progres berubah karena event domain (log karbon, login, order, poin)
+	ProgressTriggerEvent ProgressTrigger = "event"
+	// Progres dihitung ulang penuh (cek manual, fallback, atau periode baru)
+	ProgressTriggerRecompute ProgressTrigger = "recompute"
+)
+
+// MissionProgressSnapshot satu titik di riwayat progres misi user
+type MissionProgressSnapshot struct {
+	ID            int64           `json:"id"`
+	UserID        int64           `json:"user_id"`
+	MissionID     int64           `json:"mission_id"`
+	ProgressValue float64         `json:"progress_value"`
+	Delta         float64         `json:"delta"`
+	Trigger       ProgressTrigger `json:"trigger"`
+	EventType     sql.NullString  `json:"event_type"`
+	SourceType    sql.NullString  `json:"source_type"`
+	SourceID      sql.NullInt64   `json:"source_id"`
+	PeriodStart   sql.NullTime    `json:"period_start"`
+	RecordedAt    time.Time       `json:"recorded_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProofStatus string
+
+const (
+	ProofPending  ProofStatus = "pending"
+	ProofApproved ProofStatus = "approved"
+	ProofRejected ProofStatus = "rejected"
+)
+
+// MissionProofSubmission bukti foto untuk misi proof yang menunggu atau sudah direview admin
+type MissionProofSubmission struct {
+	ID          int64         `json:"id"`
+	MissionID   int64         `json:"mission_id"`
+	UserID      int64         `json:"user_id"`
+	PhotoURL    string        `json:"photo_url"`
+	Note        string        `json:"note"`
+	Status      ProofStatus   `json:"status"`
+	ReviewNote  string        `json:"review_note"`
+	ReviewedBy  sql.NullInt64 `json:"reviewed_by"`
+	ReviewedAt  sql.NullTime  `json:"reviewed_at"`
+	PeriodStart sql.NullTime  `json:"period_start"`
+	CreatedAt   time.Time     `json:"created_at"`
+	// Diisi untuk antrean moderasi
+	MissionTitle string `json:"mission_title,omitempty"`
+	Username     string `json:"username,omitempty"`
+}
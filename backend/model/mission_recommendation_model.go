@@ -0,0 +1,10 @@
+package models
+
+// RecommendationProfile sinyal aktivitas user untuk merekomendasikan misi
+type RecommendationProfile struct {
+	// Criteria yang relevan untuk user: jenis kendaraan/perangkat yang dimiliki,
+	// jenis sampah yang pernah dicatat, flight kalau pernah terbang
+	OwnedCriteria map[MissionCriteriaType]bool
+	// Jumlah misi yang pernah diselesaikan per tipe misi
+	CompletedByType map[MissionType]int
+}
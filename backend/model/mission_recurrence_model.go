@@ -0,0 +1,220 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Recurrence string
+
+const (
+	RecurrenceNone    Recurrence = "none"
+	RecurrenceDaily   Recurrence = "daily"
+	RecurrenceWeekly  Recurrence = "weekly"
+	RecurrenceMonthly Recurrence = "monthly"
+	// Periode ditentukan ekspresi cron 5 kolom di recurrence_rule
+	RecurrenceCustom Recurrence = "custom"
+)
+
+// Batas periode misi berulang dihitung di zona waktu ini
+const DefaultMissionTimezone = "Asia/Jakarta"
+
+func missionLocation() *time.Location {
+	loc, err := time.LoadLocation(DefaultMissionTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// MissionPeriod satu periode misi berulang, [Start, End)
+type MissionPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (m *Mission) IsRecurring() bool {
+	return m.Recurrence != "" && m.Recurrence != RecurrenceNone
+}
+
+// Period periode yang memuat waktu at. ok false untuk misi sekali jalan
+// atau aturan cron yang tidak valid.
+func (m *Mission) Period(at time.Time) (MissionPeriod, bool) {
+	loc := missionLocation()
+	t := at.In(loc)
+	y, mo, d := t.Date()
+
+	switch m.Recurrence {
+	case RecurrenceDaily:
+		start := time.Date(y, mo, d, 0, 0, 0, 0, loc)
+		return MissionPeriod{Start: start, End: start.AddDate(0, 0, 1)}, true
+	case RecurrenceWeekly:
+		// Minggu dimulai hari Senin
+		offset := (int(t.Weekday()) + 6) % 7
+		start := time.Date(y, mo, d-offset, 0, 0, 0, 0, loc)
+		return MissionPeriod{Start: start, End: start.AddDate(0, 0, 7)}, true
+	case RecurrenceMonthly:
+		start := time.Date(y, mo, 1, 0, 0, 0, 0, loc)
+		return MissionPeriod{Start: start, End: start.AddDate(0, 1, 0)}, true
+	case RecurrenceCustom:
+		schedule, err := ParseCron(m.RecurrenceRule)
+		if err != nil {
+			return MissionPeriod{}, false
+		}
+		start, ok := schedule.Prev(t)
+		if !ok {
+			return MissionPeriod{}, false
+		}
+		end, ok := schedule.Next(t)
+		if !ok {
+			return MissionPeriod{}, false
+		}
+		return MissionPeriod{Start: start, End: end}, true
+	}
+	return MissionPeriod{}, false
+}
+
+// =========================
+// Cron (menit jam tanggal bulan hari)
+// =========================
+
+// CronSchedule ekspresi cron standar 5 kolom. Mendukung *, angka, daftar (1,15),
+// rentang (1-5) dan step (*/2, 1-10/3).
+type CronSchedule struct {
+	minute, hour, dom, month, dow []bool
+	domAny, dowAny                bool
+}
+
+// Pencarian jadwal dibatasi supaya aturan mustahil (mis. 30 Februari) tidak berputar terus
+const cronSearchLimit = 5 * 366 * 24 * 60
+
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.New("recurrence_rule harus berisi 5 kolom cron: menit jam tanggal bulan hari")
+	}
+
+	s := &CronSchedule{}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// 7 juga berarti Minggu
+	s.dow[0] = s.dow[0] || s.dow[7]
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("step cron tidak valid: %s", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("nilai cron tidak valid: %s", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("nilai cron tidak valid: %s", part)
+				}
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("nilai cron di luar rentang %d-%d: %s", min, max, part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	// Sama seperti cron: kalau tanggal dan hari sama-sama dibatasi, salah satu cukup
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// Prev jadwal terakhir yang <= t
+func (s *CronSchedule) Prev(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute)
+
+	for i := 0; i < cronSearchLimit; i++ {
+		y, mo, d := t.Date()
+		switch {
+		case !s.month[int(mo)]:
+			t = time.Date(y, mo, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.dayMatches(t):
+			t = time.Date(y, mo, d, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.hour[t.Hour()]:
+			t = time.Date(y, mo, d, t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case !s.minute[t.Minute()]:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Next jadwal pertama yang > t
+func (s *CronSchedule) Next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for i := 0; i < cronSearchLimit; i++ {
+		y, mo, d := t.Date()
+		switch {
+		case !s.month[int(mo)]:
+			t = time.Date(y, mo+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(y, mo, d+1, 0, 0, 0, 0, loc)
+		case !s.hour[t.Hour()]:
+			t = time.Date(y, mo, d, t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -13,6 +13,10 @@ const (
 	MissionTypeStreak          MissionType = "streak"
 	MissionTypeActivity        MissionType = "activity"
 	MissionTypeCustom          MissionType = "custom"
+	// Diselesaikan lewat bukti foto yang disetujui admin
+	MissionTypeProof           MissionType = "proof"
+	// Diselesaikan dengan lulus kuis lesson yang terhubung
+	MissionTypeQuiz            MissionType = "quiz"
 )
 
 type MissionCriteriaType string
@@ -34,8 +38,115 @@ const (
 	CriteriaFan            MissionCriteriaType = "fan"
 	CriteriaWashingMachine MissionCriteriaType = "washing_machine"
 	CriteriaOther          MissionCriteriaType = "other"
+
+	// Waste (custom: kg yang didaur ulang/dikompos bulan ini)
+	CriteriaWasteOrganic  MissionCriteriaType = "waste_organic"
+	CriteriaWastePlastic  MissionCriteriaType = "waste_plastic"
+	CriteriaWastePaper    MissionCriteriaType = "waste_paper"
+	CriteriaWasteResidual MissionCriteriaType = "waste_residual"
+
+	// Flight (custom: total jarak terbang dalam km)
+	CriteriaFlight MissionCriteriaType = "flight"
+
+	// Baseline (carbon_reduction: persen penurunan emisi bulan ini terhadap baseline onboarding)
+	CriteriaBaselineReduction MissionCriteriaType = "baseline_reduction"
+)
+
+// WasteStream mengembalikan jenis sampah untuk criteria waste_*
+func (c MissionCriteriaType) WasteStream() (WasteStream, bool) {
+	switch c {
+	case CriteriaWasteOrganic:
+		return WasteOrganic, true
+	case CriteriaWastePlastic:
+		return WastePlastic, true
+	case CriteriaWastePaper:
+		return WastePaper, true
+	case CriteriaWasteResidual:
+		return WasteResidual, true
+	}
+	return "", false
+}
+
+// Criteria mengembalikan criteria waste_* untuk jenis sampah
+func (s WasteStream) Criteria() MissionCriteriaType {
+	return MissionCriteriaType("waste_" + string(s))
+}
+
+// ProgressWindow menentukan rentang aktivitas yang dihitung sebagai progres misi
+type ProgressWindow string
+
+const (
+	// Seluruh riwayat user (perilaku lama)
+	ProgressWindowLifetime ProgressWindow = "lifetime"
+	// Dari starts_at (atau created_at) misi sampai expired_at
+	ProgressWindowMission ProgressWindow = "mission"
+	// Dari enrollment user (tidak lebih awal dari starts_at) sampai expired_at
+	ProgressWindowEnrollment ProgressWindow = "enrollment"
 )
 
+// Window mengembalikan batas bawah dan atas aktivitas yang dihitung pada waktu at.
+// Nil berarti tak terbatas. Misi berulang dibatasi lagi ke periode yang sedang berjalan.
+func (m *Mission) Window(enrolledAt, at time.Time) (*time.Time, *time.Time) {
+	from, to := m.baseWindow(enrolledAt)
+
+	period, ok := m.Period(at)
+	if !ok {
+		return from, to
+	}
+	if from == nil || from.Before(period.Start) {
+		from = &period.Start
+	}
+	if to == nil || to.After(period.End) {
+		to = &period.End
+	}
+	return from, to
+}
+
+// UsesEnrollmentWindow true kalau progres dihitung sejak user ikut misi
+func (m *Mission) UsesEnrollmentWindow() bool {
+	return m.ProgressWindow != ProgressWindowLifetime && m.ProgressWindow != ProgressWindowMission
+}
+
+// DryRunWindow window simulasi misi draft pada waktu at. Misi enrollment dihitung seolah
+// user sudah ikut sejak awal window (starts_at, atau awal periode untuk misi berulang);
+// kalau tidak, semua user baru ikut di at dan progresnya selalu 0.
+func (m *Mission) DryRunWindow(at time.Time) (*time.Time, *time.Time) {
+	if !m.UsesEnrollmentWindow() {
+		return m.Window(at, at)
+	}
+	from, to := m.Window(time.Time{}, at)
+	if from != nil && from.IsZero() {
+		from = nil
+	}
+	return from, to
+}
+
+func (m *Mission) baseWindow(enrolledAt time.Time) (*time.Time, *time.Time) {
+	var from, to *time.Time
+	if m.ExpiredAt.Valid {
+		to = &m.ExpiredAt.Time
+	}
+
+	start := m.CreatedAt
+	if m.StartsAt.Valid {
+		start = m.StartsAt.Time
+	}
+
+	switch m.ProgressWindow {
+	case ProgressWindowLifetime:
+		return nil, nil
+	case ProgressWindowMission:
+		from = &start
+	default:
+		from = &enrolledAt
+		if m.StartsAt.Valid && start.After(enrolledAt) {
+			from = &start
+		}
+	}
+
+	return from, to
+}
+
 type Mission struct {
 	ID               int64           `json:"id"`
 	Title            string          `json:"title"`
@@ -49,6 +160,26 @@ type Mission struct {
 	TargetValue      float64         `json:"target_value"`
 	ExpiredAt        sql.NullTime    `json:"expired_at"`
 	CreatedAt        time.Time       `json:"created_at"`
+	StartsAt         sql.NullTime    `json:"starts_at"`
+	ProgressWindow   ProgressWindow  `json:"progress_window"`
+	Recurrence       Recurrence      `json:"recurrence"`
+	RecurrenceRule   string          `json:"recurrence_rule"`
+	// Kalau false user harus join manual sebelum progres dihitung
+	AutoJoin         bool            `json:"auto_join"`
+	PrerequisiteMode PrerequisiteMode `json:"prerequisite_mode"`
+	Prerequisites    []int64         `json:"prerequisites,omitempty"` // diisi dari tabel mission_prerequisites
+	Scope            MissionScope    `json:"scope"`
+	// Kontribusi minimum anggota supaya ikut dapat hadiah misi kolektif
+	MinContribution  float64         `json:"min_contribution"`
+	Comparator       MissionComparator `json:"comparator"`
+	// Batas atas untuk comparator between; TargetValue jadi batas bawah
+	TargetMax        sql.NullFloat64 `json:"target_max"`
+	// Jumlah log minimum di window supaya misi "di bawah target" bisa selesai
+	MinActivityLogs  int             `json:"min_activity_logs"`
+	// Aturan JSON (package rule); kalau ada, progres dihitung dari aturan ini
+	Rule             sql.NullString  `json:"rule"`
+	// Misi yang diarsipkan disembunyikan dan tidak dievaluasi, riwayat user tetap ada
+	ArchivedAt       sql.NullTime    `json:"archived_at"`
 }
 
 // Membuat sql.NullInt64 dari int64
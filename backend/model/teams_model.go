@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type TeamKind string
+
+const (
+	TeamKindTeam  TeamKind = "team"
+	TeamKindClass TeamKind = "class"
+)
+
+type TeamRole string
+
+const (
+	TeamRoleOwner  TeamRole = "owner"
+	TeamRoleMember TeamRole = "member"
+)
+
+type Team struct {
+	ID        int64     `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Kind      TeamKind  `db:"kind" json:"kind"`
+	JoinCode  string    `db:"join_code" json:"join_code"`
+	CreatedBy int64     `db:"created_by" json:"created_by"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type TeamMember struct {
+	TeamID   int64     `db:"team_id" json:"team_id"`
+	UserID   int64     `db:"user_id" json:"user_id"`
+	Role     TeamRole  `db:"role" json:"role"`
+	JoinedAt time.Time `db:"joined_at" json:"joined_at"`
+}
+
+func (Team) TableName() string {
+	return "teams"
+}
+
+func (TeamMember) TableName() string {
+	return "team_members"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// TranslationEntity jenis konten yang bisa diterjemahkan
+type TranslationEntity string
+
+const (
+	TranslationMission   TranslationEntity = "missions"
+	TranslationBadge     TranslationEntity = "badges"
+	TranslationStoreItem TranslationEntity = "store-items"
+)
+
+// Translation nama (judul untuk misi) dan deskripsi satu konten dalam satu locale
+type Translation struct {
+	EntityID    int64     `json:"entity_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -5,11 +5,53 @@ import (
 	"time"
 )
 
+type UserMissionStatus string
+
+const (
+	// Belum diikuti; tidak disimpan di user_missions
+	UserMissionAvailable UserMissionStatus = "available"
+	UserMissionActive    UserMissionStatus = "active"
+	UserMissionCompleted UserMissionStatus = "completed"
+	// Misi berakhir saat user sudah punya progres tapi belum mencapai target
+	UserMissionFailed UserMissionStatus = "failed"
+	// Misi berakhir tanpa progres sama sekali
+	UserMissionExpired   UserMissionStatus = "expired"
+	UserMissionAbandoned UserMissionStatus = "abandoned"
+)
+
+type MissionJoinSource string
+
+const (
+	JoinedAuto   MissionJoinSource = "auto"
+	JoinedManual MissionJoinSource = "manual"
+)
+
+var userMissionTransitions = map[UserMissionStatus][]UserMissionStatus{
+	UserMissionAvailable: {UserMissionActive},
+	UserMissionActive:    {UserMissionCompleted, UserMissionFailed, UserMissionExpired, UserMissionAbandoned},
+	// Misi yang ditinggalkan boleh diikuti lagi, progres mulai dari awal
+	UserMissionAbandoned: {UserMissionActive},
+}
+
+// CanTransition cek apakah perpindahan status diizinkan state machine
+func (s UserMissionStatus) CanTransition(to UserMissionStatus) bool {
+	for _, next := range userMissionTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type UserMission struct {
-	ID          int64        `json:"id"`
-	UserID      int64        `json:"user_id"`
-	MissionID   int64        `json:"mission_id"`
-	CompletedAt sql.NullTime `json:"completed_at"`
-	CreatedAt   time.Time    `json:"created_at"`
-	Mission     *Mission     `json:"mission,omitempty"`
-}
\ No newline at end of file
+	ID              int64             `json:"id"`
+	UserID          int64             `json:"user_id"`
+	MissionID       int64             `json:"mission_id"`
+	Status          UserMissionStatus `json:"status"`
+	JoinedVia       MissionJoinSource `json:"joined_via"`
+	StatusChangedAt time.Time         `json:"status_changed_at"`
+	CompletedAt     sql.NullTime      `json:"completed_at"`
+	CreatedAt       time.Time         `json:"created_at"`
+	EnrolledAt      time.Time         `json:"enrolled_at"`
+	Mission         *Mission          `json:"mission,omitempty"`
+}
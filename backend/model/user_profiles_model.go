@@ -9,5 +9,6 @@ type UserProfile struct {
 	AvatarURL *string    `db:"avatar_url"`
 	Birthdate *time.Time `db:"birthdate"`
 	Gender    *string    `db:"gender"`
+	Language  *string    `db:"language"` // locale konten pilihan user (id/en)
 	CreatedAt time.Time  `db:"created_at"`
 }
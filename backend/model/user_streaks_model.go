@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+	_ "time/tzdata" // zona waktu tetap tersedia di server tanpa tzdata sistem
+)
+
+const (
+	DefaultStreakTimezone = "Asia/Jakarta"
+	// Setiap kelipatan hari streak ini user mendapat satu streak freeze
+	StreakFreezeMilestone = 7
+	MaxStreakFreezes      = 3
+)
+
+// UserStreak status streak login harian user
+type UserStreak struct {
+	UserID           int64        `db:"user_id" json:"user_id"`
+	Timezone         string       `db:"timezone" json:"timezone"`
+	CurrentStreak    int          `db:"current_streak" json:"current_streak"`
+	LongestStreak    int          `db:"longest_streak" json:"longest_streak"`
+	LastActiveDate   sql.NullTime `db:"last_active_date" json:"last_active_date"`
+	FreezesAvailable int          `db:"freezes_available" json:"freezes_available"`
+	FreezesUsed      int          `db:"freezes_used" json:"freezes_used"`
+	UpdatedAt        time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// LoginEvent kejadian login terstruktur, menggantikan pencarian teks di activity_logs
+type LoginEvent struct {
+	ID         int64     `db:"id" json:"id"`
+	UserID     int64     `db:"user_id" json:"user_id"`
+	Source     string    `db:"source" json:"source"`
+	LoggedInAt time.Time `db:"logged_in_at" json:"logged_in_at"`
+	LocalDate  time.Time `db:"local_date" json:"local_date"`
+}
+
+func (UserStreak) TableName() string {
+	return "user_streaks"
+}
+
+func (LoginEvent) TableName() string {
+	return "user_login_events"
+}
+
+// Location zona waktu user, fallback ke zona default kalau tidak valid
+func (s *UserStreak) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		return loc
+	}
+	loc, _ := time.LoadLocation(DefaultStreakTimezone)
+	return loc
+}
+
+// LocalDate tanggal kalender t di zona loc, dinormalisasi ke tengah malam UTC
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// RegisterActiveDay mencatat hari aktif dan mengembalikan jumlah freeze yang terpakai.
+// Hari yang terlewat ditutup freeze kalau jumlahnya cukup, kalau tidak streak mulai dari 1.
+func (s *UserStreak) RegisterActiveDay(day time.Time) int {
+	frozen := 0
+
+	switch {
+	case !s.LastActiveDate.Valid:
+		s.CurrentStreak = 1
+	default:
+		gap := daysBetween(s.LastActiveDate.Time, day)
+		if gap <= 0 {
+			// Hari yang sama, atau mundur karena ganti zona waktu
+			return 0
+		}
+
+		missed := gap - 1
+		if missed <= s.FreezesAvailable {
+			frozen = missed
+			s.FreezesAvailable -= missed
+			s.FreezesUsed += missed
+			s.CurrentStreak++
+		} else {
+			s.CurrentStreak = 1
+		}
+	}
+
+	if s.CurrentStreak%StreakFreezeMilestone == 0 && s.FreezesAvailable < MaxStreakFreezes {
+		s.FreezesAvailable++
+	}
+	if s.CurrentStreak > s.LongestStreak {
+		s.LongestStreak = s.CurrentStreak
+	}
+	s.LastActiveDate = NewNullTime(day)
+
+	return frozen
+}
+
+// CurrentAt streak efektif pada tanggal today; 0 kalau sudah putus dan freeze tidak cukup
+func (s *UserStreak) CurrentAt(today time.Time) int {
+	if !s.LastActiveDate.Valid {
+		return 0
+	}
+	missed := daysBetween(s.LastActiveDate.Time, today) - 1
+	if missed > s.FreezesAvailable {
+		return 0
+	}
+	return s.CurrentStreak
+}
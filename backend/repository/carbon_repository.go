@@ -4,6 +4,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	models "github.com/Qodarrz/fiber-app/model"
 )
@@ -35,6 +37,24 @@ type CarbonRepository interface {
 	DeleteElectronic(ctx context.Context, id int64) error
 	DeleteElectronicLogs(ctx context.Context, deviceID int64) error
 	GetAllElectronicLogsByUser(ctx context.Context, userID int64) ([]*models.CarbonElectronicLog, error)
+
+	// Waste
+	CreateWasteLog(ctx context.Context, log *models.CarbonWasteLog) error
+	GetAllWasteLogsByUser(ctx context.Context, userID int64) ([]*models.CarbonWasteLog, error)
+	GetWasteSummaryByUser(ctx context.Context, userID int64) ([]*models.WasteRouteSummary, error)
+
+	// Electricity meter / token PLN
+	CreateMeterReading(ctx context.Context, reading *models.CarbonMeterReading, prepare func(prev *models.CarbonMeterReading) error) error
+	ListMeterReadings(ctx context.Context, userID int64) ([]*models.CarbonMeterReading, error)
+	SumElectronicsKwhBetween(ctx context.Context, userID int64, from, to time.Time) (float64, error)
+
+	// Flight
+	CreateFlightLog(ctx context.Context, log *models.CarbonFlightLog) error
+	GetAllFlightLogsByUser(ctx context.Context, userID int64) ([]*models.CarbonFlightLog, error)
+
+	// Baseline (kuesioner onboarding)
+	CreateBaseline(ctx context.Context, baseline *models.CarbonBaseline) error
+	ListBaselinesByUser(ctx context.Context, userID int64) ([]*models.CarbonBaseline, error)
 }
 
 type carbonRepository struct {
@@ -161,15 +181,15 @@ func (r *carbonRepository) ListUserVehicles(ctx context.Context, userID int64) (
 
 
 func (r *carbonRepository) CreateVehicleLog(ctx context.Context, log *models.CarbonVehicleLog) error {
-	_, err := r.db.ExecContext(ctx, `
+	return r.db.QueryRowContext(ctx, `
 		INSERT INTO carbon_vehicle_logs 
-			(vehicle_id, start_lat, start_lon, end_lat, end_lon, distance_km, duration_minutes, carbon_emission_g) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			(vehicle_id, start_lat, start_lon, end_lat, end_lon, distance_km, duration_minutes, carbon_emission_g, logged_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
+		RETURNING id
 	`,
 		log.VehicleID, log.StartLat, log.StartLon, log.EndLat, log.EndLon,
-		log.DistanceKm, log.DurationMinutes, log.CarbonEmission,
-	)
-	return err
+		log.DistanceKm, log.DurationMinutes, log.CarbonEmission, nullableTime(log.LoggedAt),
+	).Scan(&log.ID)
 }
 
 func (r *carbonRepository) GetVehicleLogs(ctx context.Context, vehicleID int64) ([]*models.CarbonVehicleLog, error) {
@@ -263,9 +283,8 @@ func (r *carbonRepository) ListUserElectronics(ctx context.Context, userID int64
 }
 
 func (r *carbonRepository) CreateElectronicsLog(ctx context.Context, log *models.CarbonElectronicLog) error {
-	_, err := r.db.ExecContext(ctx, `INSERT INTO carbon_electronics_logs (device_id, duration_hours, carbon_emission_g) VALUES ($1, $2, $3)`,
-		log.DeviceID, log.DurationHours, log.CarbonEmission)
-	return err
+	return r.db.QueryRowContext(ctx, `INSERT INTO carbon_electronics_logs (device_id, duration_hours, carbon_emission_g, logged_at) VALUES ($1, $2, $3, COALESCE($4, NOW())) RETURNING id`,
+		log.DeviceID, log.DurationHours, log.CarbonEmission, nullableTime(log.LoggedAt)).Scan(&log.ID)
 }
 
 func (r *carbonRepository) GetElectronicsLogs(ctx context.Context, deviceID int64) ([]*models.CarbonElectronicLog, error) {
@@ -386,3 +405,276 @@ func (r *carbonRepository) GetAllElectronicLogsByUser(ctx context.Context, userI
 
 	return logs, nil
 }
+
+func (r *carbonRepository) CreateWasteLog(ctx context.Context, log *models.CarbonWasteLog) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO carbon_waste_logs
+			(user_id, waste_stream, disposal_route, weight_kg, carbon_emission_g, avoided_emission_g, logged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`,
+		log.UserID, log.WasteStream, log.DisposalRoute, log.WeightKg,
+		log.CarbonEmission, log.AvoidedEmission, log.LoggedAt,
+	).Scan(&log.ID)
+}
+
+func (r *carbonRepository) GetAllWasteLogsByUser(ctx context.Context, userID int64) ([]*models.CarbonWasteLog, error) {
+	query := `
+		SELECT id, user_id, waste_stream, disposal_route, weight_kg,
+		       carbon_emission_g, avoided_emission_g, logged_at
+		FROM carbon_waste_logs
+		WHERE user_id = $1
+		ORDER BY logged_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.CarbonWasteLog
+	for rows.Next() {
+		var log models.CarbonWasteLog
+		if err := rows.Scan(
+			&log.ID, &log.UserID, &log.WasteStream, &log.DisposalRoute, &log.WeightKg,
+			&log.CarbonEmission, &log.AvoidedEmission, &log.LoggedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, &log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (r *carbonRepository) GetWasteSummaryByUser(ctx context.Context, userID int64) ([]*models.WasteRouteSummary, error) {
+	query := `
+		SELECT disposal_route,
+		       COUNT(id) AS total_logs,
+		       COALESCE(SUM(weight_kg), 0) AS total_weight,
+		       COALESCE(SUM(carbon_emission_g), 0) AS total_emission,
+		       COALESCE(SUM(avoided_emission_g), 0) AS total_avoided
+		FROM carbon_waste_logs
+		WHERE user_id = $1
+		GROUP BY disposal_route
+		ORDER BY disposal_route
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summary []*models.WasteRouteSummary
+	for rows.Next() {
+		var s models.WasteRouteSummary
+		if err := rows.Scan(&s.DisposalRoute, &s.TotalLogs, &s.TotalWeightKg, &s.TotalEmission, &s.TotalAvoided); err != nil {
+			return nil, err
+		}
+		summary = append(summary, &s)
+	}
+
+	return summary, rows.Err()
+}
+
+// CreateMeterReading menyimpan pembacaan meter. prepare dipanggil dengan pembacaan terakhir
+// bertipe sama (nil kalau belum ada) untuk menghitung konsumsi; user dikunci selama itu supaya
+// dua pembacaan bersamaan tidak dihitung terhadap pembacaan sebelumnya yang sama.
+func (r *carbonRepository) CreateMeterReading(ctx context.Context, reading *models.CarbonMeterReading, prepare func(prev *models.CarbonMeterReading) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, reading.UserID); err != nil {
+		return err
+	}
+
+	prev, err := latestMeterReading(ctx, tx, reading.UserID, reading.ReadingType)
+	if err != nil {
+		return err
+	}
+	if err := prepare(prev); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO carbon_meter_readings
+			(user_id, reading_type, reading_kwh, token_kwh, token_price, consumption_kwh, carbon_emission_g, read_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`,
+		reading.UserID, reading.ReadingType, reading.ReadingKwh, reading.TokenKwh, reading.TokenPrice,
+		reading.ConsumptionKwh, reading.CarbonEmission, reading.ReadAt,
+	).Scan(&reading.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// latestMeterReading pembacaan terakhir user untuk satu tipe, nil kalau belum ada
+func latestMeterReading(ctx context.Context, q rowQuerier, userID int64, readingType models.MeterReadingType) (*models.CarbonMeterReading, error) {
+	var m models.CarbonMeterReading
+	err := q.QueryRowContext(ctx, `
+		SELECT id, user_id, reading_type, reading_kwh, token_kwh, token_price,
+		       consumption_kwh, carbon_emission_g, read_at
+		FROM carbon_meter_readings
+		WHERE user_id = $1 AND reading_type = $2
+		ORDER BY read_at DESC
+		LIMIT 1
+	`, userID, readingType).Scan(
+		&m.ID, &m.UserID, &m.ReadingType, &m.ReadingKwh, &m.TokenKwh, &m.TokenPrice,
+		&m.ConsumptionKwh, &m.CarbonEmission, &m.ReadAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *carbonRepository) ListMeterReadings(ctx context.Context, userID int64) ([]*models.CarbonMeterReading, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, reading_type, reading_kwh, token_kwh, token_price,
+		       consumption_kwh, carbon_emission_g, read_at
+		FROM carbon_meter_readings
+		WHERE user_id = $1
+		ORDER BY read_at ASC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []*models.CarbonMeterReading
+	for rows.Next() {
+		var m models.CarbonMeterReading
+		if err := rows.Scan(
+			&m.ID, &m.UserID, &m.ReadingType, &m.ReadingKwh, &m.TokenKwh, &m.TokenPrice,
+			&m.ConsumptionKwh, &m.CarbonEmission, &m.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		readings = append(readings, &m)
+	}
+
+	return readings, rows.Err()
+}
+
+// SumElectronicsKwhBetween total kWh dari log elektronik user dalam rentang [from, to)
+func (r *carbonRepository) SumElectronicsKwhBetween(ctx context.Context, userID int64, from, to time.Time) (float64, error) {
+	var total float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(ce.power_watts / 1000.0 * cel.duration_hours), 0)
+		FROM carbon_electronics_logs cel
+		JOIN carbon_electronics ce ON cel.device_id = ce.id
+		WHERE ce.user_id = $1 AND cel.logged_at >= $2 AND cel.logged_at < $3
+	`, userID, from, to).Scan(&total)
+	return total, err
+}
+
+func (r *carbonRepository) CreateFlightLog(ctx context.Context, log *models.CarbonFlightLog) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO carbon_flight_logs
+			(user_id, origin, destination, cabin_class, round_trip, distance_km, carbon_emission_g, logged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`,
+		log.UserID, log.Origin, log.Destination, log.CabinClass, log.RoundTrip,
+		log.DistanceKm, log.CarbonEmission, log.LoggedAt,
+	).Scan(&log.ID)
+}
+
+func (r *carbonRepository) GetAllFlightLogsByUser(ctx context.Context, userID int64) ([]*models.CarbonFlightLog, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, origin, destination, cabin_class, round_trip,
+		       distance_km, carbon_emission_g, logged_at
+		FROM carbon_flight_logs
+		WHERE user_id = $1
+		ORDER BY logged_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.CarbonFlightLog
+	for rows.Next() {
+		var log models.CarbonFlightLog
+		if err := rows.Scan(
+			&log.ID, &log.UserID, &log.Origin, &log.Destination, &log.CabinClass, &log.RoundTrip,
+			&log.DistanceKm, &log.CarbonEmission, &log.LoggedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, &log)
+	}
+
+	return logs, rows.Err()
+}
+
+// ======================== BASELINE ========================
+
+func (r *carbonRepository) CreateBaseline(ctx context.Context, baseline *models.CarbonBaseline) error {
+	appliances, err := json.Marshal(baseline.Appliances)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO carbon_baselines
+			(user_id, commute_mode, fuel_type, commute_km_per_day, commute_days_per_week, household_size,
+			 appliances, diet, transport_emission_g, electricity_emission_g, diet_emission_g, total_emission_g)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9, $10, $11, $12)
+		RETURNING id, created_at
+	`,
+		baseline.UserID, baseline.CommuteMode, baseline.FuelType, baseline.CommuteKmPerDay,
+		baseline.CommuteDaysPerWeek, baseline.HouseholdSize, string(appliances), baseline.Diet,
+		baseline.TransportEmission, baseline.ElectricityEmission, baseline.DietEmission, baseline.TotalEmission,
+	).Scan(&baseline.ID, &baseline.CreatedAt)
+}
+
+// ListBaselinesByUser terurut dari yang terbaru
+func (r *carbonRepository) ListBaselinesByUser(ctx context.Context, userID int64) ([]*models.CarbonBaseline, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, commute_mode, fuel_type, commute_km_per_day, commute_days_per_week, household_size,
+		       appliances, diet, transport_emission_g, electricity_emission_g, diet_emission_g, total_emission_g, created_at
+		FROM carbon_baselines
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var baselines []*models.CarbonBaseline
+	for rows.Next() {
+		var b models.CarbonBaseline
+		var appliances []byte
+		if err := rows.Scan(
+			&b.ID, &b.UserID, &b.CommuteMode, &b.FuelType, &b.CommuteKmPerDay, &b.CommuteDaysPerWeek, &b.HouseholdSize,
+			&appliances, &b.Diet, &b.TransportEmission, &b.ElectricityEmission, &b.DietEmission, &b.TotalEmission, &b.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(appliances, &b.Appliances); err != nil {
+			return nil, err
+		}
+		baselines = append(baselines, &b)
+	}
+
+	return baselines, rows.Err()
+}
+
+// nullableTime mengirim NULL untuk waktu kosong supaya default database yang dipakai
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+type CheckInRepositoryInterface interface {
+	CheckIn(ctx context.Context, userID int64, at time.Time) (*model.DailyCheckIn, bool, error)
+	MarkRewarded(ctx context.Context, checkInID int64, at time.Time) (bool, error)
+	FindLatest(ctx context.Context, userID int64) (*model.DailyCheckIn, error)
+	FindRecent(ctx context.Context, userID int64, limit int) ([]*model.DailyCheckIn, error)
+}
+
+type checkInRepository struct {
+	db *sql.DB
+}
+
+func NewCheckInRepository(db *sql.DB) CheckInRepositoryInterface {
+	return &checkInRepository{db: db}
+}
+
+const checkInColumns = `id, user_id, local_date, streak_day, cycle_day, reward_points, bonus_points, rewarded_at, created_at`
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func scanCheckIn(scanner interface{ Scan(...any) error }, c *model.DailyCheckIn) error {
+	return scanner.Scan(&c.ID, &c.UserID, &c.LocalDate, &c.StreakDay, &c.CycleDay, &c.RewardPoints,
+		&c.BonusPoints, &c.RewardedAt, &c.CreatedAt)
+}
+
+// CheckIn mencatat check-in hari ini di zona waktu streak user sekaligus memperpanjang
+// streak. Kalau hari ini sudah check-in, check-in yang ada dikembalikan dengan created false.
+func (r *checkInRepository) CheckIn(ctx context.Context, userID int64, at time.Time) (*model.DailyCheckIn, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Baris streak sekaligus jadi kunci supaya check-in bersamaan tidak dobel
+	streak, err := lockStreak(ctx, tx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	day := model.LocalDate(at, streak.Location())
+	previous, err := latestCheckIn(ctx, tx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	// Tanggal terakhir bisa di depan hari ini kalau user pindah ke zona waktu yang lebih lambat
+	if previous != nil && !previous.LocalDate.Before(day) {
+		return previous, false, nil
+	}
+
+	checkIn := model.NewDailyCheckIn(userID, day, previous, at)
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO daily_checkins (user_id, local_date, streak_day, cycle_day, reward_points, bonus_points, created_at)
+		VALUES ($1, $2::date, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, userID, day.Format("2006-01-02"), checkIn.StreakDay, checkIn.CycleDay, checkIn.RewardPoints,
+		checkIn.BonusPoints, checkIn.CreatedAt).Scan(&checkIn.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	streak.RegisterActiveDay(day)
+	if err := saveStreak(ctx, tx, streak); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return checkIn, true, nil
+}
+
+// MarkRewarded menandai hadiah check-in sudah dibayar. false kalau sudah ditandai
+// sebelumnya, jadi hanya satu pemanggil yang menyelesaikan check-in.
+func (r *checkInRepository) MarkRewarded(ctx context.Context, checkInID int64, at time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE daily_checkins SET rewarded_at = $2 WHERE id = $1 AND rewarded_at IS NULL
+	`, checkInID, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// FindLatest check-in terakhir user, nil kalau belum pernah
+func (r *checkInRepository) FindLatest(ctx context.Context, userID int64) (*model.DailyCheckIn, error) {
+	return latestCheckIn(ctx, r.db, userID)
+}
+
+func latestCheckIn(ctx context.Context, q rowQuerier, userID int64) (*model.DailyCheckIn, error) {
+	checkIn := &model.DailyCheckIn{}
+	err := scanCheckIn(q.QueryRowContext(ctx, `
+		SELECT `+checkInColumns+`
+		FROM daily_checkins
+		WHERE user_id = $1
+		ORDER BY local_date DESC
+		LIMIT 1
+	`, userID), checkIn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return checkIn, nil
+}
+
+// FindRecent riwayat check-in terbaru dulu
+func (r *checkInRepository) FindRecent(ctx context.Context, userID int64, limit int) ([]*model.DailyCheckIn, error) {
+	if limit < 1 {
+		limit = 7
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+checkInColumns+`
+		FROM daily_checkins
+		WHERE user_id = $1
+		ORDER BY local_date DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkIns := []*model.DailyCheckIn{}
+	for rows.Next() {
+		c := &model.DailyCheckIn{}
+		if err := scanCheckIn(rows, c); err != nil {
+			return nil, err
+		}
+		checkIns = append(checkIns, c)
+	}
+	return checkIns, rows.Err()
+}
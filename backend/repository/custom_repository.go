@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
 )
 
 type UserCustomEndpointRepoInterface interface {
@@ -31,6 +33,9 @@ type UserCustomData struct {
 	UserPoints              UserPoints
 	MonthlyVehicleCarbon    []MonthlyCarbon // Tambahan baru
 	MonthlyElectronicCarbon []MonthlyCarbon // Tambahan baru
+	Flights                 []FlightWithCarbon
+	MonthlyFlightCarbon     []MonthlyCarbon
+	MonthlyCategoryCarbon   map[string][]MonthlyCarbon
 }
 
 // Tambahkan struct MonthlyCarbon
@@ -72,6 +77,17 @@ type ElectronicWithCarbon struct {
 	TotalCarbon float64   `json:"total_carbon_emission_g"`
 }
 
+type FlightWithCarbon struct {
+	ID          int64     `json:"id"`
+	Origin      string    `json:"origin"`
+	Destination string    `json:"destination"`
+	CabinClass  string    `json:"cabin_class"`
+	RoundTrip   bool      `json:"round_trip"`
+	DistanceKm  float64   `json:"distance_km"`
+	TotalCarbon float64   `json:"total_carbon_emission_g"`
+	LoggedAt    time.Time `json:"logged_at"`
+}
+
 type MissionProgress struct {
 	ID            int64      `json:"id"`
 	Title         string     `json:"title"`
@@ -329,66 +345,70 @@ WHERE um.completed_at IS NOT NULL
 	}
 	rows.Close()
 
-	carbonVehicleMonthlyQuery := `
-		SELECT 
-			DATE_TRUNC('month', cvl.logged_at) as month,
-			COALESCE(SUM(cvl.carbon_emission_g), 0) as total_carbon
-		FROM carbon_vehicle_logs cvl
-		JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
-		WHERE cv.user_id = $1 
-			AND cvl.logged_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '5 months'
-		GROUP BY DATE_TRUNC('month', cvl.logged_at)
-		ORDER BY month DESC
-		LIMIT 6
-	`
-	rows, err = r.db.QueryContext(ctx, carbonVehicleMonthlyQuery, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	// Total karbon per bulan (6 bulan terakhir) untuk setiap kategori terdaftar
+	data.MonthlyCategoryCarbon = make(map[string][]MonthlyCarbon)
+	for _, cat := range category.All() {
+		monthlyQuery := `
+			SELECT
+				DATE_TRUNC('month', logged_at) as month,
+				COALESCE(SUM(carbon_emission_g), 0) as total_carbon
+			FROM (` + cat.EmissionSource() + `) AS emissions
+			WHERE user_id = $1
+				AND logged_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '5 months'
+			GROUP BY DATE_TRUNC('month', logged_at)
+			ORDER BY month DESC
+			LIMIT 6
+		`
+		rows, err = r.db.QueryContext(ctx, monthlyQuery, userID)
+		if err != nil {
+			return nil, err
+		}
 
-	data.MonthlyVehicleCarbon = make([]MonthlyCarbon, 0)
-	for rows.Next() {
-		var monthlyCarbon MonthlyCarbon
-		if err := rows.Scan(&monthlyCarbon.Month, &monthlyCarbon.TotalCarbon); err != nil {
+		monthly := make([]MonthlyCarbon, 0)
+		for rows.Next() {
+			var monthlyCarbon MonthlyCarbon
+			if err := rows.Scan(&monthlyCarbon.Month, &monthlyCarbon.TotalCarbon); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			monthly = append(monthly, monthlyCarbon)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
 			return nil, err
 		}
-		data.MonthlyVehicleCarbon = append(data.MonthlyVehicleCarbon, monthlyCarbon)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+		rows.Close()
 
-	// Query untuk total karbon per bulan (6 bulan terakhir) - ELECTRONIC
-	carbonElectronicMonthlyQuery := `
-		SELECT 
-			DATE_TRUNC('month', cel.logged_at) as month,
-			COALESCE(SUM(cel.carbon_emission_g), 0) as total_carbon
-		FROM carbon_electronics_logs cel
-		JOIN carbon_electronics ce ON cel.device_id = ce.id
-		WHERE ce.user_id = $1 
-			AND cel.logged_at >= DATE_TRUNC('month', CURRENT_DATE) - INTERVAL '5 months'
-		GROUP BY DATE_TRUNC('month', cel.logged_at)
-		ORDER BY month DESC
-		LIMIT 6
+		data.MonthlyCategoryCarbon[cat.Name()] = monthly
+	}
+	data.MonthlyVehicleCarbon = data.MonthlyCategoryCarbon[category.Vehicle]
+	data.MonthlyElectronicCarbon = data.MonthlyCategoryCarbon[category.Electronics]
+	data.MonthlyFlightCarbon = data.MonthlyCategoryCarbon[category.Flight]
+
+	// Query untuk flights
+	flightQuery := `
+		SELECT id, origin, destination, cabin_class, round_trip, distance_km, carbon_emission_g, logged_at
+		FROM carbon_flight_logs
+		WHERE user_id = $1
+		ORDER BY logged_at DESC
 	`
-	rows, err = r.db.QueryContext(ctx, carbonElectronicMonthlyQuery, userID)
+	rows, err = r.db.QueryContext(ctx, flightQuery, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	data.MonthlyElectronicCarbon = make([]MonthlyCarbon, 0)
 	for rows.Next() {
-		var monthlyCarbon MonthlyCarbon
-		if err := rows.Scan(&monthlyCarbon.Month, &monthlyCarbon.TotalCarbon); err != nil {
+		var flight FlightWithCarbon
+		if err := rows.Scan(&flight.ID, &flight.Origin, &flight.Destination, &flight.CabinClass, &flight.RoundTrip, &flight.DistanceKm, &flight.TotalCarbon, &flight.LoggedAt); err != nil {
 			return nil, err
 		}
-		data.MonthlyElectronicCarbon = append(data.MonthlyElectronicCarbon, monthlyCarbon)
+		data.Flights = append(data.Flights, flight)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+
 	return data, nil
 }
 
@@ -417,16 +437,9 @@ func (r *userCustomEndpointRepo) GetLeaderboard(ctx context.Context, page, limit
        COUNT(DISTINCT um.mission_id) as completed_missions,
        (COALESCE(p.total_points, 0) * 0.7 + COUNT(DISTINCT um.mission_id) * 0.3) as score,
        COALESCE((
-           SELECT SUM(carbon_emission_g) 
-           FROM (
-               SELECT carbon_emission_g FROM carbon_vehicle_logs cvl
-               JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
-               WHERE cv.user_id = u.id
-               UNION ALL
-               SELECT carbon_emission_g FROM carbon_electronics_logs cel
-               JOIN carbon_electronics ce ON cel.device_id = ce.id
-               WHERE ce.user_id = u.id
-           ) emissions
+           SELECT SUM(carbon_emission_g)
+           FROM (` + category.EmissionUnion() + `) emissions
+           WHERE emissions.user_id = u.id
        ), 0) as carbon_reduction
 FROM users u
 LEFT JOIN user_profiles up ON u.id = up.user_id
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+var ErrLessonNotFound = errors.New("lesson not found")
+
+type LessonRepositoryInterface interface {
+	Create(ctx context.Context, lesson *model.Lesson) error
+	Update(ctx context.Context, lesson *model.Lesson) error
+	Delete(ctx context.Context, id int64) error
+	FindByID(ctx context.Context, id int64) (*model.Lesson, error)
+	FindAll(ctx context.Context, publishedOnly bool, page, limit int) ([]*model.Lesson, error)
+	FindAttemptStats(ctx context.Context, userID, lessonID int64, since *time.Time) (*model.QuizAttemptStats, error)
+	FindUserAttempts(ctx context.Context, userID, lessonID int64) ([]*model.QuizAttempt, error)
+}
+
+type lessonRepository struct {
+	db *sql.DB
+}
+
+func NewLessonRepository(db *sql.DB) LessonRepositoryInterface {
+	return &lessonRepository{db: db}
+}
+
+const lessonColumns = `id, mission_id, title, content, pass_score, max_attempts, cooldown_minutes, published, created_at, updated_at`
+
+func scanLesson(scanner interface{ Scan(...any) error }, l *model.Lesson, extra ...any) error {
+	return scanner.Scan(append([]any{&l.ID, &l.MissionID, &l.Title, &l.Content, &l.PassScore, &l.MaxAttempts,
+		&l.CooldownMinutes, &l.Published, &l.CreatedAt, &l.UpdatedAt}, extra...)...)
+}
+
+// Create menyimpan lesson beserta soal-soalnya dalam satu transaksi
+func (r *lessonRepository) Create(ctx context.Context, lesson *model.Lesson) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO lessons (mission_id, title, content, pass_score, max_attempts, cooldown_minutes, published, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id
+	`, lesson.MissionID, lesson.Title, lesson.Content, lesson.PassScore, lesson.MaxAttempts,
+		lesson.CooldownMinutes, lesson.Published, lesson.CreatedAt).Scan(&lesson.ID)
+	if err != nil {
+		return err
+	}
+	lesson.UpdatedAt = lesson.CreatedAt
+
+	if err := insertQuizQuestions(ctx, tx, lesson); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update mengganti isi lesson dan seluruh soalnya. Riwayat percobaan tidak diubah.
+func (r *lessonRepository) Update(ctx context.Context, lesson *model.Lesson) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE lessons
+		SET mission_id = $2, title = $3, content = $4, pass_score = $5, max_attempts = $6,
+		    cooldown_minutes = $7, published = $8, updated_at = $9
+		WHERE id = $1
+	`, lesson.ID, lesson.MissionID, lesson.Title, lesson.Content, lesson.PassScore, lesson.MaxAttempts,
+		lesson.CooldownMinutes, lesson.Published, lesson.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrLessonNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM quiz_questions WHERE lesson_id = $1`, lesson.ID); err != nil {
+		return err
+	}
+	if err := insertQuizQuestions(ctx, tx, lesson); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertQuizQuestions(ctx context.Context, tx *sql.Tx, lesson *model.Lesson) error {
+	for i, q := range lesson.Questions {
+		options, err := json.Marshal(q.Options)
+		if err != nil {
+			return err
+		}
+		q.LessonID, q.Position = lesson.ID, i+1
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO quiz_questions (lesson_id, position, prompt, options, correct_option, explanation)
+			VALUES ($1, $2, $3, $4::jsonb, $5, $6)
+			RETURNING id
+		`, q.LessonID, q.Position, q.Prompt, string(options), q.CorrectOption, q.Explanation).Scan(&q.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *lessonRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM lessons WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLessonNotFound
+	}
+	return nil
+}
+
+// FindByID lesson beserta soal-soalnya, nil kalau tidak ada
+func (r *lessonRepository) FindByID(ctx context.Context, id int64) (*model.Lesson, error) {
+	lesson := &model.Lesson{}
+	err := scanLesson(r.db.QueryRowContext(ctx, `SELECT `+lessonColumns+` FROM lessons WHERE id = $1`, id), lesson)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, lesson_id, position, prompt, options, correct_option, explanation
+		FROM quiz_questions
+		WHERE lesson_id = $1
+		ORDER BY position, id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		q := &model.QuizQuestion{}
+		var options []byte
+		if err := rows.Scan(&q.ID, &q.LessonID, &q.Position, &q.Prompt, &options, &q.CorrectOption, &q.Explanation); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(options, &q.Options); err != nil {
+			return nil, err
+		}
+		lesson.Questions = append(lesson.Questions, q)
+	}
+	lesson.QuestionCount = len(lesson.Questions)
+	return lesson, rows.Err()
+}
+
+// FindAll daftar lesson tanpa soal, terbaru dulu
+func (r *lessonRepository) FindAll(ctx context.Context, publishedOnly bool, page, limit int) ([]*model.Lesson, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+lessonColumns+`, (SELECT COUNT(*) FROM quiz_questions q WHERE q.lesson_id = lessons.id)
+		FROM lessons
+		WHERE published OR NOT $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, publishedOnly, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := []*model.Lesson{}
+	for rows.Next() {
+		lesson := &model.Lesson{}
+		if err := scanLesson(rows, lesson, &lesson.QuestionCount); err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, lesson)
+	}
+	return lessons, rows.Err()
+}
+
+func (r *lessonRepository) FindAttemptStats(ctx context.Context, userID, lessonID int64, since *time.Time) (*model.QuizAttemptStats, error) {
+	return quizAttemptStats(ctx, r.db, userID, lessonID, since)
+}
+
+// quizAttemptStats jumlah percobaan, status lulus dan percobaan terakhir sejak since
+// (nil berarti semua percobaan)
+func quizAttemptStats(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}, userID, lessonID int64, since *time.Time) (*model.QuizAttemptStats, error) {
+	stats := &model.QuizAttemptStats{}
+	err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(BOOL_OR(passed), FALSE), MAX(created_at)
+		FROM quiz_attempts
+		WHERE user_id = $1 AND lesson_id = $2 AND ($3::timestamptz IS NULL OR created_at >= $3)
+	`, userID, lessonID, since).Scan(&stats.Attempts, &stats.Passed, &stats.LastAttemptAt)
+	return stats, err
+}
+
+// FindUserAttempts riwayat percobaan user untuk satu lesson, terbaru dulu
+func (r *lessonRepository) FindUserAttempts(ctx context.Context, userID, lessonID int64) ([]*model.QuizAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, lesson_id, user_id, mission_id, answers, correct, total, score, passed, period_start, created_at
+		FROM quiz_attempts
+		WHERE user_id = $1 AND lesson_id = $2
+		ORDER BY created_at DESC, id DESC
+	`, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*model.QuizAttempt{}
+	for rows.Next() {
+		a := &model.QuizAttempt{}
+		var answers []byte
+		if err := rows.Scan(&a.ID, &a.LessonID, &a.UserID, &a.MissionID, &answers, &a.Correct, &a.Total,
+			&a.Score, &a.Passed, &a.PeriodStart, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(answers, &a.Answers); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Admin Mission Management
+// =========================
+
+var ErrMissionInUse = errors.New("mission already has participants or dependent missions, archive it instead")
+
+// MissionParticipants jumlah user per status untuk satu misi
+type MissionParticipants struct {
+	Active    int
+	Completed int
+	Total     int
+}
+
+// Update menyimpan kolom yang boleh diubah admin (aturan JSON hanya target-nya).
+// Tipe, criteria, metric aturan, scope, comparator, window dan pengulangan tidak ikut diubah karena mengubah arti progres
+// yang sudah tercatat; untuk itu misi harus diduplikasi.
+func (r *missionRepository) Update(ctx context.Context, mission *model.Mission) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateMission(ctx, tx, mission); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func updateMission(ctx context.Context, tx *sql.Tx, mission *model.Mission) error {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE missions
+		SET title = $2, description = $3, points_reward = $4, gives_badge = $5, badge_id = $6,
+		    target_value = $7, target_max = $8, expired_at = $9, starts_at = $10, auto_join = $11,
+		    prerequisite_mode = $12, min_contribution = $13, min_activity_logs = $14, rule = $15, updated_at = $16
+		WHERE id = $1
+	`, mission.ID, mission.Title, mission.Description, mission.PointsReward, mission.GivesBadge, mission.BadgeID,
+		mission.TargetValue, mission.TargetMax, mission.ExpiredAt, mission.StartsAt, mission.AutoJoin,
+		mission.PrerequisiteMode, mission.MinContribution, mission.MinActivityLogs, mission.Rule, time.Now())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mission_prerequisites WHERE mission_id = $1`, mission.ID); err != nil {
+		return err
+	}
+	return insertPrerequisites(ctx, tx, mission.ID, mission.Prerequisites)
+}
+
+// Archive menyembunyikan misi dan menutup misi user yang masih aktif (expired).
+// status_changed_at enrollment yang ditutup disamakan dengan archived_at supaya Restore
+// bisa membukanya lagi. Progres, penyelesaian dan poin yang sudah didapat tidak diubah.
+func (r *missionRepository) Archive(ctx context.Context, missionID int64, at time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE missions SET archived_at = $2, updated_at = $2 WHERE id = $1 AND archived_at IS NULL`, missionID, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE user_missions SET status = 'expired', status_changed_at = $2
+		WHERE mission_id = $1 AND status = 'active'
+	`, missionID, at); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Restore menampilkan lagi misi yang diarsipkan. Enrollment yang ditutup oleh Archive
+// (status_changed_at sama dengan archived_at) aktif lagi dengan progres yang tersimpan;
+// enrollment yang sudah berakhir sebelum diarsipkan tidak diubah.
+func (r *missionRepository) Restore(ctx context.Context, missionID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var archivedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, `SELECT archived_at FROM missions WHERE id = $1 FOR UPDATE`, missionID).Scan(&archivedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !archivedAt.Valid {
+		return false, nil
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE user_missions um SET status = 'active', status_changed_at = $2
+		FROM missions m
+		WHERE m.id = um.mission_id AND um.mission_id = $1
+		  AND um.status = 'expired' AND um.status_changed_at = m.archived_at
+	`, missionID, now); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE missions SET archived_at = NULL, updated_at = $2 WHERE id = $1`, missionID, now); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Delete menghapus misi yang belum pernah diikuti siapa pun dan bukan prasyarat misi lain
+func (r *missionRepository) Delete(ctx context.Context, missionID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM missions WHERE id = $1 FOR UPDATE`, missionID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var inUse bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM user_missions WHERE mission_id = $1)
+		    OR EXISTS (SELECT 1 FROM mission_prerequisites WHERE prerequisite_id = $1)
+	`, missionID).Scan(&inUse); err != nil {
+		return false, err
+	}
+	if inUse {
+		return false, ErrMissionInUse
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM missions WHERE id = $1`, missionID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *missionRepository) CountParticipants(ctx context.Context, missionID int64) (*MissionParticipants, error) {
+	p := &MissionParticipants{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'active'),
+		       COUNT(*) FILTER (WHERE completed_at IS NOT NULL),
+		       COUNT(*)
+		FROM user_missions
+		WHERE mission_id = $1
+	`, missionID).Scan(&p.Active, &p.Completed, &p.Total)
+	return p, err
+}
+
+// ReevaluateMission menerapkan perubahan target ke peserta yang sudah ada. Progres tidak
+// diubah (progres adalah hasil ukur, bukan persen), jadi target yang diturunkan langsung
+// menyelesaikan peserta yang sudah melewatinya. Penyelesaian dan poin yang sudah diberikan
+// tidak pernah dicabut saat target dinaikkan. Misi lte/between tetap dinilai di akhir window.
+func (r *checkMissionRepository) ReevaluateMission(ctx context.Context, mission *model.Mission) (int64, error) {
+	if mission.SettlesAtWindowEnd() {
+		return 0, nil
+	}
+
+	if mission.IsCollective() {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT team_id FROM mission_collective_progress
+			WHERE mission_id = $1 AND completed_at IS NULL AND progress_value >= $2
+		`, mission.ID, mission.TargetValue)
+		if err != nil {
+			return 0, err
+		}
+		var groups []sql.NullInt64
+		for rows.Next() {
+			var teamID sql.NullInt64
+			if err := rows.Scan(&teamID); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			groups = append(groups, teamID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+
+		var completed int64
+		for _, teamID := range groups {
+			done, err := r.completeCollective(ctx, mission, teamID, time.Now())
+			if err != nil {
+				return completed, err
+			}
+			if done {
+				completed++
+			}
+		}
+		return completed, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id FROM user_missions WHERE mission_id = $1 AND status = 'active'`, mission.ID)
+	if err != nil {
+		return 0, err
+	}
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var completed int64
+	for _, userID := range userIDs {
+		done, err := r.CheckMission(ctx, userID, mission)
+		if err != nil {
+			return completed, err
+		}
+		if done {
+			completed++
+		}
+	}
+	return completed, nil
+}
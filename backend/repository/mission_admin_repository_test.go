@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+func userMissionStatus(t *testing.T, db *sql.DB, userID, missionID int64) model.UserMissionStatus {
+	t.Helper()
+	var status string
+	if err := db.QueryRowContext(context.Background(), `
+		SELECT status FROM user_missions WHERE user_id = $1 AND mission_id = $2
+	`, userID, missionID).Scan(&status); err != nil {
+		t.Fatalf("read user mission status: %v", err)
+	}
+	return model.UserMissionStatus(status)
+}
+
+func TestArchiveRestoreReopensArchivedEnrollments(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	missions := NewMissionRepository(db)
+	checks := CheckMissionRepository(db)
+	now := time.Now()
+
+	participant := seedUser(t, db, "archive-participant")
+	ended := seedUser(t, db, "archive-ended")
+	newcomer := seedUser(t, db, "archive-newcomer")
+
+	mission := &model.Mission{
+		Title:          "Archive restore",
+		MissionType:    model.MissionTypeActivity,
+		PointsReward:   10,
+		TargetValue:    5,
+		CreatedAt:      now,
+		ProgressWindow: model.ProgressWindowLifetime,
+		Recurrence:     model.RecurrenceNone,
+	}
+	if err := missions.Create(ctx, mission); err != nil {
+		t.Fatalf("create mission: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, query := range []string{
+			`DELETE FROM user_mission_progress WHERE mission_id = $1`,
+			`DELETE FROM user_missions WHERE mission_id = $1`,
+			`DELETE FROM missions WHERE id = $1`,
+		} {
+			if _, err := db.Exec(query, mission.ID); err != nil {
+				t.Logf("cleanup: %v", err)
+			}
+		}
+		for _, userID := range []int64{participant, ended, newcomer} {
+			if _, err := db.Exec(`DELETE FROM users WHERE id = $1`, userID); err != nil {
+				t.Logf("cleanup user: %v", err)
+			}
+		}
+	})
+
+	for _, userID := range []int64{participant, ended} {
+		if _, err := checks.JoinMission(ctx, userID, mission.ID, 0, now); err != nil {
+			t.Fatalf("join mission: %v", err)
+		}
+	}
+	// Enrollment yang sudah berakhir sebelum diarsipkan tidak boleh ikut dibuka lagi
+	if _, err := db.ExecContext(ctx, `
+		UPDATE user_missions SET status = 'expired', status_changed_at = $3
+		WHERE user_id = $1 AND mission_id = $2
+	`, ended, mission.ID, now.Add(-time.Hour)); err != nil {
+		t.Fatalf("expire enrollment: %v", err)
+	}
+
+	if ok, err := missions.Archive(ctx, mission.ID, now.Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("archive = %v, %v", ok, err)
+	}
+	if got := userMissionStatus(t, db, participant, mission.ID); got != model.UserMissionExpired {
+		t.Fatalf("status after archive = %s, want %s", got, model.UserMissionExpired)
+	}
+
+	if ok, err := missions.Restore(ctx, mission.ID); err != nil || !ok {
+		t.Fatalf("restore = %v, %v", ok, err)
+	}
+	if got := userMissionStatus(t, db, participant, mission.ID); got != model.UserMissionActive {
+		t.Errorf("participant status after restore = %s, want %s", got, model.UserMissionActive)
+	}
+	if got := userMissionStatus(t, db, ended, mission.ID); got != model.UserMissionExpired {
+		t.Errorf("ended status after restore = %s, want %s", got, model.UserMissionExpired)
+	}
+
+	if _, err := checks.JoinMission(ctx, participant, mission.ID, 0, time.Now()); !errors.Is(err, ErrMissionAlreadyJoined) {
+		t.Errorf("participant join after restore = %v, want %v", err, ErrMissionAlreadyJoined)
+	}
+	if _, err := checks.JoinMission(ctx, newcomer, mission.ID, 0, time.Now()); err != nil {
+		t.Errorf("newcomer join after restore: %v", err)
+	}
+}
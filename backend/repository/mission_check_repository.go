@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/Qodarrz/fiber-app/category"
 	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/rule"
 )
 
 // =========================
@@ -27,6 +30,28 @@ type CheckMissionRepositoryInterface interface {
 	CheckAllUserMissions(ctx context.Context, userID int64) error
 	CheckUserMissionsByType(ctx context.Context, userID int64, missionType model.MissionType) error
 	CheckUserMissionsByCriteriaType(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType) error
+	EnrollUser(ctx context.Context, userID, missionID int64, at time.Time) (time.Time, error)
+	Publish(ctx context.Context, event model.MissionEvent) error
+	RollOverRecurringMissions(ctx context.Context, now time.Time) (int64, error)
+	JoinMission(ctx context.Context, userID, missionID int64, maxActive int, at time.Time) (*model.UserMission, error)
+	LeaveMission(ctx context.Context, userID, missionID int64, at time.Time) error
+	ExpireUserMissions(ctx context.Context, now time.Time, userID *int64) (int64, error)
+	SettleClosedWindows(ctx context.Context, now time.Time, userID *int64) (int64, error)
+	ReevaluateMission(ctx context.Context, mission *model.Mission) (int64, error)
+	FindCollectiveStandings(ctx context.Context, userID int64, mission *model.Mission, limit int) ([]*model.CollectiveStanding, error)
+	FindProgressHistory(ctx context.Context, userID, missionID int64, limit int) ([]*model.MissionProgressSnapshot, error)
+	SubmitProof(ctx context.Context, userID int64, mission *model.Mission, photoURL, note string, now time.Time) (*model.MissionProofSubmission, error)
+	FindUserProofs(ctx context.Context, userID, missionID int64) ([]*model.MissionProofSubmission, error)
+	FindProofQueue(ctx context.Context, status model.ProofStatus, page, limit int) ([]*model.MissionProofSubmission, error)
+	ReviewProof(ctx context.Context, proofID, reviewerID int64, approve bool, note string, now time.Time) (*model.MissionProofSubmission, bool, error)
+	SubmitQuizAttempt(ctx context.Context, lesson *model.Lesson, mission *model.Mission, attempt *model.QuizAttempt, now time.Time) (bool, error)
+	FindRecommendationProfile(ctx context.Context, userID int64) (*model.RecommendationProfile, error)
+	RecentProgress(ctx context.Context, userID int64, mission *model.Mission, since, now time.Time) (float64, error)
+	FindDryRunUserIDs(ctx context.Context, sample int) ([]int64, error)
+	FindCompletedAmong(ctx context.Context, missionIDs []int64) (map[int64]map[int64]bool, error)
+	DryRunProgress(ctx context.Context, mission *model.Mission, userIDs []int64, at time.Time) (map[int64]DryRunOutcome, error)
+	NotifyExpiringMissions(ctx context.Context, now time.Time, within time.Duration) (int64, error)
+	FindUsersWithActiveMissions(ctx context.Context) ([]int64, error)
 }
 
 type checkMissionRepository struct {
@@ -44,7 +69,8 @@ func (r *checkMissionRepository) FindByID(ctx context.Context, id int64) (*model
 	mission := &model.Mission{}
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, 
-		       gives_badge, badge_id, target_value, created_at, expired_at
+		       gives_badge, badge_id, target_value, created_at, expired_at,
+		       starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
 		WHERE id = $1
 	`
@@ -56,6 +82,7 @@ func (r *checkMissionRepository) FindByID(ctx context.Context, id int64) (*model
 		&mission.ID, &mission.Title, &mission.Description, &mission.MissionType,
 		&criteriaType, &mission.PointsReward, &mission.GivesBadge, &badgeID,
 		&mission.TargetValue, &mission.CreatedAt, &mission.ExpiredAt,
+		&mission.StartsAt, &mission.ProgressWindow, &mission.Recurrence, &mission.RecurrenceRule, &mission.AutoJoin, &mission.PrerequisiteMode, &mission.Scope, &mission.MinContribution, &mission.Comparator, &mission.TargetMax, &mission.MinActivityLogs, &mission.Rule, &mission.ArchivedAt,
 	)
 
 	if err != nil {
@@ -79,9 +106,12 @@ func (r *checkMissionRepository) FindByID(ctx context.Context, id int64) (*model
 func (r *checkMissionRepository) FindActiveMissions(ctx context.Context) ([]*model.Mission, error) {
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward,
-		       gives_badge, badge_id, target_value, created_at, expired_at
+		       gives_badge, badge_id, target_value, created_at, expired_at,
+		       starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
 		WHERE (expired_at IS NULL OR expired_at > $1)
+  AND (starts_at IS NULL OR starts_at <= $1)
+  AND archived_at IS NULL
   AND mission_type IN ('carbon_reduction', 'streak', 'activity', 'custom')
 	`
 	rows, err := r.db.QueryContext(ctx, query, time.Now())
@@ -100,6 +130,7 @@ func (r *checkMissionRepository) FindActiveMissions(ctx context.Context) ([]*mod
 			&m.ID, &m.Title, &m.Description, &m.MissionType, &criteriaType,
 			&m.PointsReward, &m.GivesBadge, &badgeID,
 			&m.TargetValue, &m.CreatedAt, &m.ExpiredAt,
+			&m.StartsAt, &m.ProgressWindow, &m.Recurrence, &m.RecurrenceRule, &m.AutoJoin, &m.PrerequisiteMode, &m.Scope, &m.MinContribution, &m.Comparator, &m.TargetMax, &m.MinActivityLogs, &m.Rule, &m.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -120,9 +151,12 @@ func (r *checkMissionRepository) FindActiveMissions(ctx context.Context) ([]*mod
 func (r *checkMissionRepository) FindMissionsByType(ctx context.Context, missionType model.MissionType) ([]*model.Mission, error) {
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, 
-		       gives_badge, badge_id, target_value, created_at, expired_at
+		       gives_badge, badge_id, target_value, created_at, expired_at,
+		       starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
 		WHERE mission_type = $1 AND (expired_at IS NULL OR expired_at > $2)
+		  AND (starts_at IS NULL OR starts_at <= $2)
+		  AND archived_at IS NULL
 	`
 	rows, err := r.db.QueryContext(ctx, query, missionType, time.Now())
 	if err != nil {
@@ -140,6 +174,7 @@ func (r *checkMissionRepository) FindMissionsByType(ctx context.Context, mission
 			&m.ID, &m.Title, &m.Description, &m.MissionType, &criteriaType,
 			&m.PointsReward, &m.GivesBadge, &badgeID,
 			&m.TargetValue, &m.CreatedAt, &m.ExpiredAt,
+			&m.StartsAt, &m.ProgressWindow, &m.Recurrence, &m.RecurrenceRule, &m.AutoJoin, &m.PrerequisiteMode, &m.Scope, &m.MinContribution, &m.Comparator, &m.TargetMax, &m.MinActivityLogs, &m.Rule, &m.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -159,9 +194,12 @@ func (r *checkMissionRepository) FindMissionsByType(ctx context.Context, mission
 func (r *checkMissionRepository) FindMissionsByCriteriaType(ctx context.Context, criteriaType model.MissionCriteriaType) ([]*model.Mission, error) {
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, 
-		       gives_badge, badge_id, target_value, created_at, expired_at
+		       gives_badge, badge_id, target_value, created_at, expired_at,
+		       starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
 		WHERE criteria_type = $1 AND (expired_at IS NULL OR expired_at > $2)
+		  AND (starts_at IS NULL OR starts_at <= $2)
+		  AND archived_at IS NULL
 	`
 	rows, err := r.db.QueryContext(ctx, query, criteriaType, time.Now())
 	if err != nil {
@@ -178,6 +216,7 @@ func (r *checkMissionRepository) FindMissionsByCriteriaType(ctx context.Context,
 			&m.ID, &m.Title, &m.Description, &m.MissionType, &m.CriteriaType,
 			&m.PointsReward, &m.GivesBadge, &badgeID,
 			&m.TargetValue, &m.CreatedAt, &m.ExpiredAt,
+			&m.StartsAt, &m.ProgressWindow, &m.Recurrence, &m.RecurrenceRule, &m.AutoJoin, &m.PrerequisiteMode, &m.Scope, &m.MinContribution, &m.Comparator, &m.TargetMax, &m.MinActivityLogs, &m.Rule, &m.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -227,7 +266,15 @@ func (r *checkMissionRepository) UpdateMissionProgress(ctx context.Context, user
 }
 
 func (r *checkMissionRepository) MarkMissionCompleted(ctx context.Context, userID, missionID int64) error {
-	query := `UPDATE user_missions SET completed_at = $1 WHERE user_id = $2 AND mission_id = $3`
+	// Misi berulang tetap aktif untuk periode berikutnya
+	query := `
+		UPDATE user_missions um
+		SET completed_at = $1,
+		    status = CASE WHEN m.recurrence = 'none' THEN 'completed' ELSE um.status END,
+		    status_changed_at = CASE WHEN m.recurrence = 'none' THEN $1 ELSE um.status_changed_at END
+		FROM missions m
+		WHERE m.id = um.mission_id AND um.user_id = $2 AND um.mission_id = $3
+	`
 	_, err := r.db.ExecContext(ctx, query, time.Now(), userID, missionID)
 	return err
 }
@@ -245,110 +292,211 @@ func (r *checkMissionRepository) HasUserCompletedMission(ctx context.Context, us
 // =========================
 // Mission Progress Calculation Functions
 // =========================
-func (r *checkMissionRepository) calculateMissionProgress(ctx context.Context, userID int64, mission *model.Mission) (float64, error) {
+// EnrollUser mendaftarkan user ke misi dan mengembalikan waktu enrollment.
+// Kalau sudah terdaftar, waktu enrollment lama yang dikembalikan.
+func (r *checkMissionRepository) EnrollUser(ctx context.Context, userID, missionID int64, at time.Time) (time.Time, error) {
+	var enrolledAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_missions (user_id, mission_id, created_at, enrolled_at, status, joined_via, status_changed_at)
+		VALUES ($1, $2, $3, $3, 'active', 'auto', $3)
+		ON CONFLICT (user_id, mission_id)
+		DO UPDATE SET enrolled_at = user_missions.enrolled_at
+		RETURNING enrolled_at
+	`, userID, missionID, at).Scan(&enrolledAt)
+	return enrolledAt, err
+}
+
+// missionWindow rentang aktivitas yang dihitung untuk misi ini. Hanya membaca enrollment;
+// user yang belum mengikuti misi mendapat ErrMissionNotJoined (enrollment lewat JoinMission
+// atau auto-join).
+func (r *checkMissionRepository) missionWindow(ctx context.Context, userID int64, mission *model.Mission, now time.Time) (category.Window, error) {
+	var enrolledAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT enrolled_at FROM user_missions WHERE user_id = $1 AND mission_id = $2
+	`, userID, mission.ID).Scan(&enrolledAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category.Window{}, ErrMissionNotJoined
+		}
+		return category.Window{}, err
+	}
+	from, to := mission.Window(enrolledAt, now)
+	return category.Window{From: from, To: to}, nil
+}
+
+func (r *checkMissionRepository) calculateMissionProgress(ctx context.Context, userID int64, mission *model.Mission, now time.Time) (float64, error) {
+	w, err := r.missionWindow(ctx, userID, mission, now)
+	if err != nil {
+		return 0, err
+	}
+	return r.progressInWindow(ctx, userID, mission, w)
+}
+
+// progressInWindow progres misi dari data mentah di window tertentu, tanpa enrollment
+func (r *checkMissionRepository) progressInWindow(ctx context.Context, userID int64, mission *model.Mission, w category.Window) (float64, error) {
+	// Misi dengan aturan JSON tidak melewati switch tipe misi
+	if mission.Rule.Valid {
+		return r.calculateRuleProgress(ctx, userID, mission, w)
+	}
+
 	switch mission.MissionType {
 	case model.MissionTypeStreak:
-		return r.calculateLoginStreakProgress(ctx, userID)
+		return r.calculateLoginStreakProgress(ctx, userID, w)
 	case model.MissionTypeCarbonReduction:
-		return r.calculateCarbonReductionProgress(ctx, userID, mission.CriteriaType)
+		return r.calculateCarbonReductionProgress(ctx, userID, mission.CriteriaType, w)
 	case model.MissionTypeActivity:
-		return r.calculateActivityCountProgress(ctx, userID, mission.CriteriaType)
+		return r.calculateActivityCountProgress(ctx, userID, mission.CriteriaType, w)
 	case model.MissionTypeCustom:
-		return r.calculateCustomMissionProgress(ctx, userID, mission.CriteriaType)
+		return r.calculateCustomMissionProgress(ctx, userID, mission.CriteriaType, w)
+	case model.MissionTypeProof:
+		return r.calculateProofProgress(ctx, userID, mission.ID, w)
+	case model.MissionTypeQuiz:
+		return r.calculateQuizProgress(ctx, userID, mission.ID, w)
 	default:
 		return 0, fmt.Errorf("unknown mission type: %s", mission.MissionType)
 	}
 }
 
-func (r *checkMissionRepository) calculateLoginStreakProgress(ctx context.Context, userID int64) (float64, error) {
-	var streak float64
-	query := `
-		SELECT COUNT(DISTINCT DATE(created_at)) 
-		FROM activity_logs
-		WHERE user_id = $1 AND activity LIKE '%login%'
-		AND created_at >= NOW() - INTERVAL '30 days'
-	`
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&streak)
+func (r *checkMissionRepository) calculateLoginStreakProgress(ctx context.Context, userID int64, w category.Window) (float64, error) {
+	streak, err := NewStreakRepository(r.db).FindByUserID(ctx, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0, nil
-		}
 		return 0, err
 	}
-	return streak, nil
+	if streak == nil {
+		return 0, nil
+	}
+
+	loc := streak.Location()
+	today := model.LocalDate(time.Now(), loc)
+	current := streak.CurrentAt(today)
+
+	// Hari streak sebelum window misi dimulai tidak ikut dihitung
+	if w.From != nil {
+		days := int(today.Sub(model.LocalDate(*w.From, loc)).Hours()/24) + 1
+		if current > days {
+			current = days
+		}
+	}
+	return float64(current), nil
 }
 
-func (r *checkMissionRepository) calculateCarbonReductionProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType) (float64, error) {
+func (r *checkMissionRepository) calculateCarbonReductionProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType, w category.Window) (float64, error) {
 	var totalCarbon float64
 
 	if criteriaType == "" {
+		// Total emisi dari semua kategori terdaftar
+		cond, args := w.Filter("logged_at", 2)
 		query := `
-			SELECT COALESCE(SUM(carbon_emission_g), 0) 
-			FROM (
-				SELECT carbon_emission_g FROM carbon_vehicle_logs cvl
-				JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
-				WHERE cv.user_id = $1
-				UNION ALL
-				SELECT carbon_emission_g FROM carbon_electronics_logs cel
-				JOIN carbon_electronics ce ON cel.device_id = ce.id
-				WHERE ce.user_id = $2
-			) AS emissions
-		`
-		err := r.db.QueryRowContext(ctx, query, userID, userID).Scan(&totalCarbon)
+			SELECT COALESCE(SUM(carbon_emission_g), 0)
+			FROM (` + category.EmissionUnion() + `) AS emissions
+			WHERE user_id = $1` + cond
+		err := r.db.QueryRowContext(ctx, query, append([]any{userID}, args...)...).Scan(&totalCarbon)
 		if err != nil {
 			return 0, err
 		}
-	} else {
-		// Carbon reduction berdasarkan criteria type
-		switch criteriaType {
-		case model.CriteriaCar, model.CriteriaMotorcycle, model.CriteriaBicycle,
-			model.CriteriaPublicTransport, model.CriteriaWalk:
-			// Carbon dari kendaraan tertentu
-			query := `
-				SELECT COALESCE(SUM(carbon_emission_g), 0) 
-				FROM carbon_vehicle_logs cvl
-				JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
-				WHERE cv.user_id = $1 AND cv.vehicle_type = $2
-			`
-			err := r.db.QueryRowContext(ctx, query, userID, string(criteriaType)).Scan(&totalCarbon)
-			if err != nil {
-				return 0, err
-			}
+		return totalCarbon, nil
+	}
 
-		case model.CriteriaLaptop, model.CriteriaDesktop, model.CriteriaTV,
-			model.CriteriaAC, model.CriteriaFridge, model.CriteriaFan,
-			model.CriteriaWashingMachine, model.CriteriaOther:
-			// Carbon dari elektronik tertentu
-			query := `
-				SELECT COALESCE(SUM(carbon_emission_g), 0) 
-				FROM carbon_electronics_logs cel
-				JOIN carbon_electronics ce ON cel.device_id = ce.id
-				WHERE ce.user_id = $1 AND ce.device_type = $2
-			`
-			err := r.db.QueryRowContext(ctx, query, userID, string(criteriaType)).Scan(&totalCarbon)
-			if err != nil {
-				return 0, err
-			}
-		}
+	if criteriaType == model.CriteriaBaselineReduction {
+		return r.calculateBaselineReductionProgress(ctx, userID, w)
+	}
+
+	// Carbon reduction berdasarkan criteria type
+	cat, ok := category.ForCriteria(criteriaType)
+	if !ok {
+		return 0, nil
+	}
+	q := cat.ReductionQuery(criteriaType, w)
+	err := r.db.QueryRowContext(ctx, q.SQL, append([]any{userID}, q.Args...)...).Scan(&totalCarbon)
+	if err != nil {
+		return 0, err
 	}
 
 	return totalCarbon, nil
 }
 
-func (r *checkMissionRepository) calculateActivityCountProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType) (float64, error) {
+// calculateBaselineReductionProgress persen penurunan emisi di dalam window misi dibanding
+// baseline onboarding yang diprorata. Hanya bagian baseline yang bisa dicatat
+// (kendaraan dan listrik) yang dibandingkan, sampah dan penerbangan tidak ikut.
+func (r *checkMissionRepository) calculateBaselineReductionProgress(ctx context.Context, userID int64, w category.Window) (float64, error) {
+	baselines, err := NewCarbonRepository(r.db).ListBaselinesByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(baselines) == 0 {
+		return 0, nil
+	}
+	baseline := baselines[0]
+
+	// Rentang yang dibandingkan: window misi, tapi tidak sebelum baseline diisi
+	from, to := baseline.CreatedAt, time.Now()
+	if w.From != nil && w.From.After(from) {
+		from = *w.From
+	}
+	if w.To != nil && w.To.Before(to) {
+		to = *w.To
+	}
+	if !to.After(from) {
+		return 0, nil
+	}
+	span := category.Window{From: &from, To: &to}
+
+	transport, err := r.sumCategoryEmission(ctx, userID, category.Vehicle, span)
+	if err != nil {
+		return 0, err
+	}
+	electricity, err := r.sumCategoryEmission(ctx, userID, category.Electronics, span)
+	if err != nil {
+		return 0, err
+	}
+	// Listrik baseline adalah bagian per orang dari pemakaian rumah tangga
+	if baseline.HouseholdSize > 1 {
+		electricity /= float64(baseline.HouseholdSize)
+	}
+	actual := transport + electricity
+
+	// Hari berjalan dihitung penuh, sama seperti baseline harian
+	days := math.Ceil(to.Sub(from).Hours() / 24)
+	expected := baseline.TrackedEmission() / 365 * days
+	if expected <= 0 || actual >= expected {
+		return 0, nil
+	}
+
+	return (expected - actual) / expected * 100, nil
+}
+
+// sumCategoryEmission total emisi satu kategori di dalam window
+func (r *checkMissionRepository) sumCategoryEmission(ctx context.Context, userID int64, name string, w category.Window) (float64, error) {
+	cat, ok := category.Get(name)
+	if !ok {
+		return 0, fmt.Errorf("unknown carbon category: %s", name)
+	}
+
+	var total float64
+	cond, args := w.Filter("logged_at", 2)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(carbon_emission_g), 0)
+		FROM (`+cat.EmissionSource()+`) AS emissions
+		WHERE user_id = $1`+cond, append([]any{userID}, args...)...).Scan(&total)
+	return total, err
+}
+
+func (r *checkMissionRepository) calculateActivityCountProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType, w category.Window) (float64, error) {
 	var count float64
 
 	if criteriaType == "" {
 		// Total semua aktivitas
-		query := `SELECT COUNT(*) FROM activity_logs WHERE user_id = $1`
-		err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+		cond, args := w.Filter("created_at", 2)
+		query := `SELECT COUNT(*) FROM activity_logs WHERE user_id = $1` + cond
+		err := r.db.QueryRowContext(ctx, query, append([]any{userID}, args...)...).Scan(&count)
 		if err != nil {
 			return 0, err
 		}
 	} else {
 		// Aktivitas berdasarkan criteria type
-		query := `SELECT COUNT(*) FROM activity_logs WHERE user_id = $1 AND activity = $2`
-		err := r.db.QueryRowContext(ctx, query, userID, string(criteriaType)).Scan(&count)
+		cond, args := w.Filter("created_at", 3)
+		query := `SELECT COUNT(*) FROM activity_logs WHERE user_id = $1 AND activity = $2` + cond
+		err := r.db.QueryRowContext(ctx, query, append([]any{userID, string(criteriaType)}, args...)...).Scan(&count)
 		if err != nil {
 			return 0, err
 		}
@@ -357,83 +505,104 @@ func (r *checkMissionRepository) calculateActivityCountProgress(ctx context.Cont
 	return count, nil
 }
 
-func (r *checkMissionRepository) calculateCustomMissionProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType) (float64, error) {
-	switch {
-	case criteriaType == model.CriteriaCar || criteriaType == model.CriteriaMotorcycle ||
-		criteriaType == model.CriteriaBicycle || criteriaType == model.CriteriaPublicTransport ||
-		criteriaType == model.CriteriaWalk:
-		// Hitung jarak kendaraan tertentu
-		var totalDistance float64
-		query := `
-			SELECT COALESCE(SUM(distance_km), 0) 
-			FROM carbon_vehicle_logs cvl
-			JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id
-			WHERE cv.user_id = $1 AND cv.vehicle_type = $2
-		`
-		err := r.db.QueryRowContext(ctx, query, userID, string(criteriaType)).Scan(&totalDistance)
+func (r *checkMissionRepository) calculateCustomMissionProgress(ctx context.Context, userID int64, criteriaType model.MissionCriteriaType, w category.Window) (float64, error) {
+	// Progres kategori karbon (jarak, jam, kg, ...) ditentukan oleh kategorinya
+	if cat, ok := category.ForCriteria(criteriaType); ok {
+		var progress float64
+		q := cat.ProgressQuery(criteriaType, w)
+		err := r.db.QueryRowContext(ctx, q.SQL, append([]any{userID}, q.Args...)...).Scan(&progress)
 		if err != nil {
 			return 0, err
 		}
-		return totalDistance, nil
-
-	case criteriaType == model.CriteriaLaptop || criteriaType == model.CriteriaDesktop ||
-		criteriaType == model.CriteriaTV || criteriaType == model.CriteriaAC ||
-		criteriaType == model.CriteriaFridge || criteriaType == model.CriteriaFan ||
-		criteriaType == model.CriteriaWashingMachine || criteriaType == model.CriteriaOther:
-		// Hitung jam penggunaan elektronik tertentu
-		var totalHours float64
-		query := `
-			SELECT COALESCE(SUM(duration_hours), 0) 
-			FROM carbon_electronics_logs cel
-			JOIN carbon_electronics ce ON cel.device_id = ce.id
-			WHERE ce.user_id = $1 AND ce.device_type = $2
-		`
-		err := r.db.QueryRowContext(ctx, query, userID, string(criteriaType)).Scan(&totalHours)
-		if err != nil {
-			return 0, err
-		}
-		return totalHours, nil
+		return progress, nil
+	}
 
-	default:
-		// Default: hitung points earned
-		var pointsEarned float64
-		query := `
-			SELECT COALESCE(SUM(amount), 0) 
-			FROM point_transactions 
-			WHERE user_id = $1 AND direction = 'in'
-		`
-		err := r.db.QueryRowContext(ctx, query, userID).Scan(&pointsEarned)
-		if err != nil {
-			return 0, err
-		}
-		return pointsEarned, nil
+	// Default: hitung points earned
+	var pointsEarned float64
+	cond, args := w.Filter("created_at", 2)
+	query := `
+		SELECT COALESCE(SUM(amount), 0) 
+		FROM point_transactions 
+		WHERE user_id = $1 AND direction = 'in'` + cond
+	err := r.db.QueryRowContext(ctx, query, append([]any{userID}, args...)...).Scan(&pointsEarned)
+	if err != nil {
+		return 0, err
+	}
+	return pointsEarned, nil
+}
+
+func (r *checkMissionRepository) calculateRuleProgress(ctx context.Context, userID int64, mission *model.Mission, w category.Window) (float64, error) {
+	missionRule, err := rule.Parse([]byte(mission.Rule.String))
+	if err != nil {
+		return 0, err
 	}
+	q, err := missionRule.Compile(w)
+	if err != nil {
+		return 0, err
+	}
+
+	var progress float64
+	err = r.db.QueryRowContext(ctx, q.SQL, append([]any{userID}, q.Args...)...).Scan(&progress)
+	return progress, err
 }
 
 // =========================
 // Main Mission Checking Logic
 // =========================
 func (r *checkMissionRepository) CheckMission(ctx context.Context, userID int64, mission *model.Mission) (bool, error) {
+	now := time.Now()
+
 	// Hitung progress berdasarkan mission type dan criteria type
-	progress, err := r.calculateMissionProgress(ctx, userID, mission)
+	progress, err := r.calculateMissionProgress(ctx, userID, mission, now)
 	if err != nil {
 		return false, err
 	}
 
 	// Update progress di user_mission_progress
-	if err := r.UpdateMissionProgress(ctx, userID, mission.ID, progress); err != nil {
+	if err := r.saveProgress(ctx, userID, mission, progress, now); err != nil {
 		return false, err
 	}
 
+	return r.evaluateCompletion(ctx, userID, mission, progress, now)
+}
+
+// evaluateCompletion menyelesaikan misi kalau progress sudah mencapai target
+func (r *checkMissionRepository) evaluateCompletion(ctx context.Context, userID int64, mission *model.Mission, progress float64, now time.Time) (bool, error) {
+	// Misi tim/komunitas: progres user hanya kontribusi ke target bersama
+	if mission.IsCollective() {
+		return r.evaluateCollective(ctx, userID, mission, progress, now)
+	}
+
+	// Misi lte/between dinilai saat window ditutup (SettleClosedWindows)
+	if mission.SettlesAtWindowEnd() {
+		return false, nil
+	}
+
 	// Check jika mission completed
-	if progress >= mission.TargetValue {
-		completed, err := r.HasUserCompletedMission(ctx, userID, mission.ID)
+	if mission.Meets(progress) {
+		completed, err := r.completedInPeriod(ctx, userID, mission, now)
 		if err != nil {
 			return false, err
 		}
 
 		if !completed {
-			return r.completeMission(ctx, userID, mission)
+			done, err := r.completeMission(ctx, userID, mission, progress, now)
+			if err != nil || !done {
+				return done, err
+			}
+
+			// Poin hadiah bisa memajukan misi lain
+			if err := r.Publish(ctx, model.MissionEvent{
+				Type:       model.EventPointsEarned,
+				UserID:     userID,
+				Points:     float64(mission.PointsReward),
+				SourceType: "missions",
+				SourceID:   mission.ID,
+				OccurredAt: time.Now(),
+			}); err != nil {
+				return true, err
+			}
+			return true, nil
 		}
 		return true, nil
 	}
@@ -441,53 +610,121 @@ func (r *checkMissionRepository) CheckMission(ctx context.Context, userID int64,
 	return false, nil
 }
 
-func (r *checkMissionRepository) completeMission(ctx context.Context, userID int64, mission *model.Mission) (bool, error) {
-	// Assign mission ke user
-	if err := r.AssignMissionToUser(ctx, userID, mission.ID); err != nil {
+func (r *checkMissionRepository) completeMission(ctx context.Context, userID int64, mission *model.Mission, progress float64, now time.Time) (bool, error) {
+	// Semua langkah hadiah dalam satu transaksi supaya tidak ada misi setengah dibayar
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Kunci baris user_missions: cek paralel untuk user & misi yang sama antre di sini
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_missions (user_id, mission_id, created_at, enrolled_at, status, joined_via, status_changed_at)
+		VALUES ($1, $2, $3, $3, 'active', 'auto', $3)
+		ON CONFLICT (user_id, mission_id) DO NOTHING
+	`, userID, mission.ID, time.Now()); err != nil {
 		return false, err
 	}
 
-	// Mark as completed
-	if err := r.MarkMissionCompleted(ctx, userID, mission.ID); err != nil {
+	var completedAt sql.NullTime
+	var status model.UserMissionStatus
+	if err := tx.QueryRowContext(ctx, `
+		SELECT completed_at, status FROM user_missions
+		WHERE user_id = $1 AND mission_id = $2
+		FOR UPDATE
+	`, userID, mission.ID).Scan(&completedAt, &status); err != nil {
 		return false, err
 	}
+	completedBefore := completedAt.Valid
 
-	// Give points reward
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO points (user_id, total_points, created_at) 
-		VALUES ($1, $2, $3)
-		ON CONFLICT (user_id) 
-		DO UPDATE SET total_points = points.total_points + $4
-	`, userID, mission.PointsReward, time.Now(), mission.PointsReward)
-	if err != nil {
+	// Misi yang sudah ditinggalkan atau ditutup tidak bisa diselesaikan lagi
+	if status != model.UserMissionActive {
+		return false, nil
+	}
+
+	if mission.IsRecurring() {
+		// Misi berulang: poin hanya sekali per periode
+		claimed, err := r.claimPeriodCompletion(ctx, tx, userID, mission, progress, now)
+		if err != nil || !claimed {
+			return false, err
+		}
+	} else if completedBefore {
+		return false, nil
+	}
+
+	// Mark as completed; misi berulang tetap aktif untuk periode berikutnya
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE user_missions
+		SET completed_at = $1,
+		    status = CASE WHEN $4 THEN status ELSE 'completed' END,
+		    status_changed_at = CASE WHEN $4 THEN status_changed_at ELSE $1 END
+		WHERE user_id = $2 AND mission_id = $3
+	`, time.Now(), userID, mission.ID, mission.IsRecurring()); err != nil {
 		return false, err
 	}
 
-	// Record point transaction
-	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO point_transactions (user_id, amount, direction, source, reference_type, reference_id, created_at)
-		VALUES ($1, $2, 'in', 'mission_completion', 'missions', $3, $4)
-	`, userID, mission.PointsReward, mission.ID, time.Now())
+	// Record point transaction; idempotency_key menolak pembayaran ganda
+	var txID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO point_transactions (user_id, amount, direction, source, reference_type, reference_id, created_at, idempotency_key)
+		VALUES ($1, $2, 'in', 'mission_completion', 'missions', $3, $4, $5)
+		ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+		RETURNING id
+	`, userID, mission.PointsReward, mission.ID, time.Now(), completionKey(userID, mission, now)).Scan(&txID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Give points reward
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO points (user_id, total_points, created_at) 
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) 
+		DO UPDATE SET total_points = points.total_points + EXCLUDED.total_points
+	`, userID, mission.PointsReward, time.Now()); err != nil {
 		return false, err
 	}
 
 	// Give badge jika applicable
 	if mission.GivesBadge && mission.BadgeID.Valid {
-		_, err = r.db.ExecContext(ctx, `
+		if _, err := tx.ExecContext(ctx, `
 			INSERT INTO user_badges (user_id, badge_id, redeemed_at, created_at) 
 			VALUES ($1, $2, NULL, $3)
 			ON CONFLICT (user_id, badge_id) 
 			DO NOTHING
-		`, userID, mission.BadgeID.Int64, time.Now())
-		if err != nil {
+		`, userID, mission.BadgeID.Int64, time.Now()); err != nil {
 			return false, err
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	// Buka misi lanjutan di storyline
+	if !completedBefore {
+		if err := r.unlockDependents(ctx, userID, mission, time.Now()); err != nil {
+			return true, err
+		}
+	}
+
 	return true, nil
 }
 
+// completionKey kunci idempoten hadiah misi: per misi, atau per periode untuk misi berulang
+func completionKey(userID int64, mission *model.Mission, now time.Time) string {
+	if period, ok := mission.Period(now); ok {
+		return fmt.Sprintf("mission:%d:%d:%d", mission.ID, userID, period.Start.Unix())
+	}
+	return fmt.Sprintf("mission:%d:%d", mission.ID, userID)
+}
+
+// CheckAllUserMissions menghitung ulang seluruh misi aktif dari data mentah.
+// Dipakai sebagai fallback untuk memperbaiki progres, alur normal lewat Publish.
 func (r *checkMissionRepository) CheckAllUserMissions(ctx context.Context, userID int64) error {
 	// Dapatkan semua mission aktif
 	missions, err := r.FindActiveMissions(ctx)
@@ -495,6 +732,12 @@ func (r *checkMissionRepository) CheckAllUserMissions(ctx context.Context, userI
 		return err
 	}
 
+	// Hanya misi yang diikuti user (termasuk auto-join)
+	missions, err = r.evaluableMissions(ctx, userID, missions, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Check setiap mission
 	for _, mission := range missions {
 		completed, err := r.CheckMission(ctx, userID, mission)
@@ -517,6 +760,12 @@ func (r *checkMissionRepository) CheckUserMissionsByType(ctx context.Context, us
 		return err
 	}
 
+	// Hanya misi yang diikuti user (termasuk auto-join)
+	missions, err = r.evaluableMissions(ctx, userID, missions, time.Now())
+	if err != nil {
+		return err
+	}
+
 	for _, mission := range missions {
 		completed, err := r.CheckMission(ctx, userID, mission)
 		if err != nil {
@@ -538,6 +787,12 @@ func (r *checkMissionRepository) CheckUserMissionsByCriteriaType(ctx context.Con
 		return err
 	}
 
+	// Hanya misi yang diikuti user (termasuk auto-join)
+	missions, err = r.evaluableMissions(ctx, userID, missions, time.Now())
+	if err != nil {
+		return err
+	}
+
 	for _, mission := range missions {
 		completed, err := r.CheckMission(ctx, userID, mission)
 		if err != nil {
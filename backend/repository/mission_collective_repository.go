@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Team & Community Missions
+// =========================
+
+// collectiveGroups grup tempat progres user dikumpulkan: komunitas = satu grup tanpa tim,
+// misi tim = semua tim yang diikuti user
+func (r *checkMissionRepository) collectiveGroups(ctx context.Context, userID int64, mission *model.Mission) ([]sql.NullInt64, error) {
+	if mission.Scope == model.MissionScopeCommunity {
+		return []sql.NullInt64{{}}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT team_id FROM team_members WHERE user_id = $1 ORDER BY team_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []sql.NullInt64
+	for rows.Next() {
+		var teamID int64
+		if err := rows.Scan(&teamID); err != nil {
+			return nil, err
+		}
+		groups = append(groups, sql.NullInt64{Int64: teamID, Valid: true})
+	}
+	return groups, rows.Err()
+}
+
+// evaluateCollective menyimpan progres user sebagai kontribusi ke grupnya. Total grup diperbarui
+// dengan selisih kontribusi saja, jadi tidak perlu menjumlah ulang semua anggota.
+func (r *checkMissionRepository) evaluateCollective(ctx context.Context, userID int64, mission *model.Mission, progress float64, now time.Time) (bool, error) {
+	groups, err := r.collectiveGroups(ctx, userID, mission)
+	if err != nil {
+		return false, err
+	}
+
+	completed := false
+	for _, teamID := range groups {
+		total, done, err := r.contribute(ctx, userID, mission, teamID, progress, now)
+		if err != nil {
+			return completed, err
+		}
+		if done {
+			// Grup sudah selesai; bayar ulang kontributor yang hadiahnya sempat gagal
+			if err := r.retryCollectivePayout(ctx, userID, mission, teamID, now); err != nil {
+				return completed, err
+			}
+			completed = true
+			continue
+		}
+		if total < mission.TargetValue {
+			continue
+		}
+
+		done, err = r.completeCollective(ctx, mission, teamID, now)
+		if err != nil {
+			return completed, err
+		}
+		completed = completed || done
+	}
+	return completed, nil
+}
+
+func (r *checkMissionRepository) contribute(ctx context.Context, userID int64, mission *model.Mission, teamID sql.NullInt64, progress float64, now time.Time) (float64, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO mission_contributions (mission_id, team_id, user_id, contribution, updated_at)
+		VALUES ($1, $2, $3, 0, $4)
+		ON CONFLICT (mission_id, (COALESCE(team_id, 0)), user_id) DO NOTHING
+	`, mission.ID, teamID, userID, now); err != nil {
+		return 0, false, err
+	}
+
+	var previous float64
+	if err := tx.QueryRowContext(ctx, `
+		SELECT contribution FROM mission_contributions
+		WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint AND user_id = $3
+		FOR UPDATE
+	`, mission.ID, teamID, userID).Scan(&previous); err != nil {
+		return 0, false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE mission_contributions SET contribution = $4, updated_at = $5
+		WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint AND user_id = $3
+	`, mission.ID, teamID, userID, progress, now); err != nil {
+		return 0, false, err
+	}
+
+	var total float64
+	var done bool
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO mission_collective_progress (mission_id, team_id, progress_value, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (mission_id, (COALESCE(team_id, 0))) DO UPDATE
+		SET progress_value = mission_collective_progress.progress_value + EXCLUDED.progress_value,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING progress_value, completed_at IS NOT NULL
+	`, mission.ID, teamID, progress-previous, now).Scan(&total, &done); err != nil {
+		return 0, false, err
+	}
+
+	return total, done, tx.Commit()
+}
+
+// completeCollective menandai grup selesai (sekali saja) lalu membagikan hadiah ke kontributor
+// yang memenuhi kontribusi minimum. Anggota yang kontribusinya kurang langsung dianggap gagal
+// dalam transaksi yang sama; kontributor yang hadiahnya gagal dibayar tetap aktif dan dibayar
+// ulang saat misinya dievaluasi lagi (rekonsiliasi), dijaga idempotency_key completeMission.
+func (r *checkMissionRepository) completeCollective(ctx context.Context, mission *model.Mission, teamID sql.NullInt64, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE mission_collective_progress SET completed_at = $3
+		WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint
+		  AND completed_at IS NULL AND progress_value >= $4
+		RETURNING id
+	`, mission.ID, teamID, now, mission.TargetValue).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Sudah diklaim proses lain
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Anggota yang kontribusinya kurang tidak dapat hadiah, misinya dianggap gagal
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE user_missions um
+		SET status = 'failed', status_changed_at = $3
+		WHERE um.mission_id = $1 AND um.status = 'active'
+		  AND ($2::bigint IS NULL OR um.user_id IN (SELECT user_id FROM team_members WHERE team_id = $2::bigint))
+		  AND NOT EXISTS (
+		      SELECT 1 FROM mission_contributions mc
+		      WHERE mc.mission_id = um.mission_id AND mc.team_id IS NOT DISTINCT FROM $2::bigint
+		        AND mc.user_id = um.user_id AND mc.contribution > 0 AND mc.contribution >= $4
+		  )
+	`, mission.ID, teamID, now, mission.MinContribution); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, contribution FROM mission_contributions
+		WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint
+		  AND contribution > 0 AND contribution >= $3
+	`, mission.ID, teamID, mission.MinContribution)
+	if err != nil {
+		return true, err
+	}
+
+	type contributor struct {
+		userID       int64
+		contribution float64
+	}
+	var contributors []contributor
+	for rows.Next() {
+		var c contributor
+		if err := rows.Scan(&c.userID, &c.contribution); err != nil {
+			rows.Close()
+			return true, err
+		}
+		contributors = append(contributors, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return true, err
+	}
+
+	for _, c := range contributors {
+		if err := r.payCollectiveContributor(ctx, c.userID, mission, c.contribution, now); err != nil {
+			fmt.Printf("Gagal membagikan hadiah mission %d ke user %d: %v\n", mission.ID, c.userID, err)
+		}
+	}
+	return true, nil
+}
+
+// retryCollectivePayout membayar user yang memenuhi kontribusi minimum di grup yang sudah
+// selesai tapi misinya masih aktif, yaitu yang pembayarannya gagal di completeCollective
+func (r *checkMissionRepository) retryCollectivePayout(ctx context.Context, userID int64, mission *model.Mission, teamID sql.NullInt64, now time.Time) error {
+	var contribution float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT mc.contribution
+		FROM mission_collective_progress cp
+		JOIN mission_contributions mc
+		  ON mc.mission_id = cp.mission_id AND mc.team_id IS NOT DISTINCT FROM cp.team_id AND mc.user_id = $3
+		JOIN user_missions um
+		  ON um.mission_id = cp.mission_id AND um.user_id = $3 AND um.status = 'active'
+		WHERE cp.mission_id = $1 AND cp.team_id IS NOT DISTINCT FROM $2::bigint
+		  AND cp.completed_at IS NOT NULL AND um.enrolled_at <= cp.completed_at
+		  AND mc.contribution > 0 AND mc.contribution >= $4
+	`, mission.ID, teamID, userID, mission.MinContribution).Scan(&contribution)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return r.payCollectiveContributor(ctx, userID, mission, contribution, now)
+}
+
+// payCollectiveContributor memberi hadiah misi bersama ke satu kontributor beserta notifikasinya
+func (r *checkMissionRepository) payCollectiveContributor(ctx context.Context, userID int64, mission *model.Mission, contribution float64, now time.Time) error {
+	done, err := r.completeMission(ctx, userID, mission, contribution, now)
+	if err != nil || !done {
+		return err
+	}
+
+	if err := NewNotificationRepo(r.db).Create(ctx, &model.Notification{
+		UserID:    userID,
+		Title:     "Misi bersama selesai",
+		Message:   fmt.Sprintf("Target bersama \"%s\" tercapai! Kamu mendapat %d poin.", mission.Title, mission.PointsReward),
+		CreatedAt: now,
+	}); err != nil {
+		fmt.Printf("Gagal membuat notifikasi mission %d untuk user %d: %v\n", mission.ID, userID, err)
+	}
+
+	// Poin hadiah bisa memajukan misi lain
+	if err := r.Publish(ctx, model.MissionEvent{
+		Type:       model.EventPointsEarned,
+		UserID:     userID,
+		Points:     float64(mission.PointsReward),
+		SourceType: "missions",
+		SourceID:   mission.ID,
+		OccurredAt: time.Now(),
+	}); err != nil {
+		fmt.Printf("Gagal publish points_earned untuk user %d: %v\n", userID, err)
+	}
+	return nil
+}
+
+// FindCollectiveStandings progres kolektif setiap grup yang diikuti user untuk satu misi
+func (r *checkMissionRepository) FindCollectiveStandings(ctx context.Context, userID int64, mission *model.Mission, limit int) ([]*model.CollectiveStanding, error) {
+	groups, err := r.collectiveGroups(ctx, userID, mission)
+	if err != nil {
+		return nil, err
+	}
+
+	var standings []*model.CollectiveStanding
+	for _, teamID := range groups {
+		standing := &model.CollectiveStanding{
+			Progress: model.CollectiveProgress{MissionID: mission.ID, TeamID: teamID},
+		}
+
+		err := r.db.QueryRowContext(ctx, `
+			SELECT id, progress_value, completed_at, updated_at
+			FROM mission_collective_progress
+			WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint
+		`, mission.ID, teamID).Scan(
+			&standing.Progress.ID, &standing.Progress.ProgressValue,
+			&standing.Progress.CompletedAt, &standing.Progress.UpdatedAt,
+		)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		if teamID.Valid {
+			if err := r.db.QueryRowContext(ctx, `SELECT name FROM teams WHERE id = $1`, teamID.Int64).Scan(&standing.TeamName); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+		}
+
+		if err := r.db.QueryRowContext(ctx, `
+			SELECT COALESCE(MAX(contribution), 0) FROM mission_contributions
+			WHERE mission_id = $1 AND team_id IS NOT DISTINCT FROM $2::bigint AND user_id = $3
+		`, mission.ID, teamID, userID).Scan(&standing.MyContribution); err != nil {
+			return nil, err
+		}
+
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT mc.user_id, u.username, mc.contribution, mc.updated_at
+			FROM mission_contributions mc
+			JOIN users u ON u.id = mc.user_id
+			WHERE mc.mission_id = $1 AND mc.team_id IS NOT DISTINCT FROM $2::bigint AND mc.contribution > 0
+			ORDER BY mc.contribution DESC, mc.updated_at ASC
+			LIMIT $3
+		`, mission.ID, teamID, limit)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			c := &model.MissionContribution{MissionID: mission.ID, TeamID: teamID}
+			if err := rows.Scan(&c.UserID, &c.Username, &c.Contribution, &c.UpdatedAt); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			standing.Contributors = append(standing.Contributors, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		standings = append(standings, standing)
+	}
+	return standings, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Mission Dry Run
+// =========================
+
+// FindDryRunUserIDs semua user, atau sampel acak sebanyak sample kalau sample > 0
+func (r *checkMissionRepository) FindDryRunUserIDs(ctx context.Context, sample int) ([]int64, error) {
+	query := `SELECT id FROM users ORDER BY id`
+	args := []any{}
+	if sample > 0 {
+		query = `SELECT id FROM users ORDER BY random() LIMIT $1`
+		args = append(args, sample)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FindCompletedAmong misi (dari missionIDs) yang sudah diselesaikan tiap user
+func (r *checkMissionRepository) FindCompletedAmong(ctx context.Context, missionIDs []int64) (map[int64]map[int64]bool, error) {
+	completed := map[int64]map[int64]bool{}
+	if len(missionIDs) == 0 {
+		return completed, nil
+	}
+
+	placeholders := make([]string, len(missionIDs))
+	args := make([]any, len(missionIDs))
+	for i, id := range missionIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, mission_id FROM user_missions
+		WHERE completed_at IS NOT NULL AND mission_id IN (`+strings.Join(placeholders, ", ")+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID, missionID int64
+		if err := rows.Scan(&userID, &missionID); err != nil {
+			return nil, err
+		}
+		if completed[userID] == nil {
+			completed[userID] = map[int64]bool{}
+		}
+		completed[userID][missionID] = true
+	}
+	return completed, rows.Err()
+}
+
+// DryRunOutcome hasil evaluasi misi draft untuk satu user
+type DryRunOutcome struct {
+	Progress float64
+	// Misi gte: target tercapai. Misi lte/between: lolos kalau window ditutup saat ini
+	// (termasuk jumlah log minimum).
+	Completes bool
+}
+
+// DryRunProgress menghitung progres misi draft untuk tiap user seolah user ikut pada waktu at.
+// Hanya membaca data; user_mission_progress dan user_missions tidak disentuh.
+func (r *checkMissionRepository) DryRunProgress(ctx context.Context, mission *model.Mission, userIDs []int64, at time.Time) (map[int64]DryRunOutcome, error) {
+	from, to := mission.DryRunWindow(at)
+	w := category.Window{From: from, To: to}
+
+	outcomes := make(map[int64]DryRunOutcome, len(userIDs))
+	for _, userID := range userIDs {
+		value, err := r.progressInWindow(ctx, userID, mission, w)
+		if err != nil {
+			return nil, fmt.Errorf("dry run user %d: %w", userID, err)
+		}
+
+		outcome := DryRunOutcome{Progress: value, Completes: mission.Meets(value)}
+		if outcome.Completes && mission.SettlesAtWindowEnd() {
+			logs, err := r.countActivityLogs(ctx, userID, mission, w)
+			if err != nil {
+				return nil, fmt.Errorf("dry run user %d: %w", userID, err)
+			}
+			outcome.Completes = logs >= mission.RequiredActivityLogs()
+		}
+		outcomes[userID] = outcome
+	}
+	return outcomes, nil
+}
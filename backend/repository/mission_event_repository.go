@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/rule"
+)
+
+// =========================
+// Event-driven Mission Evaluation
+// =========================
+
+type subscriptionMode int
+
+const (
+	notSubscribed subscriptionMode = iota
+	incrementProgress
+	recomputeProgress
+)
+
+// subscription menentukan apakah misi terpengaruh event, dan apakah progresnya
+// cukup ditambah delta atau harus dihitung ulang.
+func subscription(mission *model.Mission, event model.MissionEvent) (subscriptionMode, float64) {
+	// Misi aturan JSON selalu dihitung ulang karena filter bisa mempersempit event
+	if mission.Rule.Valid {
+		missionRule, err := rule.Parse([]byte(mission.Rule.String))
+		if err != nil || !missionRule.SubscribesTo(event.Type) {
+			return notSubscribed, 0
+		}
+		return recomputeProgress, 0
+	}
+
+	switch mission.MissionType {
+	case model.MissionTypeStreak:
+		// Login dan check-in harian sama-sama memperpanjang streak
+		if event.Type == model.EventUserLoggedIn || event.Type == model.EventDailyCheckIn {
+			return recomputeProgress, 0
+		}
+
+	case model.MissionTypeActivity:
+		// Activity log ditulis saat login dan order
+		if event.Type == model.EventUserLoggedIn || event.Type == model.EventOrderPlaced {
+			return recomputeProgress, 0
+		}
+
+	case model.MissionTypeCarbonReduction:
+		switch {
+		case mission.CriteriaType == model.CriteriaBaselineReduction:
+			if event.Type == model.EventCarbonLogged || event.Type == model.EventBaselineUpdated {
+				return recomputeProgress, 0
+			}
+		case event.Type != model.EventCarbonLogged:
+		case mission.CriteriaType == "":
+			return incrementProgress, event.Emission
+		case mission.CriteriaType == event.Criteria:
+			return incrementProgress, event.Emission
+		}
+
+	case model.MissionTypeCustom:
+		cat, ok := category.ForCriteria(mission.CriteriaType)
+		if !ok {
+			// Default custom: total points earned
+			if event.Type == model.EventPointsEarned {
+				return incrementProgress, event.Points
+			}
+			return notSubscribed, 0
+		}
+		if event.Type != model.EventCarbonLogged || mission.CriteriaType != event.Criteria {
+			return notSubscribed, 0
+		}
+		if cat.CumulativeProgress() {
+			return incrementProgress, event.Quantity
+		}
+		return recomputeProgress, 0
+	}
+
+	return notSubscribed, 0
+}
+
+// Publish mengevaluasi hanya misi aktif yang berlangganan event ini.
+// Untuk perbaikan data gunakan CheckAllUserMissions (hitung ulang penuh).
+func (r *checkMissionRepository) Publish(ctx context.Context, event model.MissionEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if event.ActivityAt.IsZero() {
+		event.ActivityAt = event.OccurredAt
+	}
+
+	missions, err := r.FindActiveMissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Snapshot progres mencatat event ini sebagai penyebabnya
+	ctx = withProgressEvent(ctx, event)
+
+	// Enroll ke misi auto-join yang belum diikuti; dibulatkan ke bawah per detik
+	// supaya aktivitas yang memicu event tetap masuk window
+	if err := r.enrollActiveMissions(ctx, event.UserID, event.OccurredAt.Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	states, err := r.userMissionStates(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, mission := range missions {
+		state, ok := states[mission.ID]
+		// Hanya misi yang sedang diikuti user yang dievaluasi
+		if !ok || state.status != model.UserMissionActive || state.completedFor(mission, event.OccurredAt) {
+			continue
+		}
+
+		mode, delta := subscription(mission, event)
+		if mode == incrementProgress {
+			// Aktivitas di luar window misi (atau periode berjalan) tidak menambah progres
+			from, to := mission.Window(state.enrolledAt, event.OccurredAt)
+			if !(category.Window{From: from, To: to}).Contains(event.ActivityAt) {
+				continue
+			}
+		}
+
+		var done bool
+		switch mode {
+		case incrementProgress:
+			done, err = r.incrementMissionProgress(ctx, event.UserID, mission, delta, event.OccurredAt)
+		case recomputeProgress:
+			done, err = r.CheckMission(ctx, event.UserID, mission)
+		default:
+			continue
+		}
+
+		if err != nil {
+			fmt.Printf("Gagal evaluasi mission %d untuk event %s: %v\n", mission.ID, event.Type, err)
+			continue
+		}
+		if done {
+			fmt.Printf("User %d completed mission: %s\n", event.UserID, mission.Title)
+		}
+	}
+
+	return nil
+}
+
+// incrementMissionProgress menambah progres secara atomik. Kalau belum ada baris progres
+// (misi baru, belum pernah dihitung, atau periode sudah berganti), progres dihitung penuh
+// sekali sebagai titik awal.
+func (r *checkMissionRepository) incrementMissionProgress(ctx context.Context, userID int64, mission *model.Mission, delta float64, now time.Time) (bool, error) {
+	var periodStart interface{}
+	if period, ok := mission.Period(now); ok {
+		periodStart = period.Start
+	}
+
+	var progress float64
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE user_mission_progress
+		SET progress_value = progress_value + $3, last_updated = $4
+		WHERE user_id = $1 AND mission_id = $2 AND period_start IS NOT DISTINCT FROM $5::timestamptz
+		RETURNING progress_value
+	`, userID, mission.ID, delta, time.Now(), periodStart).Scan(&progress)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.CheckMission(ctx, userID, mission)
+		}
+		return false, err
+	}
+
+	if err := r.recordProgress(ctx, userID, mission, progress-delta, progress, now); err != nil {
+		return false, err
+	}
+
+	return r.evaluateCompletion(ctx, userID, mission, progress, now)
+}
+
+type userMissionState struct {
+	status      model.UserMissionStatus
+	enrolledAt  time.Time
+	completedAt sql.NullTime
+}
+
+// completedFor misi sekali jalan selesai selamanya, misi berulang hanya untuk periodenya
+func (s userMissionState) completedFor(mission *model.Mission, at time.Time) bool {
+	if !s.completedAt.Valid {
+		return false
+	}
+	period, ok := mission.Period(at)
+	if !ok {
+		return true
+	}
+	return !s.completedAt.Time.Before(period.Start)
+}
+
+func (r *checkMissionRepository) enrollActiveMissions(ctx context.Context, userID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_missions (user_id, mission_id, created_at, enrolled_at, status, joined_via, status_changed_at)
+		SELECT $1, id, $2, $2, 'active', 'auto', $2
+		FROM missions
+		WHERE auto_join AND archived_at IS NULL
+		  AND (expired_at IS NULL OR expired_at > $2)
+		  AND (starts_at IS NULL OR starts_at <= $2)
+		  AND `+prerequisitesMetSQL("missions.id", "$1")+`
+		ON CONFLICT (user_id, mission_id) DO NOTHING
+	`, userID, at)
+	return err
+}
+
+func (r *checkMissionRepository) userMissionStates(ctx context.Context, userID int64) (map[int64]userMissionState, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT mission_id, status, enrolled_at, completed_at FROM user_missions WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[int64]userMissionState)
+	for rows.Next() {
+		var id int64
+		var state userMissionState
+		if err := rows.Scan(&id, &state.status, &state.enrolledAt, &state.completedAt); err != nil {
+			return nil, err
+		}
+		states[id] = state
+	}
+	return states, rows.Err()
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Mission Bulk Import
+// =========================
+
+// MissionImportItem satu misi yang dibuat (Mission.ID == 0) atau diubah oleh import
+type MissionImportItem struct {
+	Key     string
+	Mission *model.Mission
+	// Prasyarat berupa key misi yang baru dibuat di import yang sama; urutan item harus
+	// menempatkan misi itu lebih dulu
+	PrerequisiteKeys []string
+	// nil berarti badge misi tidak diubah; ID 0 berarti badge baru
+	Badge *model.Badge
+}
+
+// FindExternalKeys peta external_key -> id untuk semua misi hasil import
+func (r *missionRepository) FindExternalKeys(ctx context.Context) (map[string]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT external_key, id FROM missions WHERE external_key IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := map[string]int64{}
+	for rows.Next() {
+		var key string
+		var id int64
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, err
+		}
+		keys[key] = id
+	}
+	return keys, rows.Err()
+}
+
+// ImportMissions menyimpan seluruh item dalam satu transaksi; kalau satu gagal tidak ada
+// yang tersimpan. ID misi dan badge baru diisi ke item.
+func (r *missionRepository) ImportMissions(ctx context.Context, items []*MissionImportItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	created := map[string]int64{}
+	for _, item := range items {
+		mission := item.Mission
+
+		if badge := item.Badge; badge != nil {
+			if badge.ID == 0 {
+				badge.CreatedAt = time.Now()
+				err = insertBadge(ctx, tx, badge)
+			} else {
+				_, err = tx.ExecContext(ctx, `
+					UPDATE badges SET name = $2, image_url = $3, description = $4 WHERE id = $1
+				`, badge.ID, badge.Name, badge.ImageURL, badge.Description)
+			}
+			if err != nil {
+				return fmt.Errorf("import %s: badge: %w", item.Key, err)
+			}
+			mission.BadgeID = model.NewNullInt64(badge.ID)
+		}
+
+		for _, key := range item.PrerequisiteKeys {
+			id, ok := created[key]
+			if !ok {
+				return fmt.Errorf("import %s: prerequisite %s must be imported first", item.Key, key)
+			}
+			mission.Prerequisites = append(mission.Prerequisites, id)
+		}
+
+		if mission.ID != 0 {
+			if err := updateMission(ctx, tx, mission); err != nil {
+				return fmt.Errorf("import %s: %w", item.Key, err)
+			}
+			continue
+		}
+
+		if err := insertMission(ctx, tx, mission); err != nil {
+			return fmt.Errorf("import %s: %w", item.Key, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE missions SET external_key = $2 WHERE id = $1`, mission.ID, item.Key); err != nil {
+			return fmt.Errorf("import %s: %w", item.Key, err)
+		}
+		created[item.Key] = mission.ID
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// =========================
+// Mission Lifecycle Jobs
+// =========================
+
+// NotifyExpiringMissions mengingatkan user yang misinya berakhir dalam rentang within.
+// Tiap enrollment hanya diingatkan sekali (expiry_notified_at).
+func (r *checkMissionRepository) NotifyExpiringMissions(ctx context.Context, now time.Time, within time.Duration) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH due AS (
+			UPDATE user_missions um
+			SET expiry_notified_at = $1
+			FROM missions m
+			WHERE m.id = um.mission_id
+			  AND um.status = 'active'
+			  AND um.expiry_notified_at IS NULL
+			  AND (um.completed_at IS NULL OR m.recurrence <> 'none')
+			  AND m.archived_at IS NULL
+			  AND m.expired_at > $1 AND m.expired_at <= $2
+			RETURNING um.user_id, m.title
+		)
+		INSERT INTO notifications (user_id, title, message, created_at)
+		SELECT user_id, 'Misi segera berakhir',
+		       'Misi "' || title || '" berakhir dalam kurang dari 24 jam. Selesaikan sebelum terlambat!', $1
+		FROM due
+	`, now, now.Add(within))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FindUsersWithActiveMissions user yang punya misi aktif, untuk rekonsiliasi penuh
+func (r *checkMissionRepository) FindUsersWithActiveMissions(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT um.user_id
+		FROM user_missions um
+		JOIN missions m ON m.id = um.mission_id
+		WHERE um.status = 'active' AND m.archived_at IS NULL
+		ORDER BY um.user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Recurring Mission Periods
+// =========================
+
+// saveProgress menyimpan progres; untuk misi berulang progres periode lama diarsipkan dulu
+func (r *checkMissionRepository) saveProgress(ctx context.Context, userID int64, mission *model.Mission, progress float64, now time.Time) error {
+	period, ok := mission.Period(now)
+	if !ok {
+		previous, err := r.GetMissionProgress(ctx, userID, mission.ID)
+		if err != nil {
+			return err
+		}
+		if err := r.UpdateMissionProgress(ctx, userID, mission.ID, progress); err != nil {
+			return err
+		}
+		return r.recordProgress(ctx, userID, mission, previous, progress, now)
+	}
+
+	if _, err := r.archiveStaleProgress(ctx, mission.ID, period.Start, &userID); err != nil {
+		return err
+	}
+
+	// Progres periode lama sudah diarsipkan, jadi ini progres periode berjalan (0 kalau belum ada)
+	previous, err := r.GetMissionProgress(ctx, userID, mission.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO user_mission_progress(user_id, mission_id, progress_value, last_updated, period_start, period_end)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, mission_id)
+		DO UPDATE SET progress_value = EXCLUDED.progress_value, last_updated = EXCLUDED.last_updated,
+		              period_start = EXCLUDED.period_start, period_end = EXCLUDED.period_end
+	`, userID, mission.ID, progress, time.Now(), period.Start, period.End)
+	if err != nil {
+		return err
+	}
+	return r.recordProgress(ctx, userID, mission, previous, progress, now)
+}
+
+// archiveStaleProgress memindahkan progres dari periode selain periodStart ke user_mission_periods.
+// userID nil berarti semua user.
+func (r *checkMissionRepository) archiveStaleProgress(ctx context.Context, missionID int64, periodStart time.Time, userID *int64) (int64, error) {
+	var uid interface{}
+	if userID != nil {
+		uid = *userID
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		WITH stale AS (
+			DELETE FROM user_mission_progress
+			WHERE mission_id = $1
+			  AND period_start IS DISTINCT FROM $2::timestamptz
+			  AND ($3::bigint IS NULL OR user_id = $3::bigint)
+			RETURNING user_id, mission_id, period_start, period_end, progress_value
+		)
+		INSERT INTO user_mission_periods (user_id, mission_id, period_start, period_end, progress_value, archived_at)
+		SELECT user_id, mission_id, period_start, period_end, progress_value, $4
+		FROM stale
+		WHERE period_start IS NOT NULL
+		ON CONFLICT (user_id, mission_id, period_start)
+		DO UPDATE SET progress_value = EXCLUDED.progress_value, archived_at = EXCLUDED.archived_at
+	`, missionID, periodStart, uid, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// completedInPeriod cek apakah misi sudah selesai; misi berulang dicek per periode
+func (r *checkMissionRepository) completedInPeriod(ctx context.Context, userID int64, mission *model.Mission, now time.Time) (bool, error) {
+	period, ok := mission.Period(now)
+	if !ok {
+		return r.HasUserCompletedMission(ctx, userID, mission.ID)
+	}
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM user_mission_periods
+			WHERE user_id = $1 AND mission_id = $2 AND period_start = $3 AND completed_at IS NOT NULL
+		)
+	`, userID, mission.ID, period.Start).Scan(&exists)
+	return exists, err
+}
+
+// claimPeriodCompletion mencatat penyelesaian periode berjalan. False kalau periode ini
+// sudah pernah diselesaikan, jadi poin tidak diberikan dua kali.
+func (r *checkMissionRepository) claimPeriodCompletion(ctx context.Context, tx *sql.Tx, userID int64, mission *model.Mission, progress float64, now time.Time) (bool, error) {
+	period, ok := mission.Period(now)
+	if !ok {
+		return false, fmt.Errorf("mission %d tidak punya periode aktif", mission.ID)
+	}
+
+	var id int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO user_mission_periods
+			(user_id, mission_id, period_start, period_end, progress_value, completed_at, points_awarded)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, mission_id, period_start)
+		DO UPDATE SET progress_value = EXCLUDED.progress_value,
+		              completed_at = EXCLUDED.completed_at,
+		              points_awarded = EXCLUDED.points_awarded
+		WHERE user_mission_periods.completed_at IS NULL
+		RETURNING id
+	`, userID, mission.ID, period.Start, period.End, progress, time.Now(), mission.PointsReward).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// RollOverRecurringMissions mengarsipkan progres periode yang sudah lewat untuk semua
+// misi berulang yang aktif. Evaluasi juga melakukan rollover per user, jadi ini hanya
+// menjaga progres lama tidak tertinggal kalau user tidak aktif.
+func (r *checkMissionRepository) RollOverRecurringMissions(ctx context.Context, now time.Time) (int64, error) {
+	missions, err := r.FindActiveMissions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var archived int64
+	for _, mission := range missions {
+		period, ok := mission.Period(now)
+		if !ok {
+			continue
+		}
+
+		n, err := r.archiveStaleProgress(ctx, mission.ID, period.Start, nil)
+		if err != nil {
+			return archived, fmt.Errorf("rollover mission %d: %w", mission.ID, err)
+		}
+		archived += n
+	}
+	return archived, nil
+}
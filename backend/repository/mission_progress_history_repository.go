@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Mission Progress History
+// =========================
+
+type progressEventKey struct{}
+
+// withProgressEvent menandai ctx dengan event yang sedang dievaluasi supaya
+// snapshot progres tahu apa yang memindahkan progres
+func withProgressEvent(ctx context.Context, event model.MissionEvent) context.Context {
+	return context.WithValue(ctx, progressEventKey{}, event)
+}
+
+// recordProgress menyimpan snapshot kalau progres benar-benar berubah
+func (r *checkMissionRepository) recordProgress(ctx context.Context, userID int64, mission *model.Mission, previous, progress float64, now time.Time) error {
+	delta := progress - previous
+	if math.Abs(delta) < 1e-9 {
+		return nil
+	}
+
+	trigger := model.ProgressTriggerRecompute
+	var eventType, sourceType sql.NullString
+	var sourceID sql.NullInt64
+	if event, ok := ctx.Value(progressEventKey{}).(model.MissionEvent); ok {
+		trigger = model.ProgressTriggerEvent
+		eventType = model.NewNullString(string(event.Type))
+		if event.SourceType != "" {
+			sourceType = model.NewNullString(event.SourceType)
+		}
+		if event.SourceID != 0 {
+			sourceID = model.NewNullInt64(event.SourceID)
+		}
+	}
+
+	var periodStart sql.NullTime
+	if period, ok := mission.Period(now); ok {
+		periodStart = model.NewNullTime(period.Start)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO mission_progress_snapshots
+			(user_id, mission_id, progress_value, delta, trigger, event_type, source_type, source_id, period_start, recorded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, userID, mission.ID, progress, delta, trigger, eventType, sourceType, sourceID, periodStart, time.Now())
+	return err
+}
+
+// FindProgressHistory riwayat progres user untuk satu misi, terbaru dulu
+func (r *checkMissionRepository) FindProgressHistory(ctx context.Context, userID, missionID int64, limit int) ([]*model.MissionProgressSnapshot, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, mission_id, progress_value, delta, trigger, event_type,
+		       source_type, source_id, period_start, recorded_at
+		FROM mission_progress_snapshots
+		WHERE user_id = $1 AND mission_id = $2
+		ORDER BY recorded_at DESC, id DESC
+		LIMIT $3
+	`, userID, missionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*model.MissionProgressSnapshot
+	for rows.Next() {
+		s := &model.MissionProgressSnapshot{}
+		if err := rows.Scan(&s.ID, &s.UserID, &s.MissionID, &s.ProgressValue, &s.Delta, &s.Trigger, &s.EventType,
+			&s.SourceType, &s.SourceID, &s.PeriodStart, &s.RecordedAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
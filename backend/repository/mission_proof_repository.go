@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Proof-of-action Missions
+// =========================
+
+var (
+	ErrProofPending          = errors.New("a proof submission for this mission is already waiting for review")
+	ErrProofAlreadyCompleted = errors.New("mission is already completed for this period")
+	ErrProofNotFound         = errors.New("proof submission not found")
+	ErrProofAlreadyReviewed  = errors.New("proof submission has already been reviewed")
+)
+
+const proofColumns = `ps.id, ps.mission_id, ps.user_id, ps.photo_url, ps.note, ps.status, ps.review_note,
+	ps.reviewed_by, ps.reviewed_at, ps.period_start, ps.created_at`
+
+func scanProof(scanner interface{ Scan(...any) error }, p *model.MissionProofSubmission, extra ...any) error {
+	return scanner.Scan(append([]any{&p.ID, &p.MissionID, &p.UserID, &p.PhotoURL, &p.Note, &p.Status, &p.ReviewNote,
+		&p.ReviewedBy, &p.ReviewedAt, &p.PeriodStart, &p.CreatedAt}, extra...)...)
+}
+
+// calculateProofProgress jumlah bukti yang disetujui di dalam window misi
+func (r *checkMissionRepository) calculateProofProgress(ctx context.Context, userID, missionID int64, w category.Window) (float64, error) {
+	cond, args := w.Filter("created_at", 3)
+	var count float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM mission_proof_submissions
+		WHERE user_id = $1 AND mission_id = $2 AND status = 'approved'`+cond,
+		append([]any{userID, missionID}, args...)...).Scan(&count)
+	return count, err
+}
+
+// SubmitProof menyimpan bukti baru ke antrean moderasi. User harus sedang mengikuti misi
+// dan belum menyelesaikannya di periode berjalan.
+func (r *checkMissionRepository) SubmitProof(ctx context.Context, userID int64, mission *model.Mission, photoURL, note string, now time.Time) (*model.MissionProofSubmission, error) {
+	active, err := r.evaluableMissions(ctx, userID, []*model.Mission{mission}, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		return nil, ErrMissionNotJoined
+	}
+
+	completed, err := r.completedInPeriod(ctx, userID, mission, now)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		return nil, ErrProofAlreadyCompleted
+	}
+
+	var periodStart sql.NullTime
+	if period, ok := mission.Period(now); ok {
+		periodStart = model.NewNullTime(period.Start)
+	}
+
+	proof := &model.MissionProofSubmission{
+		MissionID:   mission.ID,
+		UserID:      userID,
+		PhotoURL:    photoURL,
+		Note:        note,
+		Status:      model.ProofPending,
+		PeriodStart: periodStart,
+		CreatedAt:   now,
+	}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO mission_proof_submissions (mission_id, user_id, photo_url, note, status, period_start, created_at)
+		VALUES ($1, $2, $3, $4, 'pending', $5, $6)
+		ON CONFLICT (user_id, mission_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id
+	`, mission.ID, userID, photoURL, note, periodStart, now).Scan(&proof.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProofPending
+		}
+		return nil, err
+	}
+	return proof, nil
+}
+
+// FindUserProofs riwayat bukti user untuk satu misi, terbaru dulu
+func (r *checkMissionRepository) FindUserProofs(ctx context.Context, userID, missionID int64) ([]*model.MissionProofSubmission, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+proofColumns+`
+		FROM mission_proof_submissions ps
+		WHERE ps.user_id = $1 AND ps.mission_id = $2
+		ORDER BY ps.created_at DESC, ps.id DESC
+	`, userID, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proofs []*model.MissionProofSubmission
+	for rows.Next() {
+		p := &model.MissionProofSubmission{}
+		if err := scanProof(rows, p); err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, p)
+	}
+	return proofs, rows.Err()
+}
+
+// FindProofQueue antrean moderasi; status kosong berarti semua status. Yang paling lama menunggu dulu.
+func (r *checkMissionRepository) FindProofQueue(ctx context.Context, status model.ProofStatus, page, limit int) ([]*model.MissionProofSubmission, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+proofColumns+`, m.title, u.username
+		FROM mission_proof_submissions ps
+		JOIN missions m ON m.id = ps.mission_id
+		JOIN users u ON u.id = ps.user_id
+		WHERE ($1 = '' OR ps.status = $1)
+		ORDER BY ps.created_at ASC, ps.id ASC
+		LIMIT $2 OFFSET $3
+	`, string(status), limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proofs []*model.MissionProofSubmission
+	for rows.Next() {
+		p := &model.MissionProofSubmission{}
+		if err := scanProof(rows, p, &p.MissionTitle, &p.Username); err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, p)
+	}
+	return proofs, rows.Err()
+}
+
+// ReviewProof menyetujui atau menolak bukti (sekali saja). Bukti yang disetujui langsung
+// dievaluasi lewat jalur penyelesaian biasa; user diberi notifikasi apa pun hasilnya.
+func (r *checkMissionRepository) ReviewProof(ctx context.Context, proofID, reviewerID int64, approve bool, note string, now time.Time) (*model.MissionProofSubmission, bool, error) {
+	status := model.ProofRejected
+	if approve {
+		status = model.ProofApproved
+	}
+
+	proof := &model.MissionProofSubmission{}
+	err := scanProof(r.db.QueryRowContext(ctx, `
+		UPDATE mission_proof_submissions ps
+		SET status = $2, review_note = $3, reviewed_by = $4, reviewed_at = $5
+		WHERE ps.id = $1 AND ps.status = 'pending'
+		RETURNING `+proofColumns,
+		proofID, status, note, reviewerID, now), proof)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, err
+		}
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM mission_proof_submissions WHERE id = $1)`, proofID).Scan(&exists); err != nil {
+			return nil, false, err
+		}
+		if !exists {
+			return nil, false, ErrProofNotFound
+		}
+		return nil, false, ErrProofAlreadyReviewed
+	}
+
+	mission, err := r.FindByID(ctx, proof.MissionID)
+	if err != nil {
+		return proof, false, err
+	}
+	if mission == nil {
+		return proof, false, ErrProofNotFound
+	}
+
+	var completed bool
+	if approve {
+		completed, err = r.completeWithProof(ctx, proof, mission, now)
+		if err != nil {
+			return proof, false, err
+		}
+	}
+
+	title, message := "Bukti misi ditolak", fmt.Sprintf("Bukti untuk misi \"%s\" ditolak.", mission.Title)
+	if approve {
+		title, message = "Bukti misi disetujui", fmt.Sprintf("Bukti untuk misi \"%s\" disetujui!", mission.Title)
+	}
+	if note != "" {
+		message += " Catatan: " + note
+	}
+	if err := NewNotificationRepo(r.db).Create(ctx, &model.Notification{
+		UserID:    proof.UserID,
+		Title:     title,
+		Message:   message,
+		CreatedAt: now,
+	}); err != nil {
+		return proof, completed, err
+	}
+
+	return proof, completed, nil
+}
+
+// completeWithProof menghitung progres pada waktu bukti dikirim, supaya bukti yang baru
+// disetujui setelah periode (atau misi) berakhir tetap masuk ke periode asalnya
+func (r *checkMissionRepository) completeWithProof(ctx context.Context, proof *model.MissionProofSubmission, mission *model.Mission, now time.Time) (bool, error) {
+	ctx = withProgressEvent(ctx, model.MissionEvent{
+		Type:       model.EventProofApproved,
+		UserID:     proof.UserID,
+		SourceType: "mission_proof_submissions",
+		SourceID:   proof.ID,
+		OccurredAt: now,
+	})
+
+	at := proof.CreatedAt
+	progress, err := r.calculateMissionProgress(ctx, proof.UserID, mission, at)
+	if err != nil {
+		return false, err
+	}
+
+	// Progres tersimpan hanya diperbarui kalau bukti masih di periode berjalan
+	current, ok := mission.Period(now)
+	submitted, _ := mission.Period(at)
+	if !ok || current.Start.Equal(submitted.Start) {
+		if err := r.saveProgress(ctx, proof.UserID, mission, progress, now); err != nil {
+			return false, err
+		}
+	}
+
+	return r.evaluateCompletion(ctx, proof.UserID, mission, progress, at)
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Quiz Missions
+// =========================
+
+var (
+	ErrQuizAlreadyPassed = errors.New("quiz is already passed for this period")
+	ErrQuizAttemptLimit  = errors.New("no quiz attempts left for this period")
+	ErrQuizCooldown      = errors.New("quiz is cooling down")
+)
+
+// calculateQuizProgress jumlah lesson berbeda yang kuisnya lulus di dalam window misi
+func (r *checkMissionRepository) calculateQuizProgress(ctx context.Context, userID, missionID int64, w category.Window) (float64, error) {
+	cond, args := w.Filter("created_at", 3)
+	var count float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT lesson_id) FROM quiz_attempts
+		WHERE user_id = $1 AND mission_id = $2 AND passed`+cond,
+		append([]any{userID, missionID}, args...)...).Scan(&count)
+	return count, err
+}
+
+// SubmitQuizAttempt menyimpan percobaan yang sudah dinilai setelah memeriksa batas percobaan
+// dan cooldown. Percobaan yang lulus dievaluasi ke misi quiz lesson (kalau user sedang
+// mengikutinya) lewat jalur penyelesaian biasa. mission boleh nil.
+func (r *checkMissionRepository) SubmitQuizAttempt(ctx context.Context, lesson *model.Lesson, mission *model.Mission, attempt *model.QuizAttempt, now time.Time) (bool, error) {
+	answers, err := json.Marshal(attempt.Answers)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Percobaan paralel user yang sama antre di sini supaya batas percobaan tidak terlewati
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, attempt.UserID); err != nil {
+		return false, err
+	}
+
+	stats, err := quizAttemptStats(ctx, tx, attempt.UserID, lesson.ID, model.QuizAttemptsSince(mission, now))
+	if err != nil {
+		return false, err
+	}
+	if stats.Passed {
+		return false, ErrQuizAlreadyPassed
+	}
+	if lesson.AttemptsLeft(stats) == 0 {
+		return false, ErrQuizAttemptLimit
+	}
+	if until, cooling := lesson.CooldownUntil(stats, now); cooling {
+		return false, fmt.Errorf("%w, try again after %s", ErrQuizCooldown, until.Format(time.RFC3339))
+	}
+
+	attempt.LessonID = lesson.ID
+	attempt.MissionID = lesson.MissionID
+	attempt.CreatedAt = now
+	if mission != nil {
+		if period, ok := mission.Period(now); ok {
+			attempt.PeriodStart = model.NewNullTime(period.Start)
+		}
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO quiz_attempts (lesson_id, user_id, mission_id, answers, correct, total, score, passed, period_start, created_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, attempt.LessonID, attempt.UserID, attempt.MissionID, string(answers), attempt.Correct, attempt.Total,
+		attempt.Score, attempt.Passed, attempt.PeriodStart, attempt.CreatedAt).Scan(&attempt.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if !attempt.Passed || mission == nil || !quizMissionOpen(mission, now) {
+		return false, nil
+	}
+	return r.completeWithQuiz(ctx, attempt, mission, now)
+}
+
+// quizMissionOpen misi quiz yang masih bisa menerima progres
+func quizMissionOpen(mission *model.Mission, now time.Time) bool {
+	return mission.MissionType == model.MissionTypeQuiz &&
+		!mission.ArchivedAt.Valid &&
+		(!mission.StartsAt.Valid || !mission.StartsAt.Time.After(now)) &&
+		(!mission.ExpiredAt.Valid || mission.ExpiredAt.Time.After(now))
+}
+
+func (r *checkMissionRepository) completeWithQuiz(ctx context.Context, attempt *model.QuizAttempt, mission *model.Mission, now time.Time) (bool, error) {
+	active, err := r.evaluableMissions(ctx, attempt.UserID, []*model.Mission{mission}, now)
+	if err != nil || len(active) == 0 {
+		return false, err
+	}
+
+	ctx = withProgressEvent(ctx, model.MissionEvent{
+		Type:       model.EventQuizPassed,
+		UserID:     attempt.UserID,
+		SourceType: "quiz_attempts",
+		SourceID:   attempt.ID,
+		OccurredAt: now,
+	})
+
+	progress, err := r.calculateMissionProgress(ctx, attempt.UserID, mission, now)
+	if err != nil {
+		return false, err
+	}
+	if err := r.saveProgress(ctx, attempt.UserID, mission, progress, now); err != nil {
+		return false, err
+	}
+	return r.evaluateCompletion(ctx, attempt.UserID, mission, progress, now)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// Mission Recommendations
+// =========================
+
+// FindRecommendationProfile kendaraan, perangkat dan riwayat penyelesaian user
+func (r *checkMissionRepository) FindRecommendationProfile(ctx context.Context, userID int64) (*model.RecommendationProfile, error) {
+	profile := &model.RecommendationProfile{
+		OwnedCriteria:   map[model.MissionCriteriaType]bool{},
+		CompletedByType: map[model.MissionType]int{},
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT vehicle_type::text FROM carbon_vehicles WHERE user_id = $1
+		UNION SELECT device_type::text FROM carbon_electronics WHERE user_id = $1
+		UNION SELECT 'waste_' || waste_stream FROM carbon_waste_logs WHERE user_id = $1
+		UNION SELECT 'flight' FROM carbon_flight_logs WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var criteria string
+		if err := rows.Scan(&criteria); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		profile.OwnedCriteria[model.MissionCriteriaType(criteria)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		SELECT m.mission_type, COUNT(*)
+		FROM user_missions um
+		JOIN missions m ON m.id = um.mission_id
+		WHERE um.user_id = $1 AND um.completed_at IS NOT NULL
+		GROUP BY m.mission_type
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var missionType model.MissionType
+		var count int
+		if err := rows.Scan(&missionType, &count); err != nil {
+			return nil, err
+		}
+		profile.CompletedByType[missionType] = count
+	}
+	return profile, rows.Err()
+}
+
+// RecentProgress progres yang akan didapat user dari aktivitasnya sejak since, dipakai
+// untuk memperkirakan kecepatan. Tidak mendaftarkan user ke misi.
+func (r *checkMissionRepository) RecentProgress(ctx context.Context, userID int64, mission *model.Mission, since, now time.Time) (float64, error) {
+	return r.progressInWindow(ctx, userID, mission, category.Window{From: &since, To: &now})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/Qodarrz/fiber-app/dto"
 	model "github.com/Qodarrz/fiber-app/model"
@@ -12,6 +13,7 @@ import (
 
 type MissionRepositoryInterface interface {
 	Create(ctx context.Context, mission *model.Mission) error
+	CreateWithBadge(ctx context.Context, mission *model.Mission, badge *model.Badge) error
 	FindByID(ctx context.Context, id int64) (*model.Mission, error)
 	FindAll(ctx context.Context, page, limit int) ([]*model.Mission, error)
 	FindActiveMissions(ctx context.Context) ([]*model.Mission, error)
@@ -20,8 +22,21 @@ type MissionRepositoryInterface interface {
 	UpdateUserMission(ctx context.Context, userMission *model.UserMission) error
 	FindUserMissionByID(ctx context.Context, userID, missionID int64) (*model.UserMission, error)
 	GetAllMissionProgress(ctx context.Context, userID int64) ([]dto.MissionProgressResponse, error)
+	SetPrerequisites(ctx context.Context, missionID int64, mode model.PrerequisiteMode, prerequisiteIDs []int64) error
+	FindAllPrerequisites(ctx context.Context) (map[int64][]int64, error)
+	FindCompletedMissionIDs(ctx context.Context, userID int64) (map[int64]bool, error)
+	FindArchived(ctx context.Context, page, limit int) ([]*model.Mission, error)
+	Update(ctx context.Context, mission *model.Mission) error
+	Archive(ctx context.Context, missionID int64, at time.Time) (bool, error)
+	Restore(ctx context.Context, missionID int64) (bool, error)
+	Delete(ctx context.Context, missionID int64) (bool, error)
+	CountParticipants(ctx context.Context, missionID int64) (*MissionParticipants, error)
+	FindExternalKeys(ctx context.Context) (map[string]int64, error)
+	ImportMissions(ctx context.Context, items []*MissionImportItem) error
 }
 
+var ErrPrerequisiteCycle = errors.New("mission prerequisites must not form a cycle")
+
 type missionRepository struct {
 	db *sql.DB
 }
@@ -32,11 +47,57 @@ func NewMissionRepository(db *sql.DB) MissionRepositoryInterface {
 
 // repository/mission.go (bagian Create)
 func (r *missionRepository) Create(ctx context.Context, mission *model.Mission) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertMission(ctx, tx, mission); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateWithBadge menyimpan badge baru (boleh nil) dan misinya dalam satu transaksi,
+// jadi badge tidak tertinggal kalau misi gagal disimpan
+func (r *missionRepository) CreateWithBadge(ctx context.Context, mission *model.Mission, badge *model.Badge) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if badge != nil {
+		if err := insertBadge(ctx, tx, badge); err != nil {
+			return err
+		}
+		mission.BadgeID = model.NewNullInt64(badge.ID)
+	}
+	if err := insertMission(ctx, tx, mission); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertBadge(ctx context.Context, tx *sql.Tx, badge *model.Badge) error {
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO badges (name, image_url, description, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, badge.Name, badge.ImageURL, badge.Description, badge.CreatedAt).Scan(&badge.ID)
+}
+
+// insertMission menyimpan misi beserta prasyaratnya di transaksi tx
+func insertMission(ctx context.Context, tx *sql.Tx, mission *model.Mission) error {
 	query := `
 		INSERT INTO missions 
 		    (title, description, mission_type, criteria_type, points_reward, 
-		     gives_badge, badge_id, target_value, expired_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		     gives_badge, badge_id, target_value, expired_at, created_at, starts_at, progress_window,
+		     recurrence, recurrence_rule, auto_join, prerequisite_mode, scope, min_contribution,
+		     comparator, target_max, min_activity_logs, rule)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 		RETURNING id
 	`
 
@@ -61,18 +122,153 @@ func (r *missionRepository) Create(ctx context.Context, mission *model.Mission)
 		expiredAt = nil
 	}
 
+	var startsAt interface{}
+	if mission.StartsAt.Valid {
+		startsAt = mission.StartsAt.Time
+	} else {
+		startsAt = nil
+	}
+
+	if mission.ProgressWindow == "" {
+		mission.ProgressWindow = model.ProgressWindowEnrollment
+	}
+
+	if mission.Recurrence == "" {
+		mission.Recurrence = model.RecurrenceNone
+	}
+
+	var recurrenceRule interface{}
+	if mission.RecurrenceRule != "" {
+		recurrenceRule = mission.RecurrenceRule
+	}
+
+	if mission.PrerequisiteMode == "" {
+		mission.PrerequisiteMode = model.PrerequisiteAll
+	}
+
+	if mission.Scope == "" {
+		mission.Scope = model.MissionScopeIndividual
+	}
+
+	if mission.Comparator == "" {
+		mission.Comparator = model.ComparatorAtLeast
+	}
+
 	// langsung QueryRowContext tanpa prepare
-	return r.db.QueryRowContext(ctx, query,
+	err := tx.QueryRowContext(ctx, query,
 		mission.Title, mission.Description, mission.MissionType, criteriaType,
 		mission.PointsReward, mission.GivesBadge, badgeID, mission.TargetValue,
-		expiredAt, mission.CreatedAt,
+		expiredAt, mission.CreatedAt, startsAt, mission.ProgressWindow,
+		mission.Recurrence, recurrenceRule, mission.AutoJoin, mission.PrerequisiteMode,
+		mission.Scope, mission.MinContribution,
+		mission.Comparator, mission.TargetMax, mission.MinActivityLogs, mission.Rule,
 	).Scan(&mission.ID)
+	if err != nil {
+		return err
+	}
+
+	return insertPrerequisites(ctx, tx, mission.ID, mission.Prerequisites)
+}
+
+func insertPrerequisites(ctx context.Context, tx *sql.Tx, missionID int64, prerequisiteIDs []int64) error {
+	for _, prerequisiteID := range prerequisiteIDs {
+		if prerequisiteID == missionID {
+			return ErrPrerequisiteCycle
+		}
+
+		// Tolak kalau misi ini sudah jadi prasyarat (langsung/tidak langsung) dari prerequisiteID
+		var cyclic bool
+		err := tx.QueryRowContext(ctx, `
+			WITH RECURSIVE chain AS (
+				SELECT prerequisite_id FROM mission_prerequisites WHERE mission_id = $1
+				UNION
+				SELECT mp.prerequisite_id
+				FROM mission_prerequisites mp
+				JOIN chain c ON mp.mission_id = c.prerequisite_id
+			)
+			SELECT EXISTS (SELECT 1 FROM chain WHERE prerequisite_id = $2)
+		`, prerequisiteID, missionID).Scan(&cyclic)
+		if err != nil {
+			return err
+		}
+		if cyclic {
+			return ErrPrerequisiteCycle
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO mission_prerequisites (mission_id, prerequisite_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, missionID, prerequisiteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPrerequisites mengganti seluruh prasyarat misi
+func (r *missionRepository) SetPrerequisites(ctx context.Context, missionID int64, mode model.PrerequisiteMode, prerequisiteIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE missions SET prerequisite_mode = $2 WHERE id = $1`, missionID, mode); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mission_prerequisites WHERE mission_id = $1`, missionID); err != nil {
+		return err
+	}
+	if err := insertPrerequisites(ctx, tx, missionID, prerequisiteIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindAllPrerequisites peta mission_id -> daftar prasyarat
+func (r *missionRepository) FindAllPrerequisites(ctx context.Context) (map[int64][]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT mission_id, prerequisite_id FROM mission_prerequisites ORDER BY mission_id, prerequisite_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64][]int64)
+	for rows.Next() {
+		var p model.MissionPrerequisite
+		if err := rows.Scan(&p.MissionID, &p.PrerequisiteID); err != nil {
+			return nil, err
+		}
+		result[p.MissionID] = append(result[p.MissionID], p.PrerequisiteID)
+	}
+	return result, rows.Err()
+}
+
+// FindCompletedMissionIDs misi yang pernah diselesaikan user (misi berulang cukup sekali)
+func (r *missionRepository) FindCompletedMissionIDs(ctx context.Context, userID int64) (map[int64]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT mission_id FROM user_missions WHERE user_id = $1 AND completed_at IS NOT NULL`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result[id] = true
+	}
+	return result, rows.Err()
 }
 
 func (r *missionRepository) FindByID(ctx context.Context, id int64) (*model.Mission, error) {
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, gives_badge,
-		       badge_id, target_value, expired_at, created_at
+		       badge_id, target_value, expired_at, created_at, starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
 		WHERE id = $1
 	`
@@ -86,6 +282,7 @@ func (r *missionRepository) FindByID(ctx context.Context, id int64) (*model.Miss
 		&mission.ID, &mission.Title, &mission.Description, &mission.MissionType,
 		&criteriaType, &mission.PointsReward, &mission.GivesBadge, &badgeID,
 		&mission.TargetValue, &expiredAt, &mission.CreatedAt,
+			&mission.StartsAt, &mission.ProgressWindow, &mission.Recurrence, &mission.RecurrenceRule, &mission.AutoJoin, &mission.PrerequisiteMode, &mission.Scope, &mission.MinContribution, &mission.Comparator, &mission.TargetMax, &mission.MinActivityLogs, &mission.Rule, &mission.ArchivedAt,
 	)
 
 	if err != nil {
@@ -106,12 +303,22 @@ func (r *missionRepository) FindByID(ctx context.Context, id int64) (*model.Miss
 }
 
 func (r *missionRepository) FindAll(ctx context.Context, page, limit int) ([]*model.Mission, error) {
+	return r.findPage(ctx, "archived_at IS NULL", "created_at DESC", page, limit)
+}
+
+// FindArchived misi yang diarsipkan, terbaru dulu
+func (r *missionRepository) FindArchived(ctx context.Context, page, limit int) ([]*model.Mission, error) {
+	return r.findPage(ctx, "archived_at IS NOT NULL", "archived_at DESC", page, limit)
+}
+
+func (r *missionRepository) findPage(ctx context.Context, where, orderBy string, page, limit int) ([]*model.Mission, error) {
 	offset := (page - 1) * limit
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, gives_badge,
-		       badge_id, target_value, expired_at, created_at
+		       badge_id, target_value, expired_at, created_at, starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
-		ORDER BY created_at DESC
+		WHERE ` + where + `
+		ORDER BY ` + orderBy + `
 		LIMIT $1 OFFSET $2
 	`
 
@@ -132,6 +339,7 @@ func (r *missionRepository) FindAll(ctx context.Context, page, limit int) ([]*mo
 			&mission.ID, &mission.Title, &mission.Description, &mission.MissionType,
 			&criteriaType, &mission.PointsReward, &mission.GivesBadge, &badgeID,
 			&mission.TargetValue, &expiredAt, &mission.CreatedAt,
+			&mission.StartsAt, &mission.ProgressWindow, &mission.Recurrence, &mission.RecurrenceRule, &mission.AutoJoin, &mission.PrerequisiteMode, &mission.Scope, &mission.MinContribution, &mission.Comparator, &mission.TargetMax, &mission.MinActivityLogs, &mission.Rule, &mission.ArchivedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -158,9 +366,11 @@ func (r *missionRepository) FindAll(ctx context.Context, page, limit int) ([]*mo
 func (r *missionRepository) FindActiveMissions(ctx context.Context) ([]*model.Mission, error) {
 	query := `
 		SELECT id, title, description, mission_type, criteria_type, points_reward, gives_badge,
-		       badge_id, target_value, expired_at, created_at
+		       badge_id, target_value, expired_at, created_at, starts_at, progress_window, recurrence, COALESCE(recurrence_rule, ''), auto_join, prerequisite_mode, scope, min_contribution, comparator, target_max, min_activity_logs, rule::text, archived_at
 		FROM missions
-		WHERE expired_at IS NULL OR expired_at > NOW()
+		WHERE (expired_at IS NULL OR expired_at > NOW())
+		  AND (starts_at IS NULL OR starts_at <= NOW())
+		  AND archived_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -181,6 +391,7 @@ func (r *missionRepository) FindActiveMissions(ctx context.Context) ([]*model.Mi
 			&mission.ID, &mission.Title, &mission.Description, &mission.MissionType,
 			&criteriaType, &mission.PointsReward, &mission.GivesBadge, &badgeID,
 			&mission.TargetValue, &expiredAt, &mission.CreatedAt,
+			&mission.StartsAt, &mission.ProgressWindow, &mission.Recurrence, &mission.RecurrenceRule, &mission.AutoJoin, &mission.PrerequisiteMode, &mission.Scope, &mission.MinContribution, &mission.Comparator, &mission.TargetMax, &mission.MinActivityLogs, &mission.Rule, &mission.ArchivedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -206,9 +417,11 @@ func (r *missionRepository) FindActiveMissions(ctx context.Context) ([]*model.Mi
 
 func (r *missionRepository) FindUserMissions(ctx context.Context, userID int64) ([]*model.UserMission, error) {
 	query := `
-		SELECT um.id, um.user_id, um.mission_id, um.completed_at, um.created_at,
+		SELECT um.id, um.user_id, um.mission_id, um.status, um.joined_via, um.status_changed_at,
+		       um.completed_at, um.created_at,
 		       m.title, m.description, m.mission_type, m.criteria_type, m.points_reward, 
-		       m.gives_badge, m.badge_id, m.target_value, m.expired_at, m.created_at as mission_created_at
+		       m.gives_badge, m.badge_id, m.target_value, m.expired_at, m.created_at as mission_created_at,
+		       m.starts_at, m.progress_window, m.recurrence, COALESCE(m.recurrence_rule, ''), m.auto_join, m.prerequisite_mode, m.scope, m.min_contribution, m.comparator, m.target_max, m.min_activity_logs, m.rule::text, m.archived_at, um.enrolled_at
 		FROM user_missions um
 		JOIN missions m ON um.mission_id = m.id
 		WHERE um.user_id = $1
@@ -230,10 +443,12 @@ func (r *missionRepository) FindUserMissions(ctx context.Context, userID int64)
 		var expiredAt sql.NullTime
 
 		err := rows.Scan(
-			&userMission.ID, &userMission.UserID, &userMission.MissionID, &completedAt, &userMission.CreatedAt,
+			&userMission.ID, &userMission.UserID, &userMission.MissionID,
+			&userMission.Status, &userMission.JoinedVia, &userMission.StatusChangedAt, &completedAt, &userMission.CreatedAt,
 			&userMission.Mission.Title, &userMission.Mission.Description, &userMission.Mission.MissionType,
 			&criteriaType, &userMission.Mission.PointsReward, &userMission.Mission.GivesBadge, &badgeID,
 			&userMission.Mission.TargetValue, &expiredAt, &userMission.Mission.CreatedAt,
+			&userMission.Mission.StartsAt, &userMission.Mission.ProgressWindow, &userMission.Mission.Recurrence, &userMission.Mission.RecurrenceRule, &userMission.Mission.AutoJoin, &userMission.Mission.PrerequisiteMode, &userMission.Mission.Scope, &userMission.Mission.MinContribution, &userMission.Mission.Comparator, &userMission.Mission.TargetMax, &userMission.Mission.MinActivityLogs, &userMission.Mission.Rule, &userMission.Mission.ArchivedAt, &userMission.EnrolledAt,
 		)
 		if err != nil {
 			return nil, err
@@ -261,11 +476,18 @@ func (r *missionRepository) FindUserMissions(ctx context.Context, userID int64)
 
 func (r *missionRepository) CreateUserMission(ctx context.Context, userMission *model.UserMission) error {
 	query := `
-		INSERT INTO user_missions (user_id, mission_id, completed_at, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
+		INSERT INTO user_missions (user_id, mission_id, completed_at, created_at, enrolled_at, status, joined_via, status_changed_at)
+		VALUES ($1, $2, $3, $4, $4, $5, $6, $4)
+		RETURNING id, enrolled_at, status_changed_at
 	`
 
+	if userMission.Status == "" {
+		userMission.Status = model.UserMissionActive
+	}
+	if userMission.JoinedVia == "" {
+		userMission.JoinedVia = model.JoinedManual
+	}
+
 	var completedAt interface{}
 	if userMission.CompletedAt.Valid {
 		completedAt = userMission.CompletedAt.Time
@@ -275,7 +497,8 @@ func (r *missionRepository) CreateUserMission(ctx context.Context, userMission *
 
 	err := r.db.QueryRowContext(ctx, query,
 		userMission.UserID, userMission.MissionID, completedAt, userMission.CreatedAt,
-	).Scan(&userMission.ID)
+		userMission.Status, userMission.JoinedVia,
+	).Scan(&userMission.ID, &userMission.EnrolledAt, &userMission.StatusChangedAt)
 
 	return err
 }
@@ -300,7 +523,7 @@ func (r *missionRepository) UpdateUserMission(ctx context.Context, userMission *
 
 func (r *missionRepository) FindUserMissionByID(ctx context.Context, userID, missionID int64) (*model.UserMission, error) {
 	query := `
-		SELECT id, user_id, mission_id, completed_at, created_at
+		SELECT id, user_id, mission_id, status, joined_via, status_changed_at, completed_at, created_at, enrolled_at
 		FROM user_missions
 		WHERE user_id = $1 AND mission_id = $2
 	`
@@ -310,7 +533,8 @@ func (r *missionRepository) FindUserMissionByID(ctx context.Context, userID, mis
 
 	err := r.db.QueryRowContext(ctx, query, userID, missionID).Scan(
 		&userMission.ID, &userMission.UserID, &userMission.MissionID,
-		&completedAt, &userMission.CreatedAt,
+		&userMission.Status, &userMission.JoinedVia, &userMission.StatusChangedAt,
+		&completedAt, &userMission.CreatedAt, &userMission.EnrolledAt,
 	)
 
 	if err != nil {
@@ -329,11 +553,18 @@ func (r *missionRepository) GetAllMissionProgress(ctx context.Context, userID in
 		SELECT m.id, m.title, m.description, m.target_value, m.points_reward, m.gives_badge, m.badge_id,
 		       COALESCE(ump.progress_value, 0) AS progress_value,
 		       CASE 
+		          -- Misi lte/between baru selesai setelah dinilai di akhir window
+		          WHEN m.comparator <> 'gte' THEN EXISTS (
+		              SELECT 1 FROM user_missions um
+		              WHERE um.user_id = $1 AND um.mission_id = m.id AND um.status = 'completed'
+		          )
 		          WHEN ump.progress_value >= m.target_value AND m.target_value IS NOT NULL THEN true
 		          ELSE false
 		       END AS is_completed
 		FROM missions m
 		LEFT JOIN user_mission_progress ump ON m.id = ump.mission_id AND ump.user_id = $1
+		     AND (ump.period_end IS NULL OR ump.period_end > NOW())
+		WHERE m.archived_at IS NULL
 	`
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/rule"
+)
+
+// =========================
+// End-of-window Settlement
+// =========================
+
+// SettleClosedWindows menilai misi "di bawah target" (lte/between) yang window-nya sudah
+// ditutup. Misi biasa dinilai sekali saat expired_at lewat: completed atau failed.
+// Misi berulang dinilai per periode; hanya periode terakhir yang ditutup yang dinilai.
+// userID nil berarti semua user.
+func (r *checkMissionRepository) SettleClosedWindows(ctx context.Context, now time.Time, userID *int64) (int64, error) {
+	var uid interface{}
+	if userID != nil {
+		uid = *userID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT m.id
+		FROM missions m
+		JOIN user_missions um ON um.mission_id = m.id AND um.status = 'active'
+		WHERE m.comparator <> 'gte'
+		  AND (m.recurrence <> 'none' OR (m.expired_at IS NOT NULL AND m.expired_at <= $1))
+		  AND ($2::bigint IS NULL OR um.user_id = $2::bigint)
+	`, now, uid)
+	if err != nil {
+		return 0, err
+	}
+	var missionIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		missionIDs = append(missionIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var settled int64
+	for _, id := range missionIDs {
+		mission, err := r.FindByID(ctx, id)
+		if err != nil {
+			return settled, err
+		}
+		if mission == nil {
+			continue
+		}
+
+		n, err := r.settleMission(ctx, mission, now, uid)
+		settled += n
+		if err != nil {
+			return settled, fmt.Errorf("settle mission %d: %w", mission.ID, err)
+		}
+	}
+	return settled, nil
+}
+
+func (r *checkMissionRepository) settleMission(ctx context.Context, mission *model.Mission, now time.Time, uid interface{}) (int64, error) {
+	window, ok := mission.ClosedWindow(now)
+	if !ok {
+		return 0, nil
+	}
+
+	// User yang ikut sebelum window ditutup dan belum dinilai untuk window ini
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT um.user_id, um.enrolled_at
+		FROM user_missions um
+		WHERE um.mission_id = $1 AND um.status = 'active' AND um.enrolled_at < $3
+		  AND ($4::bigint IS NULL OR um.user_id = $4::bigint)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_mission_periods ump
+		      WHERE ump.user_id = um.user_id AND ump.mission_id = um.mission_id
+		        AND ump.period_start = $2 AND ump.settled_at IS NOT NULL
+		  )
+	`, mission.ID, window.Start, window.End, uid)
+	if err != nil {
+		return 0, err
+	}
+
+	type participant struct {
+		userID     int64
+		enrolledAt time.Time
+	}
+	var participants []participant
+	for rows.Next() {
+		var p participant
+		if err := rows.Scan(&p.userID, &p.enrolledAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		participants = append(participants, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var settled int64
+	for _, p := range participants {
+		if err := r.settleParticipant(ctx, p.userID, p.enrolledAt, mission, window, now); err != nil {
+			return settled, err
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+func (r *checkMissionRepository) settleParticipant(ctx context.Context, userID int64, enrolledAt time.Time, mission *model.Mission, window model.MissionPeriod, now time.Time) error {
+	from, to := mission.Window(enrolledAt, window.Start)
+	w := category.Window{From: from, To: to}
+
+	progress, err := r.calculateMissionProgress(ctx, userID, mission, window.Start)
+	if err != nil {
+		return err
+	}
+	logs, err := r.countActivityLogs(ctx, userID, mission, w)
+	if err != nil {
+		return err
+	}
+
+	passed := mission.Meets(progress) && logs >= mission.RequiredActivityLogs()
+	if passed {
+		done, err := r.completeMission(ctx, userID, mission, progress, window.Start)
+		if err != nil {
+			return err
+		}
+		if done {
+			if err := r.Publish(ctx, model.MissionEvent{
+				Type:       model.EventPointsEarned,
+				UserID:     userID,
+				Points:     float64(mission.PointsReward),
+				SourceType: "missions",
+				SourceID:   mission.ID,
+				OccurredAt: time.Now(),
+			}); err != nil {
+				fmt.Printf("Gagal publish points_earned untuk user %d: %v\n", userID, err)
+			}
+		}
+	}
+
+	if mission.IsRecurring() {
+		// Misi tetap aktif untuk periode berikutnya, cukup catat periode ini sudah dinilai
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO user_mission_periods (user_id, mission_id, period_start, period_end, progress_value, settled_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, mission_id, period_start)
+			DO UPDATE SET progress_value = EXCLUDED.progress_value, settled_at = EXCLUDED.settled_at
+		`, userID, mission.ID, window.Start, window.End, progress, now)
+		return err
+	}
+
+	if !passed {
+		_, err = r.db.ExecContext(ctx, `
+			UPDATE user_missions SET status = 'failed', status_changed_at = $3
+			WHERE user_id = $1 AND mission_id = $2 AND status = 'active'
+		`, userID, mission.ID, now)
+	}
+	return err
+}
+
+// countActivityLogs jumlah log yang relevan untuk misi di dalam window
+func (r *checkMissionRepository) countActivityLogs(ctx context.Context, userID int64, mission *model.Mission, w category.Window) (int, error) {
+	var count int
+
+	if mission.Rule.Valid {
+		missionRule, err := rule.Parse([]byte(mission.Rule.String))
+		if err != nil {
+			return 0, err
+		}
+		q, err := missionRule.LogCountQuery(w)
+		if err != nil {
+			return 0, err
+		}
+		err = r.db.QueryRowContext(ctx, q.SQL, append([]any{userID}, q.Args...)...).Scan(&count)
+		return count, err
+	}
+
+	if cat, ok := category.ForCriteria(mission.CriteriaType); ok {
+		q := cat.LogCountQuery(mission.CriteriaType, w)
+		err := r.db.QueryRowContext(ctx, q.SQL, append([]any{userID}, q.Args...)...).Scan(&count)
+		return count, err
+	}
+
+	switch mission.MissionType {
+	case model.MissionTypeActivity:
+		progress, err := r.calculateActivityCountProgress(ctx, userID, mission.CriteriaType, w)
+		return int(progress), err
+	case model.MissionTypeCustom:
+		cond, args := w.Filter("created_at", 2)
+		err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM point_transactions WHERE user_id = $1 AND direction = 'in'`+cond,
+			append([]any{userID}, args...)...).Scan(&count)
+		return count, err
+	}
+
+	// Total emisi semua kategori
+	cond, args := w.Filter("logged_at", 2)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM (`+category.EmissionUnion()+`) AS emissions
+		WHERE user_id = $1`+cond, append([]any{userID}, args...)...).Scan(&count)
+	return count, err
+}
@@ -13,6 +13,7 @@ import (
 type PointsRepositoryInterface interface {
 	GetUserPoints(ctx context.Context, userID int64) (*model.Points, error)
 	AddPoints(ctx context.Context, userID int64, amount int, source string, referenceID int64) error
+	AddPointsOnce(ctx context.Context, userID int64, amount int, source, referenceType string, referenceID int64, idempotencyKey string) (bool, error)
 	DeductPoints(ctx context.Context, userID int64, amount int, source string, referenceID int64) error
 }
 
@@ -102,6 +103,43 @@ func (r *pointsRepository) AddPoints(ctx context.Context, userID int64, amount i
 	return tx.Commit()
 }
 
+// AddPointsOnce seperti AddPoints, tapi ditolak kalau idempotencyKey sudah pernah dibayar,
+// jadi aman diulang setelah gagal di tengah jalan. false kalau sudah dibayar sebelumnya.
+// Baris points dibuat kalau belum ada.
+func (r *pointsRepository) AddPointsOnce(ctx context.Context, userID int64, amount int, source, referenceType string, referenceID int64, idempotencyKey string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var txID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO point_transactions (user_id, amount, direction, source, reference_type, reference_id, created_at, idempotency_key)
+		VALUES ($1, $2, 'in', $3, $4, $5, $6, $7)
+		ON CONFLICT (idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+		RETURNING id
+	`, userID, amount, source, referenceType, referenceID, now, idempotencyKey).Scan(&txID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO points (user_id, total_points, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id)
+		DO UPDATE SET total_points = points.total_points + EXCLUDED.total_points
+	`, userID, amount, now); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
 func (r *pointsRepository) DeductPoints(ctx context.Context, userID int64, amount int, source string, referenceID int64) error {
 	// Start transaction
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+type SchedulerRepositoryInterface interface {
+	TryLeaderLock(ctx context.Context, name string) (release func(), ok bool, err error)
+	LastSucceededAt(ctx context.Context, job string) (sql.NullTime, error)
+	StartJobRun(ctx context.Context, job, instance string, at time.Time) (int64, error)
+	FinishJobRun(ctx context.Context, runID int64, affected int64, runErr error, at time.Time) error
+	FindJobRuns(ctx context.Context, job string, limit int) ([]*model.MissionJobRun, error)
+}
+
+type schedulerRepository struct {
+	db *sql.DB
+}
+
+func NewSchedulerRepository(db *sql.DB) SchedulerRepositoryInterface {
+	return &schedulerRepository{db: db}
+}
+
+// TryLeaderLock mengambil advisory lock Postgres di koneksi khusus. Hanya satu instance
+// yang mendapat lock; release wajib dipanggil kalau ok.
+func (r *schedulerRepository) TryLeaderLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		// Pakai context baru supaya lock tetap dilepas walau ctx scheduler sudah dibatalkan
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+		conn.Close()
+	}
+	return release, true, nil
+}
+
+// LastSucceededAt waktu mulai run sukses terakhir sebuah job
+func (r *schedulerRepository) LastSucceededAt(ctx context.Context, job string) (sql.NullTime, error) {
+	var at sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT MAX(started_at) FROM mission_job_runs WHERE job = $1 AND status = 'succeeded'
+	`, job).Scan(&at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return at, nil
+	}
+	return at, err
+}
+
+func (r *schedulerRepository) StartJobRun(ctx context.Context, job, instance string, at time.Time) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO mission_job_runs (job, instance, status, started_at)
+		VALUES ($1, $2, 'running', $3)
+		RETURNING id
+	`, job, instance, at).Scan(&id)
+	return id, err
+}
+
+func (r *schedulerRepository) FinishJobRun(ctx context.Context, runID int64, affected int64, runErr error, at time.Time) error {
+	status := model.JobRunSucceeded
+	var message sql.NullString
+	if runErr != nil {
+		status = model.JobRunFailed
+		message = model.NewNullString(runErr.Error())
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE mission_job_runs SET status = $2, affected = $3, error = $4, finished_at = $5
+		WHERE id = $1
+	`, runID, status, affected, message, at)
+	return err
+}
+
+// FindJobRuns riwayat run terbaru; job kosong berarti semua job
+func (r *schedulerRepository) FindJobRuns(ctx context.Context, job string, limit int) ([]*model.MissionJobRun, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, job, instance, status, affected, error, started_at, finished_at
+		FROM mission_job_runs
+		WHERE ($1 = '' OR job = $1)
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`, job, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*model.MissionJobRun
+	for rows.Next() {
+		run := &model.MissionJobRun{}
+		if err := rows.Scan(&run.ID, &run.Job, &run.Instance, &run.Status, &run.Affected, &run.Error,
+			&run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+type StreakRepositoryInterface interface {
+	FindByUserID(ctx context.Context, userID int64) (*model.UserStreak, error)
+	SetTimezone(ctx context.Context, userID int64, timezone string) error
+	RecordLogin(ctx context.Context, userID int64, source string, at time.Time) (*model.UserStreak, error)
+}
+
+type streakRepository struct {
+	db *sql.DB
+}
+
+func NewStreakRepository(db *sql.DB) StreakRepositoryInterface {
+	return &streakRepository{db: db}
+}
+
+func (r *streakRepository) FindByUserID(ctx context.Context, userID int64) (*model.UserStreak, error) {
+	streak := &model.UserStreak{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id, timezone, current_streak, longest_streak, last_active_date,
+		       freezes_available, freezes_used, updated_at
+		FROM user_streaks
+		WHERE user_id = $1
+	`, userID).Scan(
+		&streak.UserID, &streak.Timezone, &streak.CurrentStreak, &streak.LongestStreak, &streak.LastActiveDate,
+		&streak.FreezesAvailable, &streak.FreezesUsed, &streak.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return streak, nil
+}
+
+func (r *streakRepository) SetTimezone(ctx context.Context, userID int64, timezone string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_streaks (user_id, timezone, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET timezone = EXCLUDED.timezone, updated_at = NOW()
+	`, userID, timezone)
+	return err
+}
+
+// RecordLogin simpan login event dan perbarui streak dalam satu transaksi.
+// Baris streak dikunci agar login bersamaan tidak menghitung hari yang sama dua kali.
+func (r *streakRepository) RecordLogin(ctx context.Context, userID int64, source string, at time.Time) (*model.UserStreak, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	streak, err := lockStreak(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	day := model.LocalDate(at, streak.Location())
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_login_events (user_id, source, logged_in_at, local_date)
+		VALUES ($1, $2, $3, $4::date)
+	`, userID, source, at, day.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+
+	streak.RegisterActiveDay(day)
+	if err := saveStreak(ctx, tx, streak); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return streak, nil
+}
+
+// lockStreak ambil baris streak user (dibuat kalau belum ada) dan kunci sampai transaksi selesai
+func lockStreak(ctx context.Context, tx *sql.Tx, userID int64) (*model.UserStreak, error) {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_streaks (user_id, timezone, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, model.DefaultStreakTimezone); err != nil {
+		return nil, err
+	}
+
+	streak := &model.UserStreak{}
+	err := tx.QueryRowContext(ctx, `
+		SELECT user_id, timezone, current_streak, longest_streak, last_active_date,
+		       freezes_available, freezes_used, updated_at
+		FROM user_streaks
+		WHERE user_id = $1
+		FOR UPDATE
+	`, userID).Scan(
+		&streak.UserID, &streak.Timezone, &streak.CurrentStreak, &streak.LongestStreak, &streak.LastActiveDate,
+		&streak.FreezesAvailable, &streak.FreezesUsed, &streak.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return streak, nil
+}
+
+func saveStreak(ctx context.Context, tx *sql.Tx, streak *model.UserStreak) error {
+	streak.UpdatedAt = time.Now()
+	_, err := tx.ExecContext(ctx, `
+		UPDATE user_streaks SET
+			current_streak = $2,
+			longest_streak = $3,
+			last_active_date = $4::date,
+			freezes_available = $5,
+			freezes_used = $6,
+			updated_at = $7
+		WHERE user_id = $1
+	`,
+		streak.UserID, streak.CurrentStreak, streak.LongestStreak, streak.LastActiveDate.Time.Format("2006-01-02"),
+		streak.FreezesAvailable, streak.FreezesUsed, streak.UpdatedAt,
+	)
+	return err
+}
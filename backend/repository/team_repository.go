@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+type TeamRepositoryInterface interface {
+	Create(ctx context.Context, team *model.Team) error
+	FindByID(ctx context.Context, id int64) (*model.Team, error)
+	FindByJoinCode(ctx context.Context, code string) (*model.Team, error)
+	FindByUserID(ctx context.Context, userID int64) ([]*model.Team, error)
+	AddMember(ctx context.Context, member *model.TeamMember) (bool, error)
+	RemoveMember(ctx context.Context, teamID, userID int64) (bool, error)
+	CountMembers(ctx context.Context, teamID int64) (int, error)
+}
+
+type teamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) TeamRepositoryInterface {
+	return &teamRepository{db: db}
+}
+
+// Create membuat tim sekaligus menjadikan pembuatnya owner
+func (r *teamRepository) Create(ctx context.Context, team *model.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO teams (name, kind, join_code, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, team.Name, team.Kind, team.JoinCode, team.CreatedBy, team.CreatedAt).Scan(&team.ID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id, role, joined_at)
+		VALUES ($1, $2, 'owner', $3)
+	`, team.ID, team.CreatedBy, team.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *teamRepository) findOne(ctx context.Context, where string, arg interface{}) (*model.Team, error) {
+	team := &model.Team{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, name, kind, join_code, created_by, created_at
+		FROM teams
+		WHERE `+where, arg).Scan(
+		&team.ID, &team.Name, &team.Kind, &team.JoinCode, &team.CreatedBy, &team.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+func (r *teamRepository) FindByID(ctx context.Context, id int64) (*model.Team, error) {
+	return r.findOne(ctx, "id = $1", id)
+}
+
+func (r *teamRepository) FindByJoinCode(ctx context.Context, code string) (*model.Team, error) {
+	return r.findOne(ctx, "join_code = $1", code)
+}
+
+func (r *teamRepository) FindByUserID(ctx context.Context, userID int64) ([]*model.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.name, t.kind, t.join_code, t.created_by, t.created_at
+		FROM teams t
+		JOIN team_members tm ON tm.team_id = t.id
+		WHERE tm.user_id = $1
+		ORDER BY tm.joined_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*model.Team
+	for rows.Next() {
+		team := &model.Team{}
+		if err := rows.Scan(&team.ID, &team.Name, &team.Kind, &team.JoinCode, &team.CreatedBy, &team.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, rows.Err()
+}
+
+// AddMember false kalau user sudah jadi anggota
+func (r *teamRepository) AddMember(ctx context.Context, member *model.TeamMember) (bool, error) {
+	if member.JoinedAt.IsZero() {
+		member.JoinedAt = time.Now()
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_id, user_id) DO NOTHING
+	`, member.TeamID, member.UserID, member.Role, member.JoinedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *teamRepository) CountMembers(ctx context.Context, teamID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM team_members WHERE team_id = $1`, teamID).Scan(&count)
+	return count, err
+}
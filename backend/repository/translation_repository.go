@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+type TranslationRepositoryInterface interface {
+	// FindTranslations terjemahan terbaik per id: locale pertama di locales yang tersedia
+	FindTranslations(ctx context.Context, entity model.TranslationEntity, ids []int64, locales []string) (map[int64]*model.Translation, error)
+	FindAllTranslations(ctx context.Context, entity model.TranslationEntity, id int64) ([]*model.Translation, error)
+	EntityExists(ctx context.Context, entity model.TranslationEntity, id int64) (bool, error)
+	UpsertTranslation(ctx context.Context, entity model.TranslationEntity, translation *model.Translation) error
+	DeleteTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string) (bool, error)
+}
+
+var ErrUnknownTranslationEntity = errors.New("unknown translation entity, use missions, badges or store-items")
+
+// translationTable tabel terjemahan dan tabel induk tiap jenis konten
+type translationTable struct {
+	table  string
+	key    string
+	parent string
+}
+
+var translationTables = map[model.TranslationEntity]translationTable{
+	model.TranslationMission:   {table: "mission_translations", key: "mission_id", parent: "missions"},
+	model.TranslationBadge:     {table: "badge_translations", key: "badge_id", parent: "badges"},
+	model.TranslationStoreItem: {table: "store_item_translations", key: "store_item_id", parent: "store_items"},
+}
+
+type translationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) TranslationRepositoryInterface {
+	return &translationRepository{db: db}
+}
+
+func lookupTranslationTable(entity model.TranslationEntity) (translationTable, error) {
+	t, ok := translationTables[entity]
+	if !ok {
+		return translationTable{}, ErrUnknownTranslationEntity
+	}
+	return t, nil
+}
+
+func (r *translationRepository) FindTranslations(ctx context.Context, entity model.TranslationEntity, ids []int64, locales []string) (map[int64]*model.Translation, error) {
+	result := map[int64]*model.Translation{}
+	if len(ids) == 0 || len(locales) == 0 {
+		return result, nil
+	}
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]any, 0, len(ids)+len(locales))
+	idPlaceholders := make([]string, len(ids))
+	for i, id := range ids {
+		args = append(args, id)
+		idPlaceholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	localePlaceholders := make([]string, len(locales))
+	for i, locale := range locales {
+		args = append(args, locale)
+		localePlaceholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+t.key+`, locale, name, description, updated_at
+		FROM `+t.table+`
+		WHERE `+t.key+` IN (`+strings.Join(idPlaceholders, ", ")+`)
+		  AND locale IN (`+strings.Join(localePlaceholders, ", ")+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rank := make(map[string]int, len(locales))
+	for i, locale := range locales {
+		if _, ok := rank[locale]; !ok {
+			rank[locale] = i
+		}
+	}
+
+	for rows.Next() {
+		tr := &model.Translation{}
+		if err := rows.Scan(&tr.EntityID, &tr.Locale, &tr.Name, &tr.Description, &tr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if current, ok := result[tr.EntityID]; !ok || rank[tr.Locale] < rank[current.Locale] {
+			result[tr.EntityID] = tr
+		}
+	}
+	return result, rows.Err()
+}
+
+func (r *translationRepository) FindAllTranslations(ctx context.Context, entity model.TranslationEntity, id int64) ([]*model.Translation, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+t.key+`, locale, name, description, updated_at
+		FROM `+t.table+`
+		WHERE `+t.key+` = $1
+		ORDER BY locale
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []*model.Translation{}
+	for rows.Next() {
+		tr := &model.Translation{}
+		if err := rows.Scan(&tr.EntityID, &tr.Locale, &tr.Name, &tr.Description, &tr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, tr)
+	}
+	return translations, rows.Err()
+}
+
+func (r *translationRepository) EntityExists(ctx context.Context, entity model.TranslationEntity, id int64) (bool, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM `+t.parent+` WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (r *translationRepository) UpsertTranslation(ctx context.Context, entity model.TranslationEntity, translation *model.Translation) error {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return err
+	}
+
+	translation.UpdatedAt = time.Now()
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO `+t.table+` (`+t.key+`, locale, name, description, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (`+t.key+`, locale)
+		DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+	`, translation.EntityID, translation.Locale, translation.Name, translation.Description, translation.UpdatedAt)
+	return err
+}
+
+func (r *translationRepository) DeleteTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string) (bool, error) {
+	t, err := lookupTranslationTable(entity)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM `+t.table+` WHERE `+t.key+` = $1 AND locale = $2`, id, locale)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// =========================
+// User Mission State Machine
+// =========================
+
+var (
+	ErrMissionAlreadyJoined = errors.New("mission already joined")
+	ErrMissionNotJoined     = errors.New("mission is not active for this user")
+	ErrMissionClosed        = errors.New("mission can no longer be joined")
+	ErrActiveMissionLimit   = errors.New("active mission limit reached")
+	ErrMissionLocked        = errors.New("mission prerequisites are not completed yet")
+)
+
+// prerequisitesMetSQL kondisi SQL bahwa prasyarat misi (kolom missionCol) sudah dipenuhi
+// user (parameter userParam). Harus sama dengan Mission.PrerequisitesMet.
+func prerequisitesMetSQL(missionCol, userParam string) string {
+	completed := `EXISTS (
+		SELECT 1 FROM user_missions pum
+		WHERE pum.user_id = ` + userParam + ` AND pum.mission_id = mp.prerequisite_id AND pum.completed_at IS NOT NULL
+	)`
+	return `(
+		NOT EXISTS (SELECT 1 FROM mission_prerequisites mp WHERE mp.mission_id = ` + missionCol + `)
+		OR (
+			(SELECT prerequisite_mode FROM missions pm WHERE pm.id = ` + missionCol + `) = 'any'
+			AND EXISTS (SELECT 1 FROM mission_prerequisites mp WHERE mp.mission_id = ` + missionCol + ` AND ` + completed + `)
+		)
+		OR (
+			(SELECT prerequisite_mode FROM missions pm WHERE pm.id = ` + missionCol + `) = 'all'
+			AND NOT EXISTS (SELECT 1 FROM mission_prerequisites mp WHERE mp.mission_id = ` + missionCol + ` AND NOT ` + completed + `)
+		)
+	)`
+}
+
+// JoinMission mengaktifkan misi untuk user. maxActive membatasi jumlah misi aktif
+// yang diikuti manual (0 berarti tanpa batas).
+func (r *checkMissionRepository) JoinMission(ctx context.Context, userID, missionID int64, maxActive int, at time.Time) (*model.UserMission, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Kunci user supaya batas misi aktif tidak terlewati oleh join bersamaan
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+
+	current := model.UserMissionAvailable
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM user_missions WHERE user_id = $1 AND mission_id = $2 FOR UPDATE
+	`, userID, missionID).Scan(&status)
+	switch {
+	case err == nil:
+		current = model.UserMissionStatus(status)
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	var unlocked bool
+	if err := tx.QueryRowContext(ctx, `SELECT `+prerequisitesMetSQL("$2::bigint", "$1::bigint"), userID, missionID).Scan(&unlocked); err != nil {
+		return nil, err
+	}
+	if !unlocked {
+		return nil, ErrMissionLocked
+	}
+
+	if !current.CanTransition(model.UserMissionActive) {
+		if current == model.UserMissionActive {
+			return nil, ErrMissionAlreadyJoined
+		}
+		return nil, ErrMissionClosed
+	}
+
+	if maxActive > 0 {
+		var active int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM user_missions
+			WHERE user_id = $1 AND status = 'active' AND joined_via = 'manual'
+		`, userID).Scan(&active); err != nil {
+			return nil, err
+		}
+		if active >= maxActive {
+			return nil, ErrActiveMissionLimit
+		}
+	}
+
+	userMission := &model.UserMission{
+		UserID:          userID,
+		MissionID:       missionID,
+		Status:          model.UserMissionActive,
+		JoinedVia:       model.JoinedManual,
+		StatusChangedAt: at,
+		EnrolledAt:      at,
+	}
+
+	if current == model.UserMissionAvailable {
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO user_missions (user_id, mission_id, created_at, enrolled_at, status, joined_via, status_changed_at)
+			VALUES ($1, $2, $3, $3, 'active', 'manual', $3)
+			RETURNING id, created_at
+		`, userID, missionID, at).Scan(&userMission.ID, &userMission.CreatedAt)
+	} else {
+		// Join ulang: window progres mulai lagi dari sekarang
+		err = tx.QueryRowContext(ctx, `
+			UPDATE user_missions
+			SET status = 'active', joined_via = 'manual', enrolled_at = $3, status_changed_at = $3
+			WHERE user_id = $1 AND mission_id = $2
+			RETURNING id, created_at
+		`, userID, missionID, at).Scan(&userMission.ID, &userMission.CreatedAt)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM user_mission_progress WHERE user_id = $1 AND mission_id = $2`, userID, missionID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return userMission, nil
+}
+
+// LeaveMission menandai misi aktif sebagai abandoned; progres tidak dihitung lagi
+func (r *checkMissionRepository) LeaveMission(ctx context.Context, userID, missionID int64, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_missions
+		SET status = 'abandoned', status_changed_at = $3
+		WHERE user_id = $1 AND mission_id = $2 AND status = 'active'
+	`, userID, missionID, at)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrMissionNotJoined
+	}
+	return nil
+}
+
+// ExpireUserMissions menutup misi aktif yang sudah lewat expired_at: failed kalau
+// user sudah punya progres, expired kalau belum. Misi yang buktinya masih menunggu
+// review dibiarkan aktif sampai direview, dan misi berulang yang pernah diselesaikan
+// di salah satu periodenya tidak dianggap gagal. userID nil berarti semua user.
+func (r *checkMissionRepository) ExpireUserMissions(ctx context.Context, now time.Time, userID *int64) (int64, error) {
+	var uid interface{}
+	if userID != nil {
+		uid = *userID
+	}
+
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_missions um
+		SET status = CASE
+		        WHEN EXISTS (
+		            SELECT 1 FROM user_mission_progress ump
+		            WHERE ump.user_id = um.user_id AND ump.mission_id = um.mission_id AND ump.progress_value > 0
+		        ) THEN 'failed'
+		        ELSE 'expired'
+		    END,
+		    status_changed_at = $1
+		FROM missions m
+		WHERE m.id = um.mission_id
+		  AND um.status = 'active'
+		  AND m.expired_at IS NOT NULL AND m.expired_at <= $1
+		  AND ($2::bigint IS NULL OR um.user_id = $2::bigint)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM mission_proof_submissions ps
+		      WHERE ps.user_id = um.user_id AND ps.mission_id = um.mission_id AND ps.status = 'pending'
+		  )
+		  AND NOT EXISTS (
+		      SELECT 1 FROM user_mission_periods ump
+		      WHERE ump.user_id = um.user_id AND ump.mission_id = um.mission_id AND ump.completed_at IS NOT NULL
+		  )
+	`, now, uid)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// evaluableMissions enroll user ke misi auto-join lalu menyaring misi yang statusnya aktif
+func (r *checkMissionRepository) evaluableMissions(ctx context.Context, userID int64, missions []*model.Mission, at time.Time) ([]*model.Mission, error) {
+	if err := r.enrollActiveMissions(ctx, userID, at.Truncate(time.Second)); err != nil {
+		return nil, err
+	}
+
+	states, err := r.userMissionStates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*model.Mission
+	for _, mission := range missions {
+		if state, ok := states[mission.ID]; ok && state.status == model.UserMissionActive {
+			result = append(result, mission)
+		}
+	}
+	return result, nil
+}
+
+// unlockDependents dipanggil saat misi pertama kali selesai: misi yang prasyaratnya kini
+// terpenuhi langsung diikuti (kalau auto-join) dan user diberi notifikasi.
+func (r *checkMissionRepository) unlockDependents(ctx context.Context, userID int64, completed *model.Mission, at time.Time) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.title, m.auto_join
+		FROM missions m
+		JOIN mission_prerequisites dep ON dep.mission_id = m.id AND dep.prerequisite_id = $2
+		WHERE (m.expired_at IS NULL OR m.expired_at > $3)
+		  AND (m.starts_at IS NULL OR m.starts_at <= $3)
+		  AND m.archived_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM user_missions um WHERE um.user_id = $1 AND um.mission_id = m.id)
+		  AND `+prerequisitesMetSQL("m.id", "$1"), userID, completed.ID, at)
+	if err != nil {
+		return err
+	}
+
+	type dependent struct {
+		id       int64
+		title    string
+		autoJoin bool
+	}
+	var unlocked []dependent
+	for rows.Next() {
+		var d dependent
+		if err := rows.Scan(&d.id, &d.title, &d.autoJoin); err != nil {
+			rows.Close()
+			return err
+		}
+		unlocked = append(unlocked, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	notifRepo := NewNotificationRepo(r.db)
+	for _, d := range unlocked {
+		if d.autoJoin {
+			if _, err := r.EnrollUser(ctx, userID, d.id, at); err != nil {
+				return err
+			}
+		}
+
+		if err := notifRepo.Create(ctx, &model.Notification{
+			UserID:    userID,
+			Title:     "Misi baru terbuka",
+			Message:   fmt.Sprintf("Kamu menyelesaikan \"%s\". Misi \"%s\" sekarang bisa diikuti!", completed.Title, d.title),
+			CreatedAt: at,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
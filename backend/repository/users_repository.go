@@ -30,7 +30,7 @@ func NewUserProfileRepository(db *sql.DB) UserProfileRepositoryInterface {
 func (r *userProfileRepository) FindByUserID(ctx context.Context, userID int64) (*model.UserProfile, error) {
 	profile := &model.UserProfile{}
 	query := `
-		SELECT id, user_id, full_name, avatar_url, birthdate, gender, created_at
+		SELECT id, user_id, full_name, avatar_url, birthdate, gender, language, created_at
 		FROM user_profiles 
 		WHERE user_id = $1
 	`
@@ -42,6 +42,7 @@ func (r *userProfileRepository) FindByUserID(ctx context.Context, userID int64)
 		&profile.AvatarURL,
 		&profile.Birthdate,
 		&profile.Gender,
+		&profile.Language,
 		&profile.CreatedAt,
 	)
 
@@ -81,8 +82,9 @@ func (r *userProfileRepository) Update(ctx context.Context, profile *model.UserP
 		full_name = COALESCE($1, full_name),
 		avatar_url = COALESCE($2, avatar_url),
 		birthdate = COALESCE($3, birthdate),
-		gender = COALESCE($4, gender)
-	WHERE user_id = $5
+		gender = COALESCE($4, gender),
+		language = COALESCE($5, language)
+	WHERE user_id = $6
 	RETURNING id, created_at
 	`
 
@@ -106,6 +108,7 @@ func (r *userProfileRepository) Update(ctx context.Context, profile *model.UserP
 		strOrNil(profile.AvatarURL),
 		timeOrNil(profile.Birthdate),
 		strOrNil(profile.Gender),
+		strOrNil(profile.Language),
 		profile.UserID,
 	).Scan(&profile.ID, &profile.CreatedAt)
 
@@ -120,8 +123,8 @@ func (r *userProfileRepository) Update(ctx context.Context, profile *model.UserP
 
 func (r *userProfileRepository) Create(ctx context.Context, profile *model.UserProfile) error {
 	query := `
-		INSERT INTO user_profiles (user_id, full_name, avatar_url, birthdate, gender, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO user_profiles (user_id, full_name, avatar_url, birthdate, gender, language, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
@@ -131,6 +134,7 @@ func (r *userProfileRepository) Create(ctx context.Context, profile *model.UserP
 		profile.AvatarURL,
 		profile.Birthdate,
 		profile.Gender,
+		profile.Language,
 		time.Now(),
 	).Scan(&profile.ID)
 }
@@ -141,7 +145,7 @@ func (r *userProfileRepository) FindByUID(ctx context.Context, userID int64) (*m
 
 	query := `
         SELECT u.id, u.username, u.email, u.role,
-               p.id, p.user_id, p.full_name, p.avatar_url, p.birthdate, p.gender, p.created_at
+               p.id, p.user_id, p.full_name, p.avatar_url, p.birthdate, p.gender, p.language, p.created_at
         FROM users u
         LEFT JOIN user_profiles p ON u.id = p.user_id
         WHERE u.id = $1
@@ -153,12 +157,13 @@ func (r *userProfileRepository) FindByUID(ctx context.Context, userID int64) (*m
 		avatarURL   sql.NullString
 		birthdate   sql.NullTime
 		gender      sql.NullString
+		language    sql.NullString
 		createdAt   sql.NullTime
 	)
 
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(
 		&user.ID, &user.Username, &user.Email, &user.Role,
-		&profileID, &profileUser, &fullName, &avatarURL, &birthdate, &gender, &createdAt,
+		&profileID, &profileUser, &fullName, &avatarURL, &birthdate, &gender, &language, &createdAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -187,6 +192,9 @@ func (r *userProfileRepository) FindByUID(ctx context.Context, userID int64) (*m
 	if gender.Valid {
 		profile.Gender = &gender.String
 	}
+	if language.Valid {
+		profile.Language = &language.String
+	}
 	if createdAt.Valid {
 		profile.CreatedAt = createdAt.Time
 	}
@@ -16,6 +16,7 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		repository.NewUserRepository(db),
 		repository.NewActivityRepository(db),
 		repository.CheckMissionRepository(db),
+		repository.NewStreakRepository(db),
 	)
 
 	carbonService := service.NewCarbonService(
@@ -23,6 +24,8 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		repository.CheckMissionRepository(db),
 	)
 
+	translationRepo := repository.NewTranslationRepository(db)
+
 	missionRepo := repository.NewMissionRepository(db)
 	userMissionRepo := repository.NewMissionRepository(db)
 
@@ -30,6 +33,9 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		missionRepo,
 		userMissionRepo,
 		repository.NewBadgeRepository(db),
+		repository.CheckMissionRepository(db),
+		translationRepo,
+		repository.NewSchedulerRepository(db),
 	)
 
 	storeRepo := repository.NewStoreRepository(db)
@@ -42,6 +48,8 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		pointsRepo,
 		activityRepo,
 		notificationRepo,
+		repository.CheckMissionRepository(db),
+		translationRepo,
 	)
 
 	userCustomService := service.NewUserCustomEndpointService(
@@ -54,7 +62,7 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		repository.NewUserProfileRepository(db),
 		repository.NewActivityRepository(db),
 		repository.NewUserRepository(db),
-		
+		repository.NewStreakRepository(db),
 	)
 
 	chatbotService, err := service.NewGeminiService()
@@ -71,7 +79,17 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 		controller.InitGeminiController(app, chatbotService, mw)
 	}
 
-	badgeService := service.NewBadgeService(repository.NewBadgeRepository(db))
+	teamService := service.NewTeamService(repository.NewTeamRepository(db))
+
+	badgeService := service.NewBadgeService(repository.NewBadgeRepository(db), translationRepo)
+	translationService := service.NewTranslationService(translationRepo)
+	lessonService := service.NewLessonService(repository.NewLessonRepository(db), repository.CheckMissionRepository(db))
+	checkInService := service.NewCheckInService(
+		repository.NewCheckInRepository(db),
+		repository.NewStreakRepository(db),
+		pointsRepo,
+		repository.CheckMissionRepository(db),
+	)
 	notifCustomService := service.NewNotificationService(repository.NewNotificationRepo(db))
 
 	controller.InitAuthController(app, authService, mw)
@@ -79,7 +97,11 @@ func Setup(app *fiber.App, db *sql.DB, mw *middleware.Middlewares) {
 	controller.InitMissionController(app, userMissionService, mw)
 	controller.InitStoreController(app, storeService, mw)
 	controller.InitBadgeController(app, badgeService, mw)
+	controller.InitTranslationController(app, translationService, mw)
+	controller.InitLessonController(app, lessonService, mw)
+	controller.InitTeamController(app, teamService, mw)
 	controller.InitUserProfileController(app, profileService, mw)
+	controller.InitCheckInController(app, checkInService, mw)
 	controller.InitUserCustomEndpointController(app, userCustomService, notifCustomService, mw)
 
 }
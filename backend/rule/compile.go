@@ -0,0 +1,203 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+// metric sumber data yang bisa dipakai aturan. Nama kolom hanya berasal dari sini,
+// nilai dari aturan selalu dikirim sebagai parameter.
+type metric struct {
+	description string
+	from        string
+	userCol     string
+	timeCol     string
+	value       string // kosong kalau metric hanya bisa dihitung (count/distinct_days)
+	where       string
+	filters     map[string]string
+	events      []models.MissionEventType
+}
+
+var (
+	vehicleFrom     = "carbon_vehicle_logs cvl JOIN carbon_vehicles cv ON cvl.vehicle_id = cv.id"
+	electronicsFrom = "carbon_electronics_logs cel JOIN carbon_electronics ce ON cel.device_id = ce.id"
+
+	vehicleFilters     = map[string]string{"vehicle_type": "cv.vehicle_type", "fuel_type": "cv.fuel_type"}
+	electronicsFilters = map[string]string{"device_type": "ce.device_type"}
+	wasteFilters       = map[string]string{"waste_stream": "cwl.waste_stream", "disposal_route": "cwl.disposal_route"}
+	flightFilters      = map[string]string{"cabin_class": "cfl.cabin_class"}
+
+	carbonEvents = []models.MissionEventType{models.EventCarbonLogged}
+)
+
+var metrics = map[string]metric{
+	"vehicle_distance_km": {
+		description: "Jarak perjalanan kendaraan (km)",
+		from:        vehicleFrom, userCol: "cv.user_id", timeCol: "cvl.logged_at", value: "cvl.distance_km",
+		filters: vehicleFilters, events: carbonEvents,
+	},
+	"vehicle_duration_minutes": {
+		description: "Durasi perjalanan kendaraan (menit)",
+		from:        vehicleFrom, userCol: "cv.user_id", timeCol: "cvl.logged_at", value: "cvl.duration_minutes",
+		filters: vehicleFilters, events: carbonEvents,
+	},
+	"vehicle_emission": {
+		description: "Emisi kendaraan (kg CO2e)",
+		from:        vehicleFrom, userCol: "cv.user_id", timeCol: "cvl.logged_at", value: "cvl.carbon_emission_g",
+		filters: vehicleFilters, events: carbonEvents,
+	},
+	"electronics_hours": {
+		description: "Jam pemakaian perangkat elektronik",
+		from:        electronicsFrom, userCol: "ce.user_id", timeCol: "cel.logged_at", value: "cel.duration_hours",
+		filters: electronicsFilters, events: carbonEvents,
+	},
+	"electronics_emission": {
+		description: "Emisi perangkat elektronik (kg CO2e)",
+		from:        electronicsFrom, userCol: "ce.user_id", timeCol: "cel.logged_at", value: "cel.carbon_emission_g",
+		filters: electronicsFilters, events: carbonEvents,
+	},
+	"waste_kg": {
+		description: "Berat sampah yang dicatat (kg)",
+		from:        "carbon_waste_logs cwl", userCol: "cwl.user_id", timeCol: "cwl.logged_at", value: "cwl.weight_kg",
+		filters: wasteFilters, events: carbonEvents,
+	},
+	"waste_emission": {
+		description: "Emisi sampah (kg CO2e)",
+		from:        "carbon_waste_logs cwl", userCol: "cwl.user_id", timeCol: "cwl.logged_at", value: "cwl.carbon_emission_g",
+		filters: wasteFilters, events: carbonEvents,
+	},
+	"waste_avoided_emission": {
+		description: "Emisi yang dihindari dari daur ulang/kompos (kg CO2e)",
+		from:        "carbon_waste_logs cwl", userCol: "cwl.user_id", timeCol: "cwl.logged_at", value: "cwl.avoided_emission_g",
+		filters: wasteFilters, events: carbonEvents,
+	},
+	"flight_distance_km": {
+		description: "Jarak penerbangan (km)",
+		from:        "carbon_flight_logs cfl", userCol: "cfl.user_id", timeCol: "cfl.logged_at", value: "cfl.distance_km",
+		filters: flightFilters, events: carbonEvents,
+	},
+	"flight_emission": {
+		description: "Emisi penerbangan (kg CO2e)",
+		from:        "carbon_flight_logs cfl", userCol: "cfl.user_id", timeCol: "cfl.logged_at", value: "cfl.carbon_emission_g",
+		filters: flightFilters, events: carbonEvents,
+	},
+	"activity": {
+		description: "Aktivitas user (login, order, ...)",
+		from:        "activity_logs al", userCol: "al.user_id", timeCol: "al.created_at",
+		filters: map[string]string{"activity": "al.activity"},
+		events:  []models.MissionEventType{models.EventUserLoggedIn, models.EventOrderPlaced},
+	},
+	"daily_checkins": {
+		description: "Check-in harian",
+		from:        "daily_checkins dc", userCol: "dc.user_id", timeCol: "dc.created_at",
+		events: []models.MissionEventType{models.EventDailyCheckIn},
+	},
+	"points_earned": {
+		description: "Poin yang didapat",
+		from:        "point_transactions pt", userCol: "pt.user_id", timeCol: "pt.created_at", value: "pt.amount",
+		where:   "pt.direction = 'in'",
+		filters: map[string]string{"source": "pt.source"},
+		events:  []models.MissionEventType{models.EventPointsEarned},
+	},
+}
+
+// emissionMetric emisi total semua kategori; sumbernya dibangun saat dipakai karena
+// kategori mendaftar lewat init
+func emissionMetric() metric {
+	return metric{
+		description: "Emisi total semua kategori (kg CO2e)",
+		from:        "(" + category.EmissionUnion() + ") em", userCol: "em.user_id", timeCol: "em.logged_at", value: "em.carbon_emission_g",
+		filters: map[string]string{}, events: carbonEvents,
+	}
+}
+
+func init() {
+	metrics["emission"] = emissionMetric()
+}
+
+// Compile menerjemahkan aturan ke query berparameter. Placeholder $1 adalah user_id,
+// sama seperti category.Query.
+func (r *Rule) Compile(w category.Window) (category.Query, error) {
+	return r.compile(r.Aggregation, w)
+}
+
+// LogCountQuery jumlah log yang cocok dengan filter aturan di dalam window
+func (r *Rule) LogCountQuery(w category.Window) (category.Query, error) {
+	return r.compile(AggregateCount, w)
+}
+
+func (r *Rule) compile(agg Aggregation, w category.Window) (category.Query, error) {
+	if err := r.Validate(); err != nil {
+		return category.Query{}, err
+	}
+	m := metrics[r.Metric]
+
+	var selectExpr string
+	switch agg {
+	case AggregateSum:
+		selectExpr = "COALESCE(SUM(" + m.value + "), 0)"
+	case AggregateMax:
+		selectExpr = "COALESCE(MAX(" + m.value + "), 0)"
+	case AggregateCount:
+		selectExpr = "COUNT(*)"
+	case AggregateDistinctDay:
+		selectExpr = "COUNT(DISTINCT DATE(" + m.timeCol + "))"
+	}
+
+	var args []any
+	next := 2
+	param := func(v any) string {
+		args = append(args, v)
+		p := fmt.Sprintf("$%d", next)
+		next++
+		return p
+	}
+
+	conds := []string{m.userCol + " = $1"}
+	if m.where != "" {
+		conds = append(conds, m.where)
+	}
+
+	// Urutan filter dibuat tetap supaya query yang sama menghasilkan SQL yang sama
+	keys := make([]string, 0, len(r.Filters.Values))
+	for key := range r.Filters.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		placeholders := make([]string, 0, len(r.Filters.Values[key]))
+		for _, v := range r.Filters.Values[key] {
+			placeholders = append(placeholders, param(v))
+		}
+		conds = append(conds, m.filters[key]+" IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if tod := r.Filters.TimeOfDay; tod != nil {
+		fromClock, _ := parseClock(tod.From)
+		toClock, _ := parseClock(tod.To)
+		from, to := param(formatClock(fromClock)), param(formatClock(toClock))
+		clock := m.timeCol + "::time"
+		if fromClock < toClock {
+			conds = append(conds, fmt.Sprintf("%s >= %s::time AND %s < %s::time", clock, from, clock, to))
+		} else {
+			conds = append(conds, fmt.Sprintf("(%s >= %s::time OR %s < %s::time)", clock, from, clock, to))
+		}
+	}
+
+	windowCond, windowArgs := w.Filter(m.timeCol, next)
+	args = append(args, windowArgs...)
+
+	return category.Query{
+		SQL:  "SELECT " + selectExpr + " FROM " + m.from + " WHERE " + strings.Join(conds, " AND ") + windowCond,
+		Args: args,
+	}, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
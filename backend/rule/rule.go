@@ -0,0 +1,242 @@
+// Package rule berisi format aturan misi deklaratif (JSON) dan compiler-nya ke SQL
+// berparameter, supaya misi baru bisa dibuat admin tanpa perubahan kode.
+package rule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	models "github.com/Qodarrz/fiber-app/model"
+)
+
+type Aggregation string
+
+const (
+	AggregateSum         Aggregation = "sum"
+	AggregateCount       Aggregation = "count"
+	AggregateDistinctDay Aggregation = "distinct_days"
+	AggregateMax         Aggregation = "max"
+)
+
+// Rule aturan progres misi, contoh:
+//
+//	{"metric": "vehicle_distance_km", "filters": {"vehicle_type": ["bicycle"], "time_of_day": {"from": "06:00", "to": "09:00"}},
+//	 "aggregation": "sum", "window": "enrollment", "comparator": "gte", "target": 20}
+type Rule struct {
+	Metric      string                   `json:"metric"`
+	Filters     Filters                  `json:"filters,omitempty"`
+	Aggregation Aggregation              `json:"aggregation,omitempty"`
+	Window      models.ProgressWindow    `json:"window,omitempty"`
+	Comparator  models.MissionComparator `json:"comparator,omitempty"`
+	Target      float64                  `json:"target"`
+	TargetMax   *float64                 `json:"target_max,omitempty"`
+}
+
+// Filters filter kolom (nilai yang diizinkan) dan rentang jam aktivitas
+type Filters struct {
+	Values    map[string][]string
+	TimeOfDay *TimeOfDay
+}
+
+// TimeOfDay rentang jam lokal [From, To). From > To berarti melewati tengah malam.
+type TimeOfDay struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+const timeOfDayKey = "time_of_day"
+
+func (f *Filters) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	f.Values = map[string][]string{}
+	for key, value := range raw {
+		if key == timeOfDayKey {
+			f.TimeOfDay = &TimeOfDay{}
+			if err := json.Unmarshal(value, f.TimeOfDay); err != nil {
+				return fmt.Errorf("filter %s: %w", key, err)
+			}
+			continue
+		}
+
+		// Boleh satu string atau array string
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			f.Values[key] = []string{single}
+			continue
+		}
+		var many []string
+		if err := json.Unmarshal(value, &many); err != nil {
+			return fmt.Errorf("filter %s must be a string or an array of strings", key)
+		}
+		f.Values[key] = many
+	}
+	return nil
+}
+
+func (f Filters) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{}
+	for key, values := range f.Values {
+		out[key] = values
+	}
+	if f.TimeOfDay != nil {
+		out[timeOfDayKey] = f.TimeOfDay
+	}
+	return json.Marshal(out)
+}
+
+// Parse membaca aturan JSON dan mengisi nilai default (aggregation sum, comparator gte)
+func Parse(data []byte) (*Rule, error) {
+	r := &Rule{}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(r); err != nil {
+		return nil, fmt.Errorf("invalid rule: %w", err)
+	}
+
+	if r.Aggregation == "" {
+		r.Aggregation = AggregateSum
+	}
+	if r.Comparator == "" {
+		r.Comparator = models.ComparatorAtLeast
+	}
+	return r, nil
+}
+
+// Validate memastikan aturan bisa dikompilasi; dipanggil saat misi dibuat
+func (r *Rule) Validate() error {
+	m, ok := metrics[r.Metric]
+	if !ok {
+		return fmt.Errorf("unknown metric %q", r.Metric)
+	}
+
+	switch r.Aggregation {
+	case AggregateSum, AggregateMax:
+		if m.value == "" {
+			return fmt.Errorf("metric %q only supports count and distinct_days", r.Metric)
+		}
+	case AggregateCount, AggregateDistinctDay:
+	default:
+		return fmt.Errorf("unknown aggregation %q", r.Aggregation)
+	}
+
+	for key, values := range r.Filters.Values {
+		if _, ok := m.filters[key]; !ok {
+			return fmt.Errorf("metric %q does not support filter %q", r.Metric, key)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("filter %q needs at least one value", key)
+		}
+		for _, v := range values {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("filter %q has an empty value", key)
+			}
+		}
+	}
+
+	if tod := r.Filters.TimeOfDay; tod != nil {
+		from, err := parseClock(tod.From)
+		if err != nil {
+			return err
+		}
+		to, err := parseClock(tod.To)
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return errors.New("time_of_day from and to must differ")
+		}
+	}
+
+	switch r.Window {
+	case "", models.ProgressWindowLifetime, models.ProgressWindowMission, models.ProgressWindowEnrollment:
+	default:
+		return fmt.Errorf("unknown window %q", r.Window)
+	}
+
+	if r.Target < 0 {
+		return errors.New("target must not be negative")
+	}
+	switch r.Comparator {
+	case models.ComparatorAtLeast, models.ComparatorAtMost:
+		if r.TargetMax != nil {
+			return errors.New("target_max only applies to between comparator")
+		}
+	case models.ComparatorBetween:
+		if r.TargetMax == nil || *r.TargetMax < r.Target {
+			return errors.New("between comparator requires target_max >= target")
+		}
+	default:
+		return fmt.Errorf("unknown comparator %q", r.Comparator)
+	}
+	return nil
+}
+
+// SubscribesTo true kalau event bisa mengubah hasil aturan
+func (r *Rule) SubscribesTo(event models.MissionEventType) bool {
+	m, ok := metrics[r.Metric]
+	if !ok {
+		return false
+	}
+	for _, e := range m.events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("time_of_day must use HH:MM, got %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// MetricInfo deskripsi metric untuk admin yang menyusun aturan
+type MetricInfo struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	Filters      []string      `json:"filters"`
+	Aggregations []Aggregation `json:"aggregations"`
+}
+
+// Catalog daftar metric beserta filter dan agregasi yang didukung
+func Catalog() []MetricInfo {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	catalog := make([]MetricInfo, 0, len(names))
+	for _, name := range names {
+		m := metrics[name]
+		filters := []string{timeOfDayKey}
+		for key := range m.filters {
+			filters = append(filters, key)
+		}
+		sort.Strings(filters)
+
+		aggregations := []Aggregation{AggregateCount, AggregateDistinctDay}
+		if m.value != "" {
+			aggregations = append([]Aggregation{AggregateSum, AggregateMax}, aggregations...)
+		}
+
+		catalog = append(catalog, MetricInfo{
+			Name:         name,
+			Description:  m.description,
+			Filters:      filters,
+			Aggregations: aggregations,
+		})
+	}
+	return catalog
+}
@@ -33,17 +33,20 @@ type AuthService struct {
 	userRepo     repository.UserRepositoryInterface
 	activityRepo repository.ActivityRepositoryInterface
 	missionRepo  repository.CheckMissionRepositoryInterface
+	streakRepo   repository.StreakRepositoryInterface
 }
 
 func NewAuthService(
 	userRepo repository.UserRepositoryInterface,
 	activityRepo repository.ActivityRepositoryInterface,
 	missionRepo repository.CheckMissionRepositoryInterface,
+	streakRepo repository.StreakRepositoryInterface,
 ) *AuthService {
 	return &AuthService{
 		userRepo:     userRepo,
 		activityRepo: activityRepo,
 		missionRepo:  missionRepo,
+		streakRepo:   streakRepo,
 	}
 }
 
@@ -123,10 +126,20 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginDTO) (*models.Use
 		fmt.Printf("gagal simpan activity log: %v\n", err)
 	}
 
+	loggedInAt := time.Now()
 	go func() {
 		bgCtx := context.Background()
 
-		if err := s.missionRepo.CheckAllUserMissions(bgCtx, user.ID); err != nil {
+		// Streak diperbarui dulu supaya misi login membaca status terbaru
+		if _, err := s.streakRepo.RecordLogin(bgCtx, user.ID, "password", loggedInAt); err != nil {
+			fmt.Printf("gagal update login streak: %v\n", err)
+		}
+
+		if err := s.missionRepo.Publish(bgCtx, models.MissionEvent{
+			Type:       models.EventUserLoggedIn,
+			UserID:     user.ID,
+			OccurredAt: loggedInAt,
+		}); err != nil {
 			fmt.Printf("Gagal check missions setelah login: %v\n", err)
 		} else {
 			fmt.Printf("Success check missions untuk user %d setelah login\n", user.ID)
@@ -258,9 +271,20 @@ func (s *AuthService) LoginWithGoogle(ctx context.Context, googleUser *dto.Googl
 	}
 
 	// Check missions
+	loggedInAt := time.Now()
 	go func() {
 		bgCtx := context.Background()
-		if err := s.missionRepo.CheckAllUserMissions(bgCtx, user.ID); err != nil {
+
+		// Streak diperbarui dulu supaya misi login membaca status terbaru
+		if _, err := s.streakRepo.RecordLogin(bgCtx, user.ID, "google", loggedInAt); err != nil {
+			fmt.Printf("gagal update login streak: %v\n", err)
+		}
+
+		if err := s.missionRepo.Publish(bgCtx, models.MissionEvent{
+			Type:       models.EventUserLoggedIn,
+			UserID:     user.ID,
+			OccurredAt: loggedInAt,
+		}); err != nil {
 			fmt.Printf("Gagal check missions setelah login OAuth: %v\n", err)
 		} else {
 			fmt.Printf("Success check missions untuk user %d setelah login OAuth\n", user.ID)
@@ -13,13 +13,30 @@ type BadgeService interface {
 }
 
 type badgeService struct {
-	badgeRepo repository.BadgeRepositoryInterface
+	badgeRepo       repository.BadgeRepositoryInterface
+	translationRepo repository.TranslationRepositoryInterface
 }
 
-func NewBadgeService(badgeRepo repository.BadgeRepositoryInterface) BadgeService {
-	return &badgeService{badgeRepo: badgeRepo}
+func NewBadgeService(badgeRepo repository.BadgeRepositoryInterface, translationRepo repository.TranslationRepositoryInterface) BadgeService {
+	return &badgeService{badgeRepo: badgeRepo, translationRepo: translationRepo}
 }
 
 func (s *badgeService) GetBadgesWithOwnership(ctx context.Context, userID int64, page, limit int) ([]*model.BadgeWithOwnership, error) {
-	return s.badgeRepo.FindAllWithOwnership(ctx, userID, page, limit)
+	badges, err := s.badgeRepo.FindAllWithOwnership(ctx, userID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(badges))
+	for i, badge := range badges {
+		ids[i] = badge.ID
+	}
+	err = localize(ctx, s.translationRepo, model.TranslationBadge, ids, func(i int, tr *model.Translation) {
+		badges[i].Name = tr.Name
+		badges[i].Description = localizedText(badges[i].Description, tr.Description)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return badges, nil
 }
\ No newline at end of file
@@ -4,9 +4,13 @@ package service
 import (
 	"context"
 	"errors"
+	"math"
+	"strconv"
 	"time"
 
+	"github.com/Qodarrz/fiber-app/category"
 	"github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
 	models "github.com/Qodarrz/fiber-app/model"
 	"github.com/Qodarrz/fiber-app/repository"
 )
@@ -31,6 +35,19 @@ type CarbonServiceInterface interface {
 	DeleteElectronic(ctx context.Context, userID, deviceID int64) error
 	GetAllElectronicLogs(ctx context.Context, userID int64) ([]*models.CarbonElectronicLog, error)
 
+	AddWasteLog(ctx context.Context, userID int64, req *dto.AddWasteLogDTO) (*models.CarbonWasteLog, error)
+	GetAllWasteLogs(ctx context.Context, userID int64) ([]*models.CarbonWasteLog, error)
+	GetWasteSummary(ctx context.Context, userID int64) ([]*models.WasteRouteSummary, error)
+
+	AddMeterReading(ctx context.Context, userID int64, req *dto.AddMeterReadingDTO) (*models.CarbonMeterReading, error)
+	GetElectricitySummary(ctx context.Context, userID int64) (*dto.ElectricitySummaryDTO, error)
+
+	AddFlightLog(ctx context.Context, userID int64, req *dto.AddFlightLogDTO) (*models.CarbonFlightLog, error)
+	GetAllFlightLogs(ctx context.Context, userID int64) ([]*models.CarbonFlightLog, error)
+
+	SubmitQuestionnaire(ctx context.Context, userID int64, req *dto.OnboardingQuestionnaireDTO) (*dto.BaselineComparisonDTO, error)
+	GetBaseline(ctx context.Context, userID int64) (*dto.BaselineComparisonDTO, error)
+
 	// Electronics methods would be similarly updated
 }
 
@@ -107,24 +124,17 @@ func (s *CarbonService) AddVehicleLog(ctx context.Context, userID int64, req *dt
 		}
 	}
 
-	var factor float64
-	switch vehicle.FuelType {
-	case models.FuelPetrol:
-		factor = 0.161
-	case models.FuelDiesel:
-		factor = 0.162
-	case models.FuelElectric:
-		factor = 0.095
-	case models.FuelNone:
-		factor = 0
-	default:
-		factor = 0.16
+	est, err := category.Calculate(category.Vehicle, category.Activity{
+		Type:     string(vehicle.FuelType),
+		Quantity: req.DistanceKm,
+	})
+	if err != nil {
+		return err
 	}
 
-	carbon := req.DistanceKm * factor
-
 	// Simpan log
-	err = s.carbonRepo.CreateVehicleLog(ctx, &models.CarbonVehicleLog{
+	now := time.Now()
+	vehicleLog := &models.CarbonVehicleLog{
 		VehicleID:       vehicle.ID,
 		StartLat:        req.StartLat,
 		StartLon:        req.StartLon,
@@ -132,14 +142,26 @@ func (s *CarbonService) AddVehicleLog(ctx context.Context, userID int64, req *dt
 		EndLon:          req.EndLon,
 		DistanceKm:      req.DistanceKm,
 		DurationMinutes: req.DurationMinutes,
-		CarbonEmission:  carbon,
-	})
+		CarbonEmission:  est.Emission,
+		LoggedAt:        now,
+	}
 
-	if err != nil {
+	if err := s.carbonRepo.CreateVehicleLog(ctx, vehicleLog); err != nil {
 		return err
 	}
 
-	return s.missionRepo.CheckAllUserMissions(ctx, userID)
+	return s.missionRepo.Publish(ctx, models.MissionEvent{
+		Type:       models.EventCarbonLogged,
+		UserID:     userID,
+		Category:   category.Vehicle,
+		Criteria:   models.MissionCriteriaType(vehicle.VehicleType),
+		Emission:   est.Emission,
+		Quantity:   req.DistanceKm,
+		SourceType: "carbon_vehicle_logs",
+		SourceID:   vehicleLog.ID,
+		OccurredAt: now,
+		ActivityAt: now,
+	})
 }
 
 func (s *CarbonService) GetVehicleLogs(ctx context.Context, userID, vehicleID int64) ([]*models.CarbonVehicleLog, error) {
@@ -217,19 +239,37 @@ func (s *CarbonService) AddElectronicsLog(ctx context.Context, userID int64, req
 		}
 	}
 
-	carbon := float64(device.PowerWatts) / 1000.0 * req.DurationHours * 0.475
+	est, err := category.Calculate(category.Electronics, category.Activity{
+		Type:     device.DeviceType,
+		Quantity: float64(device.PowerWatts) / 1000.0 * req.DurationHours,
+	})
+	if err != nil {
+		return err
+	}
 
-	err = s.carbonRepo.CreateElectronicsLog(ctx, &models.CarbonElectronicLog{
+	now := time.Now()
+	electronicLog := &models.CarbonElectronicLog{
 		DeviceID:       device.ID,
 		DurationHours:  req.DurationHours,
-		CarbonEmission: carbon,
-		LoggedAt:       time.Now(),
-	})
-	if err != nil {
+		CarbonEmission: est.Emission,
+		LoggedAt:       now,
+	}
+	if err := s.carbonRepo.CreateElectronicsLog(ctx, electronicLog); err != nil {
 		return err
 	}
 
-	return s.missionRepo.CheckAllUserMissions(ctx, userID)
+	return s.missionRepo.Publish(ctx, models.MissionEvent{
+		Type:       models.EventCarbonLogged,
+		UserID:     userID,
+		Category:   category.Electronics,
+		Criteria:   models.MissionCriteriaType(device.DeviceType),
+		Emission:   est.Emission,
+		Quantity:   req.DurationHours,
+		SourceType: "carbon_electronics_logs",
+		SourceID:   electronicLog.ID,
+		OccurredAt: now,
+		ActivityAt: now,
+	})
 }
 
 func (s *CarbonService) GetElectronicsLogs(ctx context.Context, userID, deviceID int64) ([]*models.CarbonElectronicLog, error) {
@@ -379,3 +419,474 @@ func (s *CarbonService) DeleteElectronic(ctx context.Context, userID, deviceID i
 func (s *CarbonService) GetAllElectronicLogs(ctx context.Context, userID int64) ([]*models.CarbonElectronicLog, error) {
 	return s.carbonRepo.GetAllElectronicLogsByUser(ctx, userID)
 }
+
+// ======================== WASTE ========================
+
+// Log yang waktunya diisi user hanya boleh dicatat mundur sampai batas ini, supaya
+// tidak bisa dipakai mengisi window misi yang sudah lewat
+const maxLogBackdate = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidLoggedAt = errors.New("logged_at must not be in the future or more than 7 days ago")
+	ErrInvalidReadAt   = errors.New("read_at must not be in the future or more than 7 days ago")
+)
+
+// resolveLoggedAt waktu aktivitas dari request, default now
+func resolveLoggedAt(now time.Time, loggedAt *time.Time) (time.Time, error) {
+	if loggedAt == nil {
+		return now, nil
+	}
+	if loggedAt.After(now) || now.Sub(*loggedAt) > maxLogBackdate {
+		return time.Time{}, ErrInvalidLoggedAt
+	}
+	return *loggedAt, nil
+}
+
+func (s *CarbonService) AddWasteLog(ctx context.Context, userID int64, req *dto.AddWasteLogDTO) (*models.CarbonWasteLog, error) {
+	est, err := category.Calculate(category.Waste, category.Activity{
+		Type:     req.WasteStream,
+		Quantity: req.WeightKg,
+		Attrs:    map[string]string{"disposal_route": req.DisposalRoute},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loggedAt, err := resolveLoggedAt(now, req.LoggedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &models.CarbonWasteLog{
+		UserID:          userID,
+		WasteStream:     models.WasteStream(req.WasteStream),
+		DisposalRoute:   models.DisposalRoute(req.DisposalRoute),
+		WeightKg:        req.WeightKg,
+		CarbonEmission:  est.Emission,
+		AvoidedEmission: est.Avoided,
+		LoggedAt:        loggedAt,
+	}
+
+	if err := s.carbonRepo.CreateWasteLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	if err := s.missionRepo.Publish(ctx, models.MissionEvent{
+		Type:       models.EventCarbonLogged,
+		UserID:     userID,
+		Category:   category.Waste,
+		Criteria:   log.WasteStream.Criteria(),
+		Emission:   log.CarbonEmission,
+		Quantity:   log.WeightKg,
+		SourceType: "carbon_waste_logs",
+		SourceID:   log.ID,
+		OccurredAt: now,
+		ActivityAt: log.LoggedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+func (s *CarbonService) GetAllWasteLogs(ctx context.Context, userID int64) ([]*models.CarbonWasteLog, error) {
+	return s.carbonRepo.GetAllWasteLogsByUser(ctx, userID)
+}
+
+func (s *CarbonService) GetWasteSummary(ctx context.Context, userID int64) ([]*models.WasteRouteSummary, error) {
+	return s.carbonRepo.GetWasteSummaryByUser(ctx, userID)
+}
+
+// ======================== ELECTRICITY ========================
+
+func (s *CarbonService) AddMeterReading(ctx context.Context, userID int64, req *dto.AddMeterReadingDTO) (*models.CarbonMeterReading, error) {
+	readingType := models.MeterReadingType(req.ReadingType)
+	if readingType != models.MeterReadingPostpaid && readingType != models.MeterReadingToken {
+		return nil, errors.New("invalid reading type")
+	}
+	if readingType == models.MeterReadingToken && req.TokenKwh <= 0 {
+		return nil, errors.New("token_kwh must be greater than 0 for token purchases")
+	}
+
+	readAt, err := resolveLoggedAt(time.Now(), req.ReadAt)
+	if err != nil {
+		return nil, ErrInvalidReadAt
+	}
+
+	reading := &models.CarbonMeterReading{
+		UserID:      userID,
+		ReadingType: readingType,
+		ReadingKwh:  req.ReadingKwh,
+		TokenKwh:    req.TokenKwh,
+		TokenPrice:  req.TokenPrice,
+		ReadAt:      readAt,
+	}
+
+	err = s.carbonRepo.CreateMeterReading(ctx, reading, func(prev *models.CarbonMeterReading) error {
+		// Pembacaan pertama hanya jadi titik awal, konsumsi dihitung mulai pembacaan berikutnya
+		if prev == nil {
+			return nil
+		}
+		if !readAt.After(prev.ReadAt) {
+			return errors.New("read_at must be after the latest reading")
+		}
+		consumption, err := meterConsumption(prev, reading)
+		if err != nil {
+			return err
+		}
+		reading.ConsumptionKwh = consumption
+		reading.CarbonEmission = consumption * category.GridEmissionFactor
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reading, nil
+}
+
+// meterConsumption menghitung kWh yang terpakai di antara dua pembacaan dengan tipe yang sama
+func meterConsumption(prev, cur *models.CarbonMeterReading) (float64, error) {
+	var consumption float64
+	switch cur.ReadingType {
+	case models.MeterReadingPostpaid:
+		consumption = cur.ReadingKwh - prev.ReadingKwh
+	case models.MeterReadingToken:
+		consumption = prev.ReadingKwh + prev.TokenKwh - cur.ReadingKwh
+	}
+	if consumption < 0 {
+		return 0, errors.New("meter reading is lower than the previous reading")
+	}
+	return consumption, nil
+}
+
+func (s *CarbonService) GetElectricitySummary(ctx context.Context, userID int64) (*dto.ElectricitySummaryDTO, error) {
+	readings, err := s.carbonRepo.ListMeterReadings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.ElectricitySummaryDTO{Periods: []dto.ElectricityPeriodDTO{}}
+	lastByType := make(map[models.MeterReadingType]*models.CarbonMeterReading)
+
+	var periods []electricityPeriod
+	for _, reading := range readings {
+		prev := lastByType[reading.ReadingType]
+		lastByType[reading.ReadingType] = reading
+		if prev != nil {
+			periods = append(periods, electricityPeriod{start: prev, end: reading})
+		}
+	}
+
+	for _, period := range periods {
+		// Satu sumber per periode: meteran pascabayar lebih akurat daripada pembelian token
+		if period.end.ReadingType == models.MeterReadingToken && period.overlapsMeter(periods) {
+			continue
+		}
+		prev, reading := period.start, period.end
+
+		attributed, err := s.carbonRepo.SumElectronicsKwhBetween(ctx, userID, prev.ReadAt, reading.ReadAt)
+		if err != nil {
+			return nil, err
+		}
+
+		// Log peralatan bisa melebihi konsumsi meteran (estimasi daya terlalu tinggi)
+		unattributed := math.Max(reading.ConsumptionKwh-attributed, 0)
+		summary.Periods = append(summary.Periods, dto.ElectricityPeriodDTO{
+			ReadingID:          reading.ID,
+			ReadingType:        string(reading.ReadingType),
+			StartAt:            prev.ReadAt,
+			EndAt:              reading.ReadAt,
+			ConsumptionKwh:     reading.ConsumptionKwh,
+			CarbonEmission:     reading.CarbonEmission,
+			AttributedKwh:      attributed,
+			UnattributedKwh:    unattributed,
+			UnattributedCarbon: unattributed * category.GridEmissionFactor,
+		})
+
+		summary.TotalConsumptionKwh += reading.ConsumptionKwh
+		summary.TotalCarbon += reading.CarbonEmission
+		summary.TotalAttributedKwh += attributed
+		summary.TotalUnattributed += unattributed
+	}
+
+	return summary, nil
+}
+
+// electricityPeriod konsumsi di antara dua pembacaan bertipe sama
+type electricityPeriod struct {
+	start, end *models.CarbonMeterReading
+}
+
+func (p electricityPeriod) overlapsMeter(periods []electricityPeriod) bool {
+	for _, other := range periods {
+		if other.end.ReadingType == models.MeterReadingPostpaid &&
+			other.start.ReadAt.Before(p.end.ReadAt) && p.start.ReadAt.Before(other.end.ReadAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// ======================== FLIGHT ========================
+
+func (s *CarbonService) AddFlightLog(ctx context.Context, userID int64, req *dto.AddFlightLogDTO) (*models.CarbonFlightLog, error) {
+	origin, err := helpers.FindAirport(req.Origin)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := helpers.FindAirport(req.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if origin.IATA == destination.IATA {
+		return nil, errors.New("origin and destination must be different")
+	}
+
+	cabin := models.CabinEconomy
+	if req.CabinClass != "" {
+		cabin = models.CabinClass(req.CabinClass)
+	}
+
+	est, err := category.Calculate(category.Flight, category.Activity{
+		Type:     string(cabin),
+		Quantity: helpers.GreatCircleDistanceKm(origin.Lat, origin.Lon, destination.Lat, destination.Lon),
+		Attrs:    map[string]string{"round_trip": strconv.FormatBool(req.RoundTrip)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loggedAt, err := resolveLoggedAt(now, req.LoggedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &models.CarbonFlightLog{
+		UserID:         userID,
+		Origin:         origin.IATA,
+		Destination:    destination.IATA,
+		CabinClass:     cabin,
+		RoundTrip:      req.RoundTrip,
+		DistanceKm:     est.Quantity,
+		CarbonEmission: est.Emission,
+		LoggedAt:       loggedAt,
+	}
+
+	if err := s.carbonRepo.CreateFlightLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	if err := s.missionRepo.Publish(ctx, models.MissionEvent{
+		Type:       models.EventCarbonLogged,
+		UserID:     userID,
+		Category:   category.Flight,
+		Criteria:   models.CriteriaFlight,
+		Emission:   log.CarbonEmission,
+		Quantity:   log.DistanceKm,
+		SourceType: "carbon_flight_logs",
+		SourceID:   log.ID,
+		OccurredAt: now,
+		ActivityAt: log.LoggedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+func (s *CarbonService) GetAllFlightLogs(ctx context.Context, userID int64) ([]*models.CarbonFlightLog, error) {
+	return s.carbonRepo.GetAllFlightLogsByUser(ctx, userID)
+}
+
+// ======================== ONBOARDING ========================
+
+// Emisi pola makan tahunan per orang (kg CO2e)
+var dietAnnualEmissions = map[models.DietType]float64{
+	models.DietVegan:       1055,
+	models.DietVegetarian:  1390,
+	models.DietPescatarian: 1427,
+	models.DietLowMeat:     1705,
+	models.DietMediumMeat:  2055,
+	models.DietHighMeat:    2624,
+}
+
+// Daya tipikal (watt) kalau user tidak mengisi daya peralatan
+var defaultApplianceWatts = map[string]int{
+	"laptop":          60,
+	"desktop":         200,
+	"tv":              100,
+	"ac":              900,
+	"fridge":          150,
+	"fan":             50,
+	"washing_machine": 500,
+	"other":           100,
+}
+
+// Bahan bakar default per moda kalau user tidak mengisi
+var defaultCommuteFuel = map[models.VehicleType]models.FuelType{
+	models.VehicleCar:         models.FuelPetrol,
+	models.VehicleMotorcycle:  models.FuelPetrol,
+	models.VehiclePublicTrans: models.FuelDiesel,
+	models.VehicleBicycle:     models.FuelNone,
+	models.VehicleWalk:        models.FuelNone,
+}
+
+const onboardingNameSuffix = " (onboarding)"
+
+func (s *CarbonService) SubmitQuestionnaire(ctx context.Context, userID int64, req *dto.OnboardingQuestionnaireDTO) (*dto.BaselineComparisonDTO, error) {
+	dietEmission, ok := dietAnnualEmissions[models.DietType(req.Diet)]
+	if !ok {
+		return nil, errors.New("invalid diet")
+	}
+	if req.HouseholdSize < 1 {
+		return nil, errors.New("household_size must be at least 1")
+	}
+
+	mode := models.VehicleType(req.CommuteMode)
+	fuel := models.FuelType(req.FuelType)
+	if fuel == "" {
+		fuel = defaultCommuteFuel[mode]
+	}
+
+	// Transportasi: perjalanan harian x hari per minggu x 52 minggu
+	transport, err := category.Calculate(category.Vehicle, category.Activity{
+		Type:     string(fuel),
+		Quantity: req.CommuteKmPerDay * float64(req.CommuteDaysPerWeek) * 52,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CommuteKmPerDay > 0 && req.CommuteDaysPerWeek > 0 {
+		if err := s.ensureOnboardingVehicle(ctx, userID, mode, fuel); err != nil {
+			return nil, err
+		}
+	}
+
+	// Listrik: pemakaian setahun dibagi rata ke anggota rumah
+	appliances := make([]models.ApplianceUsage, 0, len(req.Appliances))
+	var annualKwh float64
+	for _, a := range req.Appliances {
+		watts := a.PowerWatts
+		if watts <= 0 {
+			watts = defaultApplianceWatts[a.DeviceType]
+		}
+		if err := s.ensureOnboardingElectronic(ctx, userID, a.DeviceType, watts); err != nil {
+			return nil, err
+		}
+
+		annualKwh += float64(watts) / 1000.0 * a.HoursPerDay * 365
+		appliances = append(appliances, models.ApplianceUsage{
+			DeviceType:  a.DeviceType,
+			PowerWatts:  watts,
+			HoursPerDay: a.HoursPerDay,
+		})
+	}
+
+	electricity, err := category.Calculate(category.Electronics, category.Activity{
+		Quantity: annualKwh / float64(req.HouseholdSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &models.CarbonBaseline{
+		UserID:              userID,
+		CommuteMode:         mode,
+		FuelType:            fuel,
+		CommuteKmPerDay:     req.CommuteKmPerDay,
+		CommuteDaysPerWeek:  req.CommuteDaysPerWeek,
+		HouseholdSize:       req.HouseholdSize,
+		Appliances:          appliances,
+		Diet:                models.DietType(req.Diet),
+		TransportEmission:   transport.Emission,
+		ElectricityEmission: electricity.Emission,
+		DietEmission:        dietEmission,
+		TotalEmission:       transport.Emission + electricity.Emission + dietEmission,
+	}
+
+	if err := s.carbonRepo.CreateBaseline(ctx, baseline); err != nil {
+		return nil, err
+	}
+
+	if err := s.missionRepo.Publish(ctx, models.MissionEvent{
+		Type:       models.EventBaselineUpdated,
+		UserID:     userID,
+		SourceType: "carbon_baselines",
+		SourceID:   baseline.ID,
+		OccurredAt: baseline.CreatedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetBaseline(ctx, userID)
+}
+
+func (s *CarbonService) ensureOnboardingVehicle(ctx context.Context, userID int64, mode models.VehicleType, fuel models.FuelType) error {
+	name := string(mode) + onboardingNameSuffix
+	existing, err := s.carbonRepo.FindVehicleByUserAndName(ctx, userID, name)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	_, err = s.carbonRepo.CreateVehicle(ctx, &models.CarbonVehicle{
+		UserID:      userID,
+		VehicleType: mode,
+		FuelType:    fuel,
+		Name:        name,
+	})
+	return err
+}
+
+func (s *CarbonService) ensureOnboardingElectronic(ctx context.Context, userID int64, deviceType string, watts int) error {
+	name := deviceType + onboardingNameSuffix
+	existing, err := s.carbonRepo.FindElectronicsByUserAndName(ctx, userID, name)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	_, err = s.carbonRepo.CreateElectronics(ctx, &models.CarbonElectronic{
+		UserID:     userID,
+		DeviceName: name,
+		DeviceType: deviceType,
+		PowerWatts: watts,
+	})
+	return err
+}
+
+// GetBaseline mengembalikan baseline terbaru beserta perubahan dari pengisian sebelumnya
+func (s *CarbonService) GetBaseline(ctx context.Context, userID int64) (*dto.BaselineComparisonDTO, error) {
+	baselines, err := s.carbonRepo.ListBaselinesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(baselines) == 0 {
+		return nil, nil
+	}
+
+	result := &dto.BaselineComparisonDTO{History: make([]dto.BaselineDTO, 0, len(baselines))}
+	for _, b := range baselines {
+		result.History = append(result.History, dto.BaselineDTO{
+			ID:                  b.ID,
+			TransportEmission:   b.TransportEmission,
+			ElectricityEmission: b.ElectricityEmission,
+			DietEmission:        b.DietEmission,
+			TotalEmission:       b.TotalEmission,
+			CreatedAt:           b.CreatedAt,
+		})
+	}
+
+	result.Current = &result.History[0]
+	if len(result.History) > 1 {
+		result.Previous = &result.History[1]
+		result.ChangeKg = result.Current.TotalEmission - result.Previous.TotalEmission
+		if result.Previous.TotalEmission > 0 {
+			result.ChangePercent = result.ChangeKg / result.Previous.TotalEmission * 100
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+)
+
+var ErrAlreadyCheckedIn = errors.New("hari ini sudah check-in")
+
+type CheckInServiceInterface interface {
+	GetStatus(ctx context.Context, userID int64) (*dto.CheckInStatusDTO, error)
+	CheckIn(ctx context.Context, userID int64) (*dto.CheckInResultDTO, error)
+}
+
+type checkInService struct {
+	checkInRepo repository.CheckInRepositoryInterface
+	streakRepo  repository.StreakRepositoryInterface
+	pointsRepo  repository.PointsRepositoryInterface
+	missionRepo repository.CheckMissionRepositoryInterface
+}
+
+func NewCheckInService(
+	checkInRepo repository.CheckInRepositoryInterface,
+	streakRepo repository.StreakRepositoryInterface,
+	pointsRepo repository.PointsRepositoryInterface,
+	missionRepo repository.CheckMissionRepositoryInterface,
+) CheckInServiceInterface {
+	return &checkInService{
+		checkInRepo: checkInRepo,
+		streakRepo:  streakRepo,
+		pointsRepo:  pointsRepo,
+		missionRepo: missionRepo,
+	}
+}
+
+// GetStatus status check-in hari ini, hadiah berikutnya dan tabel hadiah siklus
+func (s *checkInService) GetStatus(ctx context.Context, userID int64) (*dto.CheckInStatusDTO, error) {
+	streak, err := s.streakRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &model.UserStreak{UserID: userID, Timezone: model.DefaultStreakTimezone}
+	}
+
+	recent, err := s.checkInRepo.FindRecent(ctx, userID, len(model.CheckInRewards))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := model.LocalDate(now, streak.Location())
+	res := &dto.CheckInStatusDTO{
+		Timezone: streak.Timezone,
+		Today:    today.Format("2006-01-02"),
+		Rewards:  checkInRewardTable(),
+		Recent:   make([]dto.CheckInDTO, 0, len(recent)),
+	}
+	for _, c := range recent {
+		res.Recent = append(res.Recent, checkInToDTO(c))
+	}
+
+	// Pratinjau check-in berikutnya: hari ini, atau besok kalau hari ini sudah check-in
+	var latest *model.DailyCheckIn
+	nextDay := today
+	if len(recent) > 0 {
+		latest = recent[0]
+		res.CheckedInToday = !latest.LocalDate.Before(today)
+		if res.CheckedInToday {
+			res.StreakDay = latest.StreakDay
+			nextDay = latest.LocalDate.AddDate(0, 0, 1)
+		}
+	}
+	next := model.NewDailyCheckIn(userID, nextDay, latest, now)
+	if !res.CheckedInToday && next.StreakDay > 1 {
+		res.StreakDay = latest.StreakDay
+	}
+	res.NextCycleDay = next.CycleDay
+	res.NextReward = next.TotalPoints()
+
+	return res, nil
+}
+
+// CheckIn mencatat check-in hari ini lalu menambahkan hadiahnya lewat point_transactions.
+// Hadiah dibayar dengan idempotency_key per tanggal sebelum check-in ditandai rewarded,
+// jadi kalau proses gagal di tengah, check-in ulang di hari yang sama menyelesaikannya
+// tanpa membayar dua kali.
+func (s *checkInService) CheckIn(ctx context.Context, userID int64) (*dto.CheckInResultDTO, error) {
+	now := time.Now()
+	checkIn, created, err := s.checkInRepo.CheckIn(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !created && checkIn.RewardedAt.Valid {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	if _, err := s.pointsRepo.AddPointsOnce(ctx, userID, checkIn.TotalPoints(), model.CheckInPointSource,
+		"daily_checkins", checkIn.ID, checkIn.RewardKey()); err != nil {
+		return nil, err
+	}
+
+	// Request bersamaan sama-sama lolos pembayaran (hanya satu yang benar-benar menambah poin);
+	// yang berhasil menandai rewarded yang menyelesaikan check-in
+	marked, err := s.checkInRepo.MarkRewarded(ctx, checkIn.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	s.publishCheckIn(ctx, userID, checkIn, now)
+
+	points, err := s.pointsRepo.GetUserPoints(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CheckInResultDTO{CheckIn: checkInToDTO(checkIn), TotalPoints: points.TotalPoints}, nil
+}
+
+// publishCheckIn memicu misi streak dan misi poin
+func (s *checkInService) publishCheckIn(ctx context.Context, userID int64, checkIn *model.DailyCheckIn, now time.Time) {
+	events := []model.MissionEvent{
+		{
+			Type:       model.EventDailyCheckIn,
+			UserID:     userID,
+			SourceType: "daily_checkins",
+			SourceID:   checkIn.ID,
+			OccurredAt: now,
+		},
+		{
+			Type:       model.EventPointsEarned,
+			UserID:     userID,
+			Points:     float64(checkIn.TotalPoints()),
+			SourceType: "daily_checkins",
+			SourceID:   checkIn.ID,
+			OccurredAt: now,
+		},
+	}
+	for _, event := range events {
+		if err := s.missionRepo.Publish(ctx, event); err != nil {
+			fmt.Printf("Gagal check missions setelah check-in: %v\n", err)
+		}
+	}
+}
+
+func checkInRewardTable() []dto.CheckInRewardDTO {
+	rewards := make([]dto.CheckInRewardDTO, 0, len(model.CheckInRewards))
+	for i := range model.CheckInRewards {
+		reward, bonus := model.CheckInReward(i + 1)
+		rewards = append(rewards, dto.CheckInRewardDTO{Day: i + 1, Points: reward, Bonus: bonus})
+	}
+	return rewards
+}
+
+func checkInToDTO(c *model.DailyCheckIn) dto.CheckInDTO {
+	return dto.CheckInDTO{
+		ID:           c.ID,
+		Date:         c.LocalDate.Format("2006-01-02"),
+		StreakDay:    c.StreakDay,
+		CycleDay:     c.CycleDay,
+		RewardPoints: c.RewardPoints,
+		BonusPoints:  c.BonusPoints,
+		TotalPoints:  c.TotalPoints(),
+		CreatedAt:    c.CreatedAt,
+	}
+}
@@ -12,6 +12,7 @@ type UserCustomEndpointServiceInterface interface {
 	GetLeaderboard(ctx context.Context, req *dto.LeaderboardRequestDTO) (*dto.LeaderboardResponseDTO, error)
 	GetMissionProgress(ctx context.Context, userID, missionID int64) (float64, error)
 	GetAllMissionProgress(ctx context.Context, userID int64) ([]dto.MissionProgressResponse, error)
+	RecomputeMissionProgress(ctx context.Context, userID int64) error
 }
 
 type userCustomEndpointService struct {
@@ -199,6 +200,39 @@ func (s *userCustomEndpointService) mapToDTO(data *repository.UserCustomData) *d
 		})
 	}
 
+	// Map flights
+	for _, f := range data.Flights {
+		response.Flights = append(response.Flights, dto.CustomFlightDTO{
+			ID:          f.ID,
+			Origin:      f.Origin,
+			Destination: f.Destination,
+			CabinClass:  f.CabinClass,
+			RoundTrip:   f.RoundTrip,
+			DistanceKm:  f.DistanceKm,
+			TotalCarbon: f.TotalCarbon,
+			LoggedAt:    f.LoggedAt,
+		})
+	}
+
+	for _, mfc := range data.MonthlyFlightCarbon {
+		response.MonthlyFlightCarbon = append(response.MonthlyFlightCarbon, dto.MonthlyCarbonDTO{
+			Month:       mfc.Month,
+			TotalCarbon: mfc.TotalCarbon,
+		})
+	}
+
+	response.MonthlyCategoryCarbon = make(map[string][]dto.MonthlyCarbonDTO)
+	for name, monthly := range data.MonthlyCategoryCarbon {
+		items := make([]dto.MonthlyCarbonDTO, 0, len(monthly))
+		for _, mc := range monthly {
+			items = append(items, dto.MonthlyCarbonDTO{
+				Month:       mc.Month,
+				TotalCarbon: mc.TotalCarbon,
+			})
+		}
+		response.MonthlyCategoryCarbon[name] = items
+	}
+
 	return response
 }
 
@@ -236,4 +270,8 @@ func (s *userCustomEndpointService) GetMissionProgress(ctx context.Context, user
 
 func (s *userCustomEndpointService) GetAllMissionProgress(ctx context.Context, userID int64) ([]dto.MissionProgressResponse, error) {
 	return s.missionRepo.GetAllMissionProgress(ctx, userID)
-}
\ No newline at end of file
+}
+// RecomputeMissionProgress fallback hitung ulang penuh untuk memperbaiki progres misi
+func (s *userCustomEndpointService) RecomputeMissionProgress(ctx context.Context, userID int64) error {
+	return s.checkMissionRepo.CheckAllUserMissions(ctx, userID)
+}
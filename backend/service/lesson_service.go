@@ -0,0 +1,334 @@
+// service/lesson_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+)
+
+var (
+	ErrLessonMissionNotQuiz = errors.New("mission_id must reference a quiz mission")
+	ErrInvalidQuizQuestion  = errors.New("invalid quiz question")
+	ErrInvalidQuizAnswers   = errors.New("invalid quiz answers")
+)
+
+// defaultPassScore persen jawaban benar minimal kalau admin tidak mengisi pass_score
+const defaultPassScore = 70
+
+type LessonServiceInterface interface {
+	CreateLesson(ctx context.Context, req *dto.LessonRequestDTO) (*dto.LessonResponseDTO, error)
+	UpdateLesson(ctx context.Context, id int64, req *dto.LessonRequestDTO) (*dto.LessonResponseDTO, error)
+	DeleteLesson(ctx context.Context, id int64) error
+	GetLessons(ctx context.Context, includeDrafts bool, page, limit int) ([]*dto.LessonResponseDTO, error)
+	GetLessonForAdmin(ctx context.Context, id int64) (*dto.LessonResponseDTO, error)
+	GetLesson(ctx context.Context, userID, id int64) (*dto.LessonResponseDTO, error)
+	SubmitQuiz(ctx context.Context, userID, lessonID int64, req *dto.SubmitQuizDTO) (*dto.QuizResultDTO, error)
+	GetMyAttempts(ctx context.Context, userID, lessonID int64) ([]*dto.QuizAttemptDTO, error)
+}
+
+type lessonService struct {
+	lessonRepo repository.LessonRepositoryInterface
+	checkRepo  repository.CheckMissionRepositoryInterface
+}
+
+func NewLessonService(lessonRepo repository.LessonRepositoryInterface, checkRepo repository.CheckMissionRepositoryInterface) LessonServiceInterface {
+	return &lessonService{lessonRepo: lessonRepo, checkRepo: checkRepo}
+}
+
+func (s *lessonService) CreateLesson(ctx context.Context, req *dto.LessonRequestDTO) (*dto.LessonResponseDTO, error) {
+	lesson, err := s.buildLesson(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	lesson.CreatedAt = time.Now()
+
+	if err := s.lessonRepo.Create(ctx, lesson); err != nil {
+		return nil, err
+	}
+	lesson.QuestionCount = len(lesson.Questions)
+	return lessonToDTO(lesson, true), nil
+}
+
+func (s *lessonService) UpdateLesson(ctx context.Context, id int64, req *dto.LessonRequestDTO) (*dto.LessonResponseDTO, error) {
+	existing, err := s.lessonRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, repository.ErrLessonNotFound
+	}
+
+	lesson, err := s.buildLesson(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	lesson.ID = id
+	lesson.CreatedAt = existing.CreatedAt
+	lesson.UpdatedAt = time.Now()
+
+	if err := s.lessonRepo.Update(ctx, lesson); err != nil {
+		return nil, err
+	}
+	lesson.QuestionCount = len(lesson.Questions)
+	return lessonToDTO(lesson, true), nil
+}
+
+func (s *lessonService) DeleteLesson(ctx context.Context, id int64) error {
+	return s.lessonRepo.Delete(ctx, id)
+}
+
+// buildLesson memvalidasi request admin; misi yang dihubungkan harus bertipe quiz
+func (s *lessonService) buildLesson(ctx context.Context, req *dto.LessonRequestDTO) (*model.Lesson, error) {
+	lesson := &model.Lesson{
+		Title:           strings.TrimSpace(req.Title),
+		Content:         req.Content,
+		PassScore:       req.PassScore,
+		MaxAttempts:     req.MaxAttempts,
+		CooldownMinutes: req.CooldownMinutes,
+		Published:       req.Published,
+	}
+	if lesson.PassScore == 0 {
+		lesson.PassScore = defaultPassScore
+	}
+
+	if req.MissionID != nil {
+		mission, err := s.checkRepo.FindByID(ctx, *req.MissionID)
+		if err != nil {
+			return nil, err
+		}
+		if mission == nil {
+			return nil, ErrMissionNotFound
+		}
+		if mission.MissionType != model.MissionTypeQuiz {
+			return nil, ErrLessonMissionNotQuiz
+		}
+		lesson.MissionID = model.NewNullInt64(mission.ID)
+	}
+
+	for i, q := range req.Questions {
+		if q.CorrectOption >= len(q.Options) {
+			return nil, fmt.Errorf("%w: question %d correct_option must be between 0 and %d", ErrInvalidQuizQuestion, i+1, len(q.Options)-1)
+		}
+		lesson.Questions = append(lesson.Questions, &model.QuizQuestion{
+			Prompt:        strings.TrimSpace(q.Prompt),
+			Options:       q.Options,
+			CorrectOption: q.CorrectOption,
+			Explanation:   q.Explanation,
+		})
+	}
+	return lesson, nil
+}
+
+func (s *lessonService) GetLessons(ctx context.Context, includeDrafts bool, page, limit int) ([]*dto.LessonResponseDTO, error) {
+	lessons, err := s.lessonRepo.FindAll(ctx, !includeDrafts, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.LessonResponseDTO, 0, len(lessons))
+	for _, lesson := range lessons {
+		lessonDTO := lessonToDTO(lesson, false)
+		lessonDTO.Content = ""
+		res = append(res, lessonDTO)
+	}
+	return res, nil
+}
+
+func (s *lessonService) GetLessonForAdmin(ctx context.Context, id int64) (*dto.LessonResponseDTO, error) {
+	lesson, err := s.lessonRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, repository.ErrLessonNotFound
+	}
+	return lessonToDTO(lesson, true), nil
+}
+
+// GetLesson materi dan soal tanpa kunci jawaban, beserta status percobaan user
+func (s *lessonService) GetLesson(ctx context.Context, userID, id int64) (*dto.LessonResponseDTO, error) {
+	lesson, mission, err := s.publishedLesson(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.lessonProgress(ctx, userID, lesson, mission, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	res := lessonToDTO(lesson, false)
+	res.Progress = progress
+	return res, nil
+}
+
+// SubmitQuiz menilai jawaban di server. Setiap soal harus dijawab tepat sekali.
+func (s *lessonService) SubmitQuiz(ctx context.Context, userID, lessonID int64, req *dto.SubmitQuizDTO) (*dto.QuizResultDTO, error) {
+	lesson, mission, err := s.publishedLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int64]int, len(req.Answers))
+	for _, answer := range req.Answers {
+		if _, dup := selected[answer.QuestionID]; dup {
+			return nil, fmt.Errorf("%w: question %d answered more than once", ErrInvalidQuizAnswers, answer.QuestionID)
+		}
+		selected[answer.QuestionID] = answer.Option
+	}
+
+	attempt := &model.QuizAttempt{UserID: userID, Total: len(lesson.Questions)}
+	results := make([]dto.QuizQuestionResultDTO, 0, len(lesson.Questions))
+	for _, q := range lesson.Questions {
+		option, ok := selected[q.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: question %d is not answered", ErrInvalidQuizAnswers, q.ID)
+		}
+		if option >= len(q.Options) {
+			return nil, fmt.Errorf("%w: option for question %d must be between 0 and %d", ErrInvalidQuizAnswers, q.ID, len(q.Options)-1)
+		}
+		delete(selected, q.ID)
+
+		correct := option == q.CorrectOption
+		if correct {
+			attempt.Correct++
+		}
+		attempt.Answers = append(attempt.Answers, model.QuizAnswer{QuestionID: q.ID, Option: option})
+		results = append(results, dto.QuizQuestionResultDTO{QuestionID: q.ID, Selected: option, Correct: correct})
+	}
+	if len(selected) > 0 {
+		return nil, fmt.Errorf("%w: answers contain questions that do not belong to this lesson", ErrInvalidQuizAnswers)
+	}
+
+	if attempt.Total > 0 {
+		attempt.Score = math.Round(float64(attempt.Correct)/float64(attempt.Total)*10000) / 100
+	}
+	attempt.Passed = attempt.Score >= float64(lesson.PassScore)
+
+	now := time.Now()
+	completed, err := s.checkRepo.SubmitQuizAttempt(ctx, lesson, mission, attempt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.lessonProgress(ctx, userID, lesson, mission, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kunci jawaban dibuka setelah lulus atau kalau percobaan sudah habis
+	if attempt.Passed || (progress.AttemptsLeft != nil && *progress.AttemptsLeft == 0) {
+		for i, q := range lesson.Questions {
+			correctOption := q.CorrectOption
+			results[i].CorrectOption = &correctOption
+			results[i].Explanation = q.Explanation
+		}
+	}
+
+	return &dto.QuizResultDTO{
+		AttemptID:        attempt.ID,
+		LessonID:         lesson.ID,
+		Correct:          attempt.Correct,
+		Total:            attempt.Total,
+		Score:            attempt.Score,
+		PassScore:        lesson.PassScore,
+		Passed:           attempt.Passed,
+		MissionCompleted: completed,
+		Results:          results,
+		Progress:         *progress,
+	}, nil
+}
+
+func (s *lessonService) GetMyAttempts(ctx context.Context, userID, lessonID int64) ([]*dto.QuizAttemptDTO, error) {
+	attempts, err := s.lessonRepo.FindUserAttempts(ctx, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.QuizAttemptDTO, 0, len(attempts))
+	for _, a := range attempts {
+		res = append(res, &dto.QuizAttemptDTO{
+			ID:        a.ID,
+			Correct:   a.Correct,
+			Total:     a.Total,
+			Score:     a.Score,
+			Passed:    a.Passed,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// publishedLesson lesson yang sudah dipublikasikan beserta misi quiz-nya (kalau ada)
+func (s *lessonService) publishedLesson(ctx context.Context, id int64) (*model.Lesson, *model.Mission, error) {
+	lesson, err := s.lessonRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lesson == nil || !lesson.Published {
+		return nil, nil, repository.ErrLessonNotFound
+	}
+
+	var mission *model.Mission
+	if lesson.MissionID.Valid {
+		if mission, err = s.checkRepo.FindByID(ctx, lesson.MissionID.Int64); err != nil {
+			return nil, nil, err
+		}
+	}
+	return lesson, mission, nil
+}
+
+func (s *lessonService) lessonProgress(ctx context.Context, userID int64, lesson *model.Lesson, mission *model.Mission, now time.Time) (*dto.LessonProgressDTO, error) {
+	stats, err := s.lessonRepo.FindAttemptStats(ctx, userID, lesson.ID, model.QuizAttemptsSince(mission, now))
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &dto.LessonProgressDTO{Attempts: stats.Attempts, Passed: stats.Passed}
+	if left := lesson.AttemptsLeft(stats); left >= 0 {
+		progress.AttemptsLeft = &left
+	}
+	if until, cooling := lesson.CooldownUntil(stats, now); cooling && !stats.Passed {
+		progress.NextAttemptAt = &until
+	}
+	return progress, nil
+}
+
+// lessonToDTO withAnswers hanya untuk admin
+func lessonToDTO(lesson *model.Lesson, withAnswers bool) *dto.LessonResponseDTO {
+	res := &dto.LessonResponseDTO{
+		ID:              lesson.ID,
+		Title:           lesson.Title,
+		Content:         lesson.Content,
+		PassScore:       lesson.PassScore,
+		MaxAttempts:     lesson.MaxAttempts,
+		CooldownMinutes: lesson.CooldownMinutes,
+		Published:       lesson.Published,
+		QuestionCount:   lesson.QuestionCount,
+		CreatedAt:       lesson.CreatedAt,
+		UpdatedAt:       lesson.UpdatedAt,
+	}
+	if lesson.MissionID.Valid {
+		missionID := lesson.MissionID.Int64
+		res.MissionID = &missionID
+	}
+
+	for _, q := range lesson.Questions {
+		question := dto.QuizQuestionResponseDTO{ID: q.ID, Prompt: q.Prompt, Options: q.Options}
+		if withAnswers {
+			correctOption := q.CorrectOption
+			question.CorrectOption = &correctOption
+			question.Explanation = q.Explanation
+		}
+		res.Questions = append(res.Questions, question)
+	}
+	return res
+}
@@ -0,0 +1,218 @@
+// service/mission_admin_service.go
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/rule"
+)
+
+// UpdateMission mengubah misi yang mungkin sedang berjalan. Progres peserta tidak diubah;
+// kalau target turun, peserta yang progresnya sudah cukup langsung diselesaikan.
+// Penyelesaian dan poin yang sudah diberikan tidak dicabut kalau target dinaikkan.
+func (s *missionService) UpdateMission(ctx context.Context, missionID int64, req *dto.UpdateMissionDTO) (*dto.UpdateMissionResponseDTO, error) {
+	mission, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, ErrMissionNotFound
+	}
+	if err := s.attachPrerequisites(ctx, []*model.Mission{mission}); err != nil {
+		return nil, err
+	}
+
+	oldTarget, oldMax := mission.TargetValue, mission.TargetMax
+
+	if req.Title != nil {
+		mission.Title = *req.Title
+	}
+	if req.Description != nil {
+		mission.Description = *req.Description
+	}
+	if req.PointsReward != nil {
+		mission.PointsReward = *req.PointsReward
+	}
+	if req.GivesBadge != nil {
+		mission.GivesBadge = *req.GivesBadge
+	}
+	if req.BadgeID != nil {
+		mission.BadgeID = model.NewNullInt64(*req.BadgeID)
+	}
+	if req.TargetValue != nil {
+		mission.TargetValue = *req.TargetValue
+	}
+	if req.TargetMax != nil {
+		if mission.Comparator != model.ComparatorBetween {
+			return nil, errors.New("target_max only applies to between comparator")
+		}
+		mission.TargetMax = model.NewNullFloat64(*req.TargetMax)
+	}
+	if mission.Comparator == model.ComparatorBetween && mission.TargetMax.Float64 < mission.TargetValue {
+		return nil, errors.New("between comparator requires target_max >= target_value")
+	}
+	if req.ExpiredAt != nil {
+		mission.ExpiredAt = model.NewNullTime(*req.ExpiredAt)
+	}
+	if req.StartsAt != nil {
+		mission.StartsAt = model.NewNullTime(*req.StartsAt)
+	}
+	if mission.StartsAt.Valid && mission.ExpiredAt.Valid && !mission.StartsAt.Time.Before(mission.ExpiredAt.Time) {
+		return nil, errors.New("starts_at must be before expired_at")
+	}
+	if req.AutoJoin != nil {
+		mission.AutoJoin = *req.AutoJoin
+	}
+	if req.Prerequisites != nil {
+		mission.Prerequisites = *req.Prerequisites
+	}
+	if req.PrerequisiteMode != nil {
+		mission.PrerequisiteMode = model.PrerequisiteMode(*req.PrerequisiteMode)
+	}
+	if req.MinContribution != nil {
+		if !mission.IsCollective() {
+			return nil, errors.New("min_contribution only applies to team or community missions")
+		}
+		mission.MinContribution = *req.MinContribution
+	}
+	if req.MinActivityLogs != nil {
+		mission.MinActivityLogs = *req.MinActivityLogs
+	}
+
+	targetChanged := mission.TargetValue != oldTarget || mission.TargetMax != oldMax
+	if targetChanged && mission.Rule.Valid {
+		if err := syncRuleTarget(mission); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.missionRepo.Update(ctx, mission); err != nil {
+		return nil, fmt.Errorf("failed to update mission: %w", err)
+	}
+
+	res := &dto.UpdateMissionResponseDTO{TargetChanged: targetChanged}
+	if targetChanged && !mission.ArchivedAt.Valid {
+		if res.NewlyCompleted, err = s.checkRepo.ReevaluateMission(ctx, mission); err != nil {
+			return nil, fmt.Errorf("mission updated but re-evaluation failed: %w", err)
+		}
+	}
+	res.Mission = *s.missionToDTO(mission)
+	return res, nil
+}
+
+// syncRuleTarget menyamakan target di aturan JSON dengan kolom misi
+func syncRuleTarget(mission *model.Mission) error {
+	missionRule, err := rule.Parse([]byte(mission.Rule.String))
+	if err != nil {
+		return err
+	}
+	missionRule.Target = mission.TargetValue
+	if mission.TargetMax.Valid {
+		missionRule.TargetMax = &mission.TargetMax.Float64
+	}
+
+	normalized, err := json.Marshal(missionRule)
+	if err != nil {
+		return err
+	}
+	mission.Rule = model.NewNullString(string(normalized))
+	return nil
+}
+
+func (s *missionService) ArchiveMission(ctx context.Context, missionID int64) error {
+	archived, err := s.missionRepo.Archive(ctx, missionID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !archived {
+		return ErrMissionNotFound
+	}
+	return nil
+}
+
+func (s *missionService) RestoreMission(ctx context.Context, missionID int64) error {
+	restored, err := s.missionRepo.Restore(ctx, missionID)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return ErrMissionNotFound
+	}
+	return nil
+}
+
+// DeleteMission hanya untuk misi yang belum pernah diikuti; selain itu gunakan arsip
+func (s *missionService) DeleteMission(ctx context.Context, missionID int64) error {
+	deleted, err := s.missionRepo.Delete(ctx, missionID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMissionNotFound
+	}
+	return nil
+}
+
+// DuplicateMission menyalin misi (termasuk aturan dan prasyarat) sebagai misi baru
+func (s *missionService) DuplicateMission(ctx context.Context, missionID int64, req *dto.DuplicateMissionDTO) (*dto.MissionResponseDTO, error) {
+	source, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrMissionNotFound
+	}
+	if err := s.attachPrerequisites(ctx, []*model.Mission{source}); err != nil {
+		return nil, err
+	}
+
+	clone := *source
+	clone.ID = 0
+	clone.CreatedAt = time.Now()
+	clone.ArchivedAt = sql.NullTime{}
+	clone.Title = source.Title + " (copy)"
+	if req.Title != nil {
+		clone.Title = *req.Title
+	}
+	if req.StartsAt != nil {
+		clone.StartsAt = model.NewNullTime(*req.StartsAt)
+	}
+	if req.ExpiredAt != nil {
+		clone.ExpiredAt = model.NewNullTime(*req.ExpiredAt)
+	}
+	if clone.StartsAt.Valid && clone.ExpiredAt.Valid && !clone.StartsAt.Time.Before(clone.ExpiredAt.Time) {
+		return nil, errors.New("starts_at must be before expired_at")
+	}
+
+	if err := s.missionRepo.Create(ctx, &clone); err != nil {
+		return nil, fmt.Errorf("failed to duplicate mission: %w", err)
+	}
+	return s.missionToDTO(&clone), nil
+}
+
+func (s *missionService) GetArchivedMissions(ctx context.Context, page, limit int) ([]*dto.MissionResponseDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	missions, err := s.missionRepo.FindArchived(ctx, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*dto.MissionResponseDTO{}
+	for _, mission := range missions {
+		result = append(result, s.missionToDTO(mission))
+	}
+	return result, nil
+}
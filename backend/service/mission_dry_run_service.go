@@ -0,0 +1,153 @@
+// service/mission_dry_run_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+var ErrDryRunCollective = errors.New("dry run only supports individual missions")
+
+// dryRunBuckets batas atas (persen target) tiap bucket distribusi progres
+var dryRunBuckets = []struct {
+	label string
+	upper float64
+}{
+	{"0%", 0},
+	{"1-24%", 25},
+	{"25-49%", 50},
+	{"50-74%", 75},
+	{"75-99%", 100},
+	{">=100%", math.Inf(1)},
+}
+
+// DryRunMission mengevaluasi misi draft ke semua user (atau sampel) tanpa menyimpan apa pun,
+// seolah misi dipublikasikan dan diikuti user pada waktu req.At
+func (s *missionService) DryRunMission(ctx context.Context, req *dto.DryRunMissionDTO) (*dto.MissionDryRunResultDTO, error) {
+	mission, err := buildMission(req.Mission)
+	if err != nil {
+		return nil, err
+	}
+	if mission.IsCollective() {
+		return nil, ErrDryRunCollective
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+	mission.CreatedAt = at
+
+	userIDs, err := s.checkRepo.FindDryRunUserIDs(ctx, req.SampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// User yang belum memenuhi prasyarat tidak akan bisa ikut
+	completed, err := s.checkRepo.FindCompletedAmong(ctx, mission.Prerequisites)
+	if err != nil {
+		return nil, err
+	}
+	eligible := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if mission.PrerequisitesMet(completed[userID]) {
+			eligible = append(eligible, userID)
+		}
+	}
+
+	outcomes, err := s.checkRepo.DryRunProgress(ctx, mission, eligible, at)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.MissionDryRunResultDTO{
+		UsersEvaluated:     len(userIDs),
+		Sampled:            req.SampleSize > 0,
+		EligibleUsers:      len(eligible),
+		SettlesAtWindowEnd: mission.SettlesAtWindowEnd(),
+		Distribution:       make([]dto.DryRunBucketDTO, len(dryRunBuckets)),
+	}
+	from, to := mission.DryRunWindow(at)
+	res.WindowFrom, res.WindowTo = from, to
+	res.EnrollmentFromWindowStart = mission.UsesEnrollmentWindow()
+	for i, bucket := range dryRunBuckets {
+		res.Distribution[i].Label = bucket.label
+	}
+
+	values := make([]float64, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		values = append(values, outcome.Progress)
+		if outcome.Progress > 0 {
+			res.UsersWithProgress++
+		}
+		if outcome.Completes {
+			res.WouldComplete++
+		}
+		res.Distribution[dryRunBucket(mission, outcome.Progress)].Users++
+	}
+
+	if len(eligible) > 0 {
+		res.CompletionRate = math.Round(float64(res.WouldComplete)/float64(len(eligible))*10000) / 100
+	}
+	res.PointsLiability = res.WouldComplete * mission.PointsReward
+	if mission.GivesBadge {
+		res.BadgesAwarded = res.WouldComplete
+	}
+	res.Percentiles = progressPercentiles(values)
+
+	return res, nil
+}
+
+func dryRunBucket(mission *model.Mission, value float64) int {
+	if value <= 0 || mission.TargetValue <= 0 {
+		if value > 0 {
+			return len(dryRunBuckets) - 1
+		}
+		return 0
+	}
+	percent := value / mission.TargetValue * 100
+	for i, bucket := range dryRunBuckets[1:] {
+		if percent < bucket.upper {
+			return i + 1
+		}
+	}
+	return len(dryRunBuckets) - 1
+}
+
+// progressPercentiles persentil dengan metode nearest-rank
+func progressPercentiles(values []float64) dto.DryRunPercentilesDTO {
+	if len(values) == 0 {
+		return dto.DryRunPercentilesDTO{}
+	}
+	sort.Float64s(values)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(values)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return values[i]
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return dto.DryRunPercentilesDTO{
+		Min:  values[0],
+		P25:  rank(25),
+		P50:  rank(50),
+		P75:  rank(75),
+		P90:  rank(90),
+		P99:  rank(99),
+		Max:  values[len(values)-1],
+		Mean: sum / float64(len(values)),
+	}
+}
@@ -0,0 +1,253 @@
+// service/mission_import_format.go
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ImportFormatYAML = "yaml"
+	ImportFormatCSV  = "csv"
+)
+
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// importListSeparator pemisah nilai daftar (prerequisites, prerequisite_keys) di sel CSV
+const importListSeparator = ";"
+
+// importRow satu definisi misi mentah beserta posisinya di file
+type importRow struct {
+	row    int
+	fields map[string]any
+	// Sel CSV yang tidak bisa diubah ke tipe kolomnya
+	cellErrors []dto.MissionImportErrorDTO
+}
+
+// importFields tipe tiap kolom ImportMissionDTO berdasarkan nama json-nya
+var importFields = collectImportFields(reflect.TypeOf(dto.ImportMissionDTO{}))
+
+func collectImportFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			for name, typ := range collectImportFields(field.Type) {
+				fields[name] = typ
+			}
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func decodeImportFile(format string, data []byte) ([]importRow, error) {
+	// Spreadsheet sering menyimpan BOM di awal file
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	switch strings.ToLower(format) {
+	case ImportFormatYAML, "yml":
+		return decodeYAMLImport(data)
+	case ImportFormatCSV:
+		return decodeCSVImport(data)
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q, use yaml or csv", ErrInvalidImportFile, format)
+}
+
+// decodeYAMLImport menerima daftar misi langsung atau di bawah key "missions"
+func decodeYAMLImport(data []byte) ([]importRow, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	if root, ok := doc.(map[string]any); ok {
+		doc = root["missions"]
+	}
+	list, ok := doc.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a list of missions", ErrInvalidImportFile)
+	}
+
+	rows := make([]importRow, 0, len(list))
+	for i, item := range list {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: mission %d is not a mapping", ErrInvalidImportFile, i+1)
+		}
+		rows = append(rows, importRow{row: i + 1, fields: fields})
+	}
+	return rows, nil
+}
+
+// decodeCSVImport baris pertama berisi nama kolom (sama dengan field JSON); sel kosong
+// berarti field tidak diisi
+func decodeCSVImport(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := importFields[header[i]]; !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidImportFile, column)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		fields := map[string]any{}
+		var cellErrors []dto.MissionImportErrorDTO
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			value, err := parseImportCell(importFields[header[i]], cell)
+			if err != nil {
+				cellErrors = append(cellErrors, dto.MissionImportErrorDTO{Row: line, Field: header[i], Message: err.Error()})
+				continue
+			}
+			fields[header[i]] = value
+		}
+		if len(fields) == 0 && len(cellErrors) == 0 {
+			continue
+		}
+		key, _ := fields["key"].(string)
+		for i := range cellErrors {
+			cellErrors[i].Key = key
+		}
+		rows = append(rows, importRow{row: line, fields: fields, cellErrors: cellErrors})
+	}
+	return rows, nil
+}
+
+// parseImportCell mengubah teks sel CSV ke nilai sesuai tipe field DTO
+func parseImportCell(typ reflect.Type, cell string) (any, error) {
+	if typ == reflect.TypeOf(json.RawMessage{}) {
+		if !json.Valid([]byte(cell)) {
+			return nil, errors.New("must be valid JSON")
+		}
+		return json.RawMessage(cell), nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return parseImportTime(cell)
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return cell, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return b, nil
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return f, nil
+	case reflect.Slice:
+		var list []any
+		for _, part := range strings.Split(cell, importListSeparator) {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if typ.Elem().Kind() == reflect.String {
+				list = append(list, part)
+				continue
+			}
+			id, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("must be a list of ids separated by %q", importListSeparator)
+			}
+			list = append(list, id)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported column type %s", typ)
+}
+
+// parseImportTime menerima RFC3339 atau tanggal/waktu tanpa zona seperti yang diekspor spreadsheet
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("must be a date (YYYY-MM-DD) or RFC3339 time")
+}
+
+// toImportDTO mengubah field mentah ke ImportMissionDTO; field yang tidak dikenal ditolak
+// supaya salah ketik nama kolom tidak diam-diam diabaikan
+func toImportDTO(row importRow) (*dto.ImportMissionDTO, *dto.MissionImportErrorDTO) {
+	key, _ := row.fields["key"].(string)
+	fail := func(field, message string) *dto.MissionImportErrorDTO {
+		return &dto.MissionImportErrorDTO{Row: row.row, Key: key, Field: field, Message: message}
+	}
+
+	for name, value := range row.fields {
+		typ, ok := importFields[name]
+		if !ok {
+			return nil, fail(name, "unknown field")
+		}
+		if s, ok := value.(string); ok && typ == reflect.TypeOf(&time.Time{}) {
+			t, err := parseImportTime(s)
+			if err != nil {
+				return nil, fail(name, err.Error())
+			}
+			row.fields[name] = t
+		}
+	}
+
+	raw, err := json.Marshal(row.fields)
+	if err != nil {
+		return nil, fail("", err.Error())
+	}
+
+	req := new(dto.ImportMissionDTO)
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fail(typeErr.Field, "must be "+typeErr.Type.String())
+		}
+		return nil, fail("", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return req, nil
+}
@@ -0,0 +1,521 @@
+// service/mission_import_service.go
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+	"github.com/Qodarrz/fiber-app/rule"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxImportMissions batas jumlah misi per file import
+const maxImportMissions = 500
+
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+)
+
+// MissionImportError seluruh kesalahan validasi import; kalau ada, tidak ada yang disimpan
+type MissionImportError struct {
+	Errors []dto.MissionImportErrorDTO
+}
+
+func (e *MissionImportError) Error() string {
+	return fmt.Sprintf("import has %d validation errors", len(e.Errors))
+}
+
+var importValidator = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+	return v
+}()
+
+// importPlan rencana untuk satu misi di file
+type importPlan struct {
+	row      int
+	req      *dto.ImportMissionDTO
+	mission  *model.Mission
+	fileDeps []string // prasyarat yang didefinisikan di file yang sama
+	result   dto.MissionImportItemDTO
+	item     *repository.MissionImportItem
+	// Target berubah pada misi yang sudah ada; peserta dinilai ulang setelah import
+	reevaluate bool
+}
+
+// ImportMissions membuat atau mengubah misi (beserta badge) dari file YAML/CSV berdasarkan key.
+// Seluruh file divalidasi dulu; dengan dryRun hanya diff yang dikembalikan.
+func (s *missionService) ImportMissions(ctx context.Context, format string, data []byte, dryRun bool) (*dto.MissionImportResultDTO, error) {
+	rows, err := decodeImportFile(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no missions found", ErrInvalidImportFile)
+	}
+	if len(rows) > maxImportMissions {
+		return nil, fmt.Errorf("%w: at most %d missions per import", ErrInvalidImportFile, maxImportMissions)
+	}
+
+	var errs []dto.MissionImportErrorDTO
+	fail := func(p *importPlan, field, message string) {
+		errs = append(errs, dto.MissionImportErrorDTO{Row: p.row, Key: p.req.Key, Field: field, Message: message})
+	}
+
+	// 1. Decode dan validasi tiap baris
+	plans := make([]*importPlan, 0, len(rows))
+	byKey := map[string]*importPlan{}
+	invalidKeys := map[string]bool{}
+	for _, row := range rows {
+		if len(row.cellErrors) > 0 {
+			errs = append(errs, row.cellErrors...)
+			continue
+		}
+		req, rowErr := toImportDTO(row)
+		if rowErr != nil {
+			errs = append(errs, *rowErr)
+			continue
+		}
+		p := &importPlan{row: row.row, req: req}
+
+		if err := importValidator.Struct(req); err != nil {
+			var fieldErrs validator.ValidationErrors
+			if !errors.As(err, &fieldErrs) {
+				return nil, err
+			}
+			for _, fe := range fieldErrs {
+				fail(p, fe.Field(), importValidationMessage(fe))
+			}
+			invalidKeys[req.Key] = true
+			continue
+		}
+		if prev, ok := byKey[req.Key]; ok {
+			fail(p, "key", fmt.Sprintf("duplicate key, already used on row %d", prev.row))
+			continue
+		}
+
+		mission, err := buildMission(&req.CreateMissionDTO)
+		if err != nil {
+			fail(p, "", err.Error())
+			invalidKeys[req.Key] = true
+			continue
+		}
+		if msg := validateImportBadge(req); msg != "" {
+			fail(p, "badge_name", msg)
+			invalidKeys[req.Key] = true
+			continue
+		}
+		p.mission = mission
+		byKey[req.Key] = p
+		plans = append(plans, p)
+	}
+
+	keys, err := s.missionRepo.FindExternalKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Prasyarat berdasarkan key: misi di file yang sama atau hasil import sebelumnya
+	for _, p := range plans {
+		for _, key := range p.req.PrerequisiteKeys {
+			switch {
+			case key == p.req.Key:
+				fail(p, "prerequisite_keys", "mission cannot be its own prerequisite")
+			case byKey[key] != nil:
+				p.fileDeps = append(p.fileDeps, key)
+			case keys[key] != 0:
+				p.mission.Prerequisites = append(p.mission.Prerequisites, keys[key])
+			case invalidKeys[key]:
+				// Kesalahannya sudah dilaporkan di baris misi itu sendiri
+			default:
+				fail(p, "prerequisite_keys", fmt.Sprintf("unknown mission key %q", key))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MissionImportError{Errors: errs}
+	}
+
+	ordered, cycle := orderImportPlans(plans, byKey)
+	if cycle != nil {
+		return nil, &MissionImportError{Errors: []dto.MissionImportErrorDTO{{
+			Row: cycle[0].row, Key: cycle[0].req.Key, Field: "prerequisite_keys",
+			Message: "prerequisites form a cycle: " + strings.Join(importCycleKeys(cycle), " -> "),
+		}}}
+	}
+
+	// 3. Bandingkan dengan misi yang sudah ada
+	labels := make(map[int64]string, len(keys))
+	for key, id := range keys {
+		labels[id] = key
+	}
+	for _, p := range ordered {
+		if err := s.planImport(ctx, p, keys, labels); err != nil {
+			var planErr *importPlanError
+			if errors.As(err, &planErr) {
+				fail(p, planErr.field, planErr.message)
+				continue
+			}
+			return nil, err
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &MissionImportError{Errors: errs}
+	}
+
+	res := &dto.MissionImportResultDTO{DryRun: dryRun, Items: make([]dto.MissionImportItemDTO, 0, len(plans))}
+	var items []*repository.MissionImportItem
+	for _, p := range ordered {
+		if p.item != nil {
+			items = append(items, p.item)
+		}
+	}
+
+	if !dryRun && len(items) > 0 {
+		if err := s.missionRepo.ImportMissions(ctx, items); err != nil {
+			return nil, fmt.Errorf("failed to import missions: %w", err)
+		}
+		for _, p := range ordered {
+			if p.item == nil {
+				continue
+			}
+			id := p.item.Mission.ID
+			p.result.MissionID = &id
+			if p.reevaluate {
+				if p.result.NewlyCompleted, err = s.checkRepo.ReevaluateMission(ctx, p.item.Mission); err != nil {
+					return nil, fmt.Errorf("missions imported but re-evaluation of %s failed: %w", p.req.Key, err)
+				}
+			}
+		}
+	}
+
+	// Hasil mengikuti urutan file, bukan urutan penyimpanan
+	for _, p := range plans {
+		switch p.result.Action {
+		case ImportActionCreate:
+			res.Created++
+		case ImportActionUpdate:
+			res.Updated++
+		default:
+			res.Unchanged++
+		}
+		res.Items = append(res.Items, p.result)
+	}
+	return res, nil
+}
+
+type importPlanError struct {
+	field   string
+	message string
+}
+
+func (e *importPlanError) Error() string {
+	return e.message
+}
+
+// planImport menentukan aksi untuk satu misi dan, kalau ada perubahan, item yang disimpan
+func (s *missionService) planImport(ctx context.Context, p *importPlan, keys map[string]int64, labels map[int64]string) error {
+	req, mission := p.req, p.mission
+	p.result = dto.MissionImportItemDTO{Row: p.row, Key: req.Key}
+
+	// Prasyarat di file yang misinya sudah ada bisa langsung pakai id
+	var pendingKeys []string
+	for _, key := range p.fileDeps {
+		if id, ok := keys[key]; ok {
+			mission.Prerequisites = append(mission.Prerequisites, id)
+		} else {
+			pendingKeys = append(pendingKeys, key)
+		}
+	}
+
+	var badge *model.Badge
+	if req.GivesBadge && req.BadgeID == nil {
+		badge = &model.Badge{Name: req.BadgeName, ImageURL: req.BadgeImageURL, Description: req.BadgeDescription}
+	}
+
+	existingID, exists := keys[req.Key]
+	if !exists {
+		p.result.Action = ImportActionCreate
+		p.item = &repository.MissionImportItem{Key: req.Key, Mission: mission, PrerequisiteKeys: pendingKeys, Badge: badge}
+		return nil
+	}
+
+	existing, err := s.missionRepo.FindByID(ctx, existingID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrMissionNotFound
+	}
+	if err := s.attachPrerequisites(ctx, []*model.Mission{existing}); err != nil {
+		return err
+	}
+	p.result.MissionID = &existing.ID
+
+	if field, err := checkImportImmutable(existing, mission); err != nil {
+		return &importPlanError{field: field, message: err.Error()}
+	}
+
+	var changes []dto.MissionImportChangeDTO
+	diff := func(field string, from, to any) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, dto.MissionImportChangeDTO{Field: field, From: from, To: to})
+		}
+	}
+	diff("title", existing.Title, mission.Title)
+	diff("description", existing.Description, mission.Description)
+	diff("points_reward", existing.PointsReward, mission.PointsReward)
+	diff("gives_badge", existing.GivesBadge, mission.GivesBadge)
+	diff("target_value", existing.TargetValue, mission.TargetValue)
+	diff("target_max", importFloat(existing.TargetMax), importFloat(mission.TargetMax))
+	diff("starts_at", importTime(existing.StartsAt), importTime(mission.StartsAt))
+	diff("expired_at", importTime(existing.ExpiredAt), importTime(mission.ExpiredAt))
+	diff("auto_join", existing.AutoJoin, mission.AutoJoin)
+	diff("prerequisite_mode", existing.PrerequisiteMode, mission.PrerequisiteMode)
+	diff("prerequisites", importPrerequisiteLabels(existing.Prerequisites, nil, labels),
+		importPrerequisiteLabels(mission.Prerequisites, pendingKeys, labels))
+	diff("min_contribution", existing.MinContribution, mission.MinContribution)
+	diff("min_activity_logs", existing.MinActivityLogs, mission.MinActivityLogs)
+
+	merged := *existing
+	merged.Title, merged.Description = mission.Title, mission.Description
+	merged.PointsReward, merged.GivesBadge = mission.PointsReward, mission.GivesBadge
+	merged.TargetValue, merged.TargetMax = mission.TargetValue, mission.TargetMax
+	merged.StartsAt, merged.ExpiredAt = mission.StartsAt, mission.ExpiredAt
+	merged.AutoJoin, merged.PrerequisiteMode = mission.AutoJoin, mission.PrerequisiteMode
+	merged.Prerequisites = mission.Prerequisites
+	merged.MinContribution, merged.MinActivityLogs = mission.MinContribution, mission.MinActivityLogs
+	merged.Rule = mission.Rule
+
+	// Badge: badge_id langsung, atau badge milik misi diperbarui/dibuat dari metadata
+	if req.BadgeID != nil {
+		diff("badge_id", importInt(existing.BadgeID), *req.BadgeID)
+		merged.BadgeID = mission.BadgeID
+	} else if badge != nil {
+		var current model.Badge
+		if existing.BadgeID.Valid {
+			found, err := s.badgeRepo.FindByID(ctx, existing.BadgeID.Int64)
+			if err != nil {
+				return err
+			}
+			if found != nil {
+				current = *found
+				badge.ID = found.ID
+			}
+		}
+		before := len(changes)
+		diff("badge_name", current.Name, badge.Name)
+		diff("badge_image_url", current.ImageURL, badge.ImageURL)
+		diff("badge_description", current.Description, badge.Description)
+		if len(changes) == before {
+			badge = nil
+		}
+	}
+
+	if len(changes) == 0 {
+		p.result.Action = ImportActionUnchanged
+		return nil
+	}
+
+	p.result.Action = ImportActionUpdate
+	p.result.Changes = changes
+	p.item = &repository.MissionImportItem{Key: req.Key, Mission: &merged, PrerequisiteKeys: pendingKeys, Badge: badge}
+	p.reevaluate = !existing.ArchivedAt.Valid &&
+		(existing.TargetValue != merged.TargetValue || existing.TargetMax != merged.TargetMax)
+	return nil
+}
+
+// checkImportImmutable kolom yang mengubah arti progres tidak bisa diubah lewat import,
+// sama seperti UpdateMission; gunakan key baru untuk misi dengan definisi berbeda
+func checkImportImmutable(existing, mission *model.Mission) (string, error) {
+	fields := []struct {
+		name     string
+		from, to any
+	}{
+		{"mission_type", existing.MissionType, mission.MissionType},
+		{"criteria_type", existing.CriteriaType, mission.CriteriaType},
+		{"scope", existing.Scope, mission.Scope},
+		{"comparator", existing.Comparator, mission.Comparator},
+		{"progress_window", existing.ProgressWindow, mission.ProgressWindow},
+		{"recurrence", existing.Recurrence, mission.Recurrence},
+		{"recurrence_rule", existing.RecurrenceRule, mission.RecurrenceRule},
+	}
+	for _, f := range fields {
+		if f.from != f.to {
+			return f.name, fmt.Errorf("%s cannot change from %v to %v on an existing mission, use a new key", f.name, f.from, f.to)
+		}
+	}
+
+	same, err := sameRuleShape(existing.Rule, mission.Rule)
+	if err != nil {
+		return "rule", err
+	}
+	if !same {
+		return "rule", errors.New("only the rule target can change on an existing mission, use a new key")
+	}
+	return "", nil
+}
+
+// sameRuleShape membandingkan dua aturan JSON tanpa memperhatikan target-nya
+func sameRuleShape(a, b sql.NullString) (bool, error) {
+	if a.Valid != b.Valid {
+		return false, nil
+	}
+	if !a.Valid {
+		return true, nil
+	}
+
+	shape := func(raw string) (string, error) {
+		r, err := rule.Parse([]byte(raw))
+		if err != nil {
+			return "", err
+		}
+		r.Target, r.TargetMax = 0, nil
+		normalized, err := json.Marshal(r)
+		return string(normalized), err
+	}
+	from, err := shape(a.String)
+	if err != nil {
+		return false, err
+	}
+	to, err := shape(b.String)
+	if err != nil {
+		return false, err
+	}
+	return from == to, nil
+}
+
+// validateImportBadge aturan yang sama dengan CreateMissionWithBadge
+func validateImportBadge(req *dto.ImportMissionDTO) string {
+	hasMetadata := req.BadgeName != "" || req.BadgeImageURL != "" || req.BadgeDescription != ""
+	switch {
+	case req.BadgeID != nil && hasMetadata:
+		return "use either badge_id or badge_name/badge_image_url, not both"
+	case !req.GivesBadge && hasMetadata:
+		return "badge fields require gives_badge to be true"
+	case req.GivesBadge && req.BadgeID == nil && (req.BadgeName == "" || req.BadgeImageURL == ""):
+		return "badge_name and badge_image_url are required when gives_badge is true"
+	}
+	return ""
+}
+
+// orderImportPlans mengurutkan misi supaya prasyarat di file yang sama disimpan lebih dulu.
+// Kalau ada siklus, misi-misi dalam siklus dikembalikan.
+func orderImportPlans(plans []*importPlan, byKey map[string]*importPlan) ([]*importPlan, []*importPlan) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[*importPlan]int{}
+	ordered := make([]*importPlan, 0, len(plans))
+	var stack []*importPlan
+
+	var visit func(p *importPlan) []*importPlan
+	visit = func(p *importPlan) []*importPlan {
+		switch state[p] {
+		case done:
+			return nil
+		case visiting:
+			for i, q := range stack {
+				if q == p {
+					return append(append([]*importPlan{}, stack[i:]...), p)
+				}
+			}
+		}
+
+		state[p] = visiting
+		stack = append(stack, p)
+		for _, key := range p.fileDeps {
+			if cycle := visit(byKey[key]); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[p] = done
+		ordered = append(ordered, p)
+		return nil
+	}
+
+	for _, p := range plans {
+		if cycle := visit(p); cycle != nil {
+			return nil, cycle
+		}
+	}
+	return ordered, nil
+}
+
+func importCycleKeys(cycle []*importPlan) []string {
+	keys := make([]string, len(cycle))
+	for i, p := range cycle {
+		keys[i] = p.req.Key
+	}
+	return keys
+}
+
+func importValidationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return "is required unless rule is set"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	}
+	return "is invalid (" + fe.Tag() + ")"
+}
+
+// importPrerequisiteLabels prasyarat sebagai key misi (atau id untuk misi tanpa key), terurut
+func importPrerequisiteLabels(ids []int64, pendingKeys []string, labels map[int64]string) []string {
+	out := append([]string{}, pendingKeys...)
+	for _, id := range ids {
+		if key, ok := labels[id]; ok {
+			out = append(out, key)
+		} else {
+			out = append(out, strconv.FormatInt(id, 10))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// importTime waktu dibandingkan tanpa zona, sesuai kolom TIMESTAMP di database
+func importTime(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.Format("2006-01-02 15:04:05")
+}
+
+func importFloat(f sql.NullFloat64) any {
+	if !f.Valid {
+		return nil
+	}
+	return f.Float64
+}
+
+func importInt(i sql.NullInt64) any {
+	if !i.Valid {
+		return nil
+	}
+	return i.Int64
+}
@@ -0,0 +1,114 @@
+// service/mission_proof_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+var (
+	ErrMissionNotProof    = errors.New("mission does not accept proof submissions")
+	ErrInvalidProofStatus = errors.New("status must be pending, approved or rejected")
+	ErrRejectNoteRequired = errors.New("a note is required when rejecting proof")
+)
+
+// SubmitProof mengirim bukti foto ke antrean moderasi
+func (s *missionService) SubmitProof(ctx context.Context, userID, missionID int64, photoURL, note string) (*dto.ProofSubmissionDTO, error) {
+	mission, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, ErrMissionNotFound
+	}
+	if mission.MissionType != model.MissionTypeProof {
+		return nil, ErrMissionNotProof
+	}
+
+	proof, err := s.checkRepo.SubmitProof(ctx, userID, mission, photoURL, strings.TrimSpace(note), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return proofToDTO(proof), nil
+}
+
+// GetMyProofs bukti yang pernah dikirim user untuk satu misi
+func (s *missionService) GetMyProofs(ctx context.Context, userID, missionID int64) ([]*dto.ProofSubmissionDTO, error) {
+	proofs, err := s.checkRepo.FindUserProofs(ctx, userID, missionID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ProofSubmissionDTO, 0, len(proofs))
+	for _, proof := range proofs {
+		res = append(res, proofToDTO(proof))
+	}
+	return res, nil
+}
+
+// GetProofQueue antrean moderasi untuk admin, default hanya yang masih pending
+func (s *missionService) GetProofQueue(ctx context.Context, status string, page, limit int) ([]*dto.ProofSubmissionDTO, error) {
+	proofStatus := model.ProofStatus(status)
+	switch proofStatus {
+	case "":
+		proofStatus = model.ProofPending
+	case "all":
+		proofStatus = ""
+	case model.ProofPending, model.ProofApproved, model.ProofRejected:
+	default:
+		return nil, ErrInvalidProofStatus
+	}
+
+	proofs, err := s.checkRepo.FindProofQueue(ctx, proofStatus, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ProofSubmissionDTO, 0, len(proofs))
+	for _, proof := range proofs {
+		res = append(res, proofToDTO(proof))
+	}
+	return res, nil
+}
+
+// ReviewProof menyetujui atau menolak bukti; persetujuan menyelesaikan misi lewat jalur hadiah biasa
+func (s *missionService) ReviewProof(ctx context.Context, reviewerID, proofID int64, approve bool, req *dto.ReviewProofDTO) (*dto.ProofReviewResponseDTO, error) {
+	note := strings.TrimSpace(req.Note)
+	if !approve && note == "" {
+		return nil, ErrRejectNoteRequired
+	}
+
+	proof, completed, err := s.checkRepo.ReviewProof(ctx, proofID, reviewerID, approve, note, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ProofReviewResponseDTO{
+		Proof:            *proofToDTO(proof),
+		MissionCompleted: completed,
+	}, nil
+}
+
+func proofToDTO(proof *model.MissionProofSubmission) *dto.ProofSubmissionDTO {
+	res := &dto.ProofSubmissionDTO{
+		ID:           proof.ID,
+		MissionID:    proof.MissionID,
+		MissionTitle: proof.MissionTitle,
+		UserID:       proof.UserID,
+		Username:     proof.Username,
+		PhotoURL:     proof.PhotoURL,
+		Note:         proof.Note,
+		Status:       string(proof.Status),
+		ReviewNote:   proof.ReviewNote,
+		CreatedAt:    proof.CreatedAt,
+	}
+	if proof.ReviewedAt.Valid {
+		reviewedAt := proof.ReviewedAt.Time
+		res.ReviewedAt = &reviewedAt
+	}
+	return res
+}
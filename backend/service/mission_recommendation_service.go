@@ -0,0 +1,240 @@
+// service/mission_recommendation_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Qodarrz/fiber-app/category"
+	dto "github.com/Qodarrz/fiber-app/dto"
+	model "github.com/Qodarrz/fiber-app/model"
+)
+
+// paceLookback rentang aktivitas terakhir untuk memperkirakan kecepatan user
+const paceLookback = 30 * 24 * time.Hour
+
+// recommendationInput sinyal per misi yang dipakai untuk skor dan alasan
+type recommendationInput struct {
+	mission       *model.Mission
+	joined        bool
+	progress      float64
+	pacePerDay    float64
+	daysLeft      *float64
+	owned         bool
+	ownable       bool
+	similarDone   int
+	estimatedDays *float64
+}
+
+// GetRecommendedMissions mengurutkan misi aktif yang masih bisa dikerjakan user berdasarkan
+// kendaraan/perangkatnya, progres saat ini, riwayat penyelesaian dan perkiraan hari selesai
+func (s *missionService) GetRecommendedMissions(ctx context.Context, userID int64, limit int) ([]*dto.RecommendedMissionDTO, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 20 {
+		limit = 20
+	}
+
+	missions, err := s.missionRepo.FindActiveMissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachPrerequisites(ctx, missions); err != nil {
+		return nil, err
+	}
+	if err := s.localizeMissions(ctx, missions); err != nil {
+		return nil, err
+	}
+
+	userMissions, err := s.userMissionRepo.FindUserMissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[int64]*model.UserMission, len(userMissions))
+	completed := make(map[int64]bool, len(userMissions))
+	for _, um := range userMissions {
+		states[um.MissionID] = um
+		completed[um.MissionID] = um.CompletedAt.Valid
+	}
+
+	profile, err := s.checkRepo.FindRecommendationProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var inputs []*recommendationInput
+	for _, mission := range missions {
+		if !mission.PrerequisitesMet(completed) {
+			continue
+		}
+
+		in := &recommendationInput{
+			mission:     mission,
+			similarDone: profile.CompletedByType[mission.MissionType],
+		}
+
+		if um, ok := states[mission.ID]; ok {
+			if um.Status == model.UserMissionActive {
+				if doneForPeriod(mission, um, now) {
+					continue
+				}
+				in.joined = true
+				if in.progress, err = s.checkRepo.GetMissionProgress(ctx, userID, mission.ID); err != nil {
+					return nil, err
+				}
+			} else if !um.Status.CanTransition(model.UserMissionActive) {
+				// Sudah selesai, gagal atau kedaluwarsa
+				continue
+			}
+		}
+
+		if cat, ok := category.ForCriteria(mission.CriteriaType); ok {
+			in.owned = profile.OwnedCriteria[mission.CriteriaType]
+			in.ownable = cat.Name() == category.Vehicle || cat.Name() == category.Electronics
+		}
+
+		in.daysLeft = missionDaysLeft(mission, now)
+		if estimable(mission) {
+			recent, err := s.checkRepo.RecentProgress(ctx, userID, mission, now.Add(-paceLookback), now)
+			if err != nil {
+				return nil, err
+			}
+			in.pacePerDay = recent / paceLookback.Hours() * 24
+			if mission.MissionType == model.MissionTypeStreak && recent > 0 {
+				// Streak yang masih berjalan bertambah satu per hari
+				in.pacePerDay = 1
+			}
+			if in.pacePerDay > 0 {
+				days := math.Max(0, mission.TargetValue-in.progress) / in.pacePerDay
+				in.estimatedDays = &days
+			}
+		}
+
+		inputs = append(inputs, in)
+	}
+
+	res := make([]*dto.RecommendedMissionDTO, 0, len(inputs))
+	for _, in := range inputs {
+		score, reason := scoreRecommendation(in)
+		item := &dto.RecommendedMissionDTO{
+			Mission:       *s.missionToDTO(in.mission),
+			Score:         math.Round(score*10) / 10,
+			Reason:        reason,
+			Joined:        in.joined,
+			Progress:      in.progress,
+			EstimatedDays: in.estimatedDays,
+		}
+		if in.mission.TargetValue > 0 {
+			item.ProgressPercent = math.Min(100, in.progress/in.mission.TargetValue*100)
+		}
+		res = append(res, item)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Mission.ID < res[j].Mission.ID
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// doneForPeriod misi sekali jalan selesai selamanya, misi berulang hanya untuk periode berjalan
+func doneForPeriod(mission *model.Mission, um *model.UserMission, now time.Time) bool {
+	if !um.CompletedAt.Valid {
+		return false
+	}
+	period, ok := mission.Period(now)
+	return !ok || !um.CompletedAt.Time.Before(period.Start)
+}
+
+// missionDaysLeft sisa hari sampai misi (atau periode berjalan) ditutup, nil kalau tanpa batas
+func missionDaysLeft(mission *model.Mission, now time.Time) *float64 {
+	var end *time.Time
+	if mission.ExpiredAt.Valid {
+		end = &mission.ExpiredAt.Time
+	}
+	if period, ok := mission.Period(now); ok && (end == nil || period.End.Before(*end)) {
+		end = &period.End
+	}
+	if end == nil {
+		return nil
+	}
+	days := math.Max(0, end.Sub(now).Hours()/24)
+	return &days
+}
+
+// estimable kecepatan hanya berarti untuk misi "capai target" individual yang progresnya
+// bertambah seiring aktivitas
+func estimable(mission *model.Mission) bool {
+	return !mission.SettlesAtWindowEnd() && !mission.IsCollective() &&
+		mission.MissionType != model.MissionTypeProof && mission.MissionType != model.MissionTypeQuiz &&
+		mission.CriteriaType != model.CriteriaBaselineReduction &&
+		mission.TargetValue > 0
+}
+
+// scoreRecommendation skor 0-100: relevansi (25), progres (30), perkiraan waktu (20),
+// riwayat misi sejenis (10), sudah diikuti (5). Alasan diambil dari sinyal terkuat.
+func scoreRecommendation(in *recommendationInput) (float64, string) {
+	var score float64
+
+	switch {
+	case in.owned:
+		score += 25
+	case !in.ownable:
+		score += 10
+	}
+
+	var percent float64
+	if !in.mission.SettlesAtWindowEnd() && in.mission.TargetValue > 0 {
+		percent = math.Min(1, in.progress/in.mission.TargetValue)
+		score += 30 * percent
+	}
+
+	feasible := in.estimatedDays != nil && (in.daysLeft == nil || *in.estimatedDays <= *in.daysLeft)
+	switch {
+	case feasible:
+		score += 20 / (1 + *in.estimatedDays/7)
+	case in.estimatedDays != nil:
+		// Dengan kecepatan sekarang tidak akan selesai sebelum ditutup
+		score -= 10
+	case in.mission.SettlesAtWindowEnd():
+		score += 10
+	}
+
+	score += math.Min(10, float64(3*in.similarDone))
+	if in.joined {
+		score += 5
+	}
+
+	switch {
+	case percent >= 0.5 && percent < 1:
+		return score, fmt.Sprintf("You're already %.0f%% of the way there", percent*100)
+	case feasible && *in.estimatedDays <= 1:
+		return score, "At your current pace you could finish within a day"
+	case feasible && *in.estimatedDays <= 14:
+		return score, fmt.Sprintf("At your current pace you could finish in about %.0f days", math.Ceil(*in.estimatedDays))
+	case in.owned:
+		return score, fmt.Sprintf("Fits the %s activity you already track", strings.ReplaceAll(string(in.mission.CriteriaType), "_", " "))
+	case in.similarDone == 1:
+		return score, "You've completed a similar mission before"
+	case in.similarDone > 1:
+		return score, fmt.Sprintf("You've completed %d similar missions", in.similarDone)
+	case in.mission.SettlesAtWindowEnd():
+		return score, "Keep your activity in range until the window closes"
+	case in.mission.MissionType == model.MissionTypeProof:
+		return score, "Take a real-world eco action and share a photo"
+	case in.mission.MissionType == model.MissionTypeQuiz:
+		return score, "Learn something new and pass a short quiz"
+	}
+	return score, "A new challenge to try"
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	"github.com/Qodarrz/fiber-app/repository"
+)
+
+const (
+	// schedulerLockName nama advisory lock; hanya satu instance yang menjalankan job
+	schedulerLockName = "mission_scheduler"
+	// reconcileInterval jeda rekonsiliasi penuh seluruh misi aktif
+	reconcileInterval = 6 * time.Hour
+	// expiryReminderWindow user diingatkan kalau misinya berakhir dalam rentang ini
+	expiryReminderWindow = 24 * time.Hour
+	// jobDueSlack toleransi supaya tick yang sedikit lebih cepat tetap menjalankan job
+	jobDueSlack = 30 * time.Second
+)
+
+// Nama job scheduler misi, dipakai juga sebagai filter riwayat run
+const (
+	jobRolloverRecurring   = "rollover_recurring"
+	jobSettleClosedWindows = "settle_closed_windows"
+	jobExpireEnrollments   = "expire_enrollments"
+	jobNotifyExpiring      = "notify_expiring"
+	jobReconcileMissions   = "reconcile_missions"
+)
+
+var ErrUnknownSchedulerJob = errors.New("unknown scheduler job")
+
+// schedulerJob satu job berkala; urutan di daftar job menentukan urutan eksekusi
+type schedulerJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context, now time.Time) (int64, error)
+}
+
+// StartMissionScheduler menjalankan rollover periode misi berulang, menilai misi
+// "di bawah target" yang window-nya ditutup, menutup misi user yang sudah lewat
+// expired_at, mengingatkan misi yang segera berakhir dan rekonsiliasi penuh secara berkala.
+// Beberapa instance boleh berjalan bersamaan: tiap tick hanya pemegang advisory lock yang
+// menjalankan job, dan job yang sudah sukses dalam interval-nya dilewati. Riwayat run
+// disimpan di mission_job_runs.
+// Hanya untuk server yang berjalan terus (main.go); di serverless rollover terjadi
+// saat misi dievaluasi.
+func StartMissionScheduler(ctx context.Context, missionRepo repository.CheckMissionRepositoryInterface, schedulerRepo repository.SchedulerRepositoryInterface, interval time.Duration) {
+	jobs := missionJobs(missionRepo, interval)
+	instance := schedulerInstance()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runDueJobs(ctx, schedulerRepo, jobs, instance)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func missionJobs(missionRepo repository.CheckMissionRepositoryInterface, interval time.Duration) []schedulerJob {
+	return []schedulerJob{
+		{
+			name:     jobRolloverRecurring,
+			interval: interval,
+			run:      missionRepo.RollOverRecurringMissions,
+		},
+		{
+			// Nilai misi lte/between dulu sebelum yang lewat expired_at ditutup
+			name:     jobSettleClosedWindows,
+			interval: interval,
+			run: func(ctx context.Context, now time.Time) (int64, error) {
+				return missionRepo.SettleClosedWindows(ctx, now, nil)
+			},
+		},
+		{
+			name:     jobExpireEnrollments,
+			interval: interval,
+			run: func(ctx context.Context, now time.Time) (int64, error) {
+				return missionRepo.ExpireUserMissions(ctx, now, nil)
+			},
+		},
+		{
+			name:     jobNotifyExpiring,
+			interval: interval,
+			run: func(ctx context.Context, now time.Time) (int64, error) {
+				return missionRepo.NotifyExpiringMissions(ctx, now, expiryReminderWindow)
+			},
+		},
+		{
+			name:     jobReconcileMissions,
+			interval: reconcileInterval,
+			run: func(ctx context.Context, now time.Time) (int64, error) {
+				return reconcileMissions(ctx, missionRepo)
+			},
+		},
+	}
+}
+
+// runDueJobs menjalankan job yang sudah jatuh tempo kalau instance ini memegang lock
+func runDueJobs(ctx context.Context, schedulerRepo repository.SchedulerRepositoryInterface, jobs []schedulerJob, instance string) {
+	release, ok, err := schedulerRepo.TryLeaderLock(ctx, schedulerLockName)
+	if err != nil {
+		log.Printf("Gagal mengambil lock scheduler misi: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer release()
+
+	for _, job := range jobs {
+		now := time.Now()
+
+		last, err := schedulerRepo.LastSucceededAt(ctx, job.name)
+		if err != nil {
+			log.Printf("Gagal membaca riwayat job %s: %v", job.name, err)
+			continue
+		}
+		if last.Valid && now.Sub(last.Time)+jobDueSlack < job.interval {
+			continue
+		}
+
+		runID, err := schedulerRepo.StartJobRun(ctx, job.name, instance, now)
+		if err != nil {
+			log.Printf("Gagal mencatat job %s: %v", job.name, err)
+			continue
+		}
+
+		affected, runErr := job.run(ctx, now)
+		if runErr != nil {
+			log.Printf("Job %s gagal: %v", job.name, runErr)
+		} else if affected > 0 {
+			log.Printf("Job %s: %d baris diproses", job.name, affected)
+		}
+
+		if err := schedulerRepo.FinishJobRun(ctx, runID, affected, runErr, time.Now()); err != nil {
+			log.Printf("Gagal menyimpan hasil job %s: %v", job.name, err)
+		}
+	}
+}
+
+// reconcileMissions menghitung ulang seluruh misi aktif tiap user dari data mentah,
+// memperbaiki progres yang terlewat oleh evaluasi berbasis event
+func reconcileMissions(ctx context.Context, missionRepo repository.CheckMissionRepositoryInterface) (int64, error) {
+	userIDs, err := missionRepo.FindUsersWithActiveMissions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var reconciled, failed int64
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return reconciled, ctx.Err()
+		}
+		if err := missionRepo.CheckAllUserMissions(ctx, userID); err != nil {
+			log.Printf("Gagal rekonsiliasi misi user %d: %v", userID, err)
+			failed++
+			continue
+		}
+		reconciled++
+	}
+
+	if failed > 0 {
+		return reconciled, fmt.Errorf("%d of %d users failed to reconcile", failed, len(userIDs))
+	}
+	return reconciled, nil
+}
+
+func schedulerInstance() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// GetJobRuns riwayat run scheduler untuk admin; job kosong berarti semua job
+func (s *missionService) GetJobRuns(ctx context.Context, job string, limit int) ([]*dto.MissionJobRunDTO, error) {
+	if job != "" && !isSchedulerJob(job) {
+		return nil, ErrUnknownSchedulerJob
+	}
+
+	runs, err := s.schedulerRepo.FindJobRuns(ctx, job, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.MissionJobRunDTO, 0, len(runs))
+	for _, run := range runs {
+		item := &dto.MissionJobRunDTO{
+			ID:        run.ID,
+			Job:       run.Job,
+			Instance:  run.Instance,
+			Status:    string(run.Status),
+			Affected:  run.Affected,
+			Error:     run.Error.String,
+			StartedAt: run.StartedAt,
+		}
+		if run.FinishedAt.Valid {
+			finishedAt := run.FinishedAt.Time
+			item.FinishedAt = &finishedAt
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func isSchedulerJob(name string) bool {
+	switch name {
+	case jobRolloverRecurring, jobSettleClosedWindows, jobExpireEnrollments, jobNotifyExpiring, jobReconcileMissions:
+		return true
+	}
+	return false
+}
@@ -3,13 +3,23 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"os"
+	"strconv"
 	"time"
 
 	dto "github.com/Qodarrz/fiber-app/dto"
 	model "github.com/Qodarrz/fiber-app/model"
 	"github.com/Qodarrz/fiber-app/repository"
+	"github.com/Qodarrz/fiber-app/rule"
+)
+
+var (
+	ErrMissionNotFound      = errors.New("mission not found")
+	ErrMissionNotCollective = errors.New("mission is not a team or community mission")
 )
 
 type MissionServiceInterface interface {
@@ -17,15 +27,37 @@ type MissionServiceInterface interface {
 	CreateMissionWithBadge(ctx context.Context, req *dto.CreateMissionWithBadgeDTO) (*dto.MissionWithBadgeResponseDTO, error)
 	GetMissionByID(ctx context.Context, id int64) (*dto.MissionResponseDTO, error)
 	GetAllMissions(ctx context.Context, page, limit int) ([]*dto.MissionResponseDTO, error)
-	GetActiveMissions(ctx context.Context) ([]*dto.MissionResponseDTO, error)
+	GetActiveMissions(ctx context.Context, userID *int64) ([]*dto.MissionResponseDTO, error)
 	GetUserMissions(ctx context.Context, userID int64) ([]*dto.UserMissionResponseDTO, error)
 	CheckMissionCompletion(ctx context.Context, userID, missionID int64) (bool, error)
+	GetAvailableMissions(ctx context.Context, userID int64) ([]*dto.MissionResponseDTO, error)
+	JoinMission(ctx context.Context, userID, missionID int64) (*dto.UserMissionResponseDTO, error)
+	LeaveMission(ctx context.Context, userID, missionID int64) error
+	GetCollectiveProgress(ctx context.Context, userID, missionID int64) ([]*dto.CollectiveProgressDTO, error)
+	GetProgressTimeline(ctx context.Context, userID, missionID int64, limit int) (*dto.MissionTimelineDTO, error)
+	SubmitProof(ctx context.Context, userID, missionID int64, photoURL, note string) (*dto.ProofSubmissionDTO, error)
+	GetMyProofs(ctx context.Context, userID, missionID int64) ([]*dto.ProofSubmissionDTO, error)
+	GetProofQueue(ctx context.Context, status string, page, limit int) ([]*dto.ProofSubmissionDTO, error)
+	ReviewProof(ctx context.Context, reviewerID, proofID int64, approve bool, req *dto.ReviewProofDTO) (*dto.ProofReviewResponseDTO, error)
+	GetRecommendedMissions(ctx context.Context, userID int64, limit int) ([]*dto.RecommendedMissionDTO, error)
+	DryRunMission(ctx context.Context, req *dto.DryRunMissionDTO) (*dto.MissionDryRunResultDTO, error)
+	UpdateMission(ctx context.Context, missionID int64, req *dto.UpdateMissionDTO) (*dto.UpdateMissionResponseDTO, error)
+	ArchiveMission(ctx context.Context, missionID int64) error
+	RestoreMission(ctx context.Context, missionID int64) error
+	DeleteMission(ctx context.Context, missionID int64) error
+	DuplicateMission(ctx context.Context, missionID int64, req *dto.DuplicateMissionDTO) (*dto.MissionResponseDTO, error)
+	GetArchivedMissions(ctx context.Context, page, limit int) ([]*dto.MissionResponseDTO, error)
+	ImportMissions(ctx context.Context, format string, data []byte, dryRun bool) (*dto.MissionImportResultDTO, error)
+	GetJobRuns(ctx context.Context, job string, limit int) ([]*dto.MissionJobRunDTO, error)
 }
 
 type missionService struct {
 	missionRepo     repository.MissionRepositoryInterface
 	userMissionRepo repository.MissionRepositoryInterface
 	badgeRepo       repository.BadgeRepositoryInterface
+	checkRepo       repository.CheckMissionRepositoryInterface
+	translationRepo repository.TranslationRepositoryInterface
+	schedulerRepo   repository.SchedulerRepositoryInterface
 }
 
 
@@ -33,11 +65,17 @@ func NewMissionService(
 	missionRepo repository.MissionRepositoryInterface,
 	userMissionRepo repository.MissionRepositoryInterface,
 	badgeRepo repository.BadgeRepositoryInterface,
+	checkRepo repository.CheckMissionRepositoryInterface,
+	translationRepo repository.TranslationRepositoryInterface,
+	schedulerRepo repository.SchedulerRepositoryInterface,
 ) MissionServiceInterface {
 	return &missionService{
 		missionRepo:     missionRepo,
 		userMissionRepo: userMissionRepo,
 		badgeRepo:       badgeRepo,
+		checkRepo:       checkRepo,
+		translationRepo: translationRepo,
+		schedulerRepo:   schedulerRepo,
 	}		
 }
 
@@ -52,49 +90,23 @@ func (s *missionService) CreateMissionWithBadge(ctx context.Context, req *dto.Cr
 		}
 	}
 
-	var badgeID *int64
-	var badgeResponse *dto.BadgeResponseDTO
+	// Definisi misi divalidasi dulu supaya request yang ditolak tidak meninggalkan badge
+	mission, err := buildMission(missionRequestFromBadgeDTO(req))
+	if err != nil {
+		return nil, err
+	}
 
-	// Jika mission memberikan badge, buat badge terlebih dahulu
+	var badge *model.Badge
 	if req.GivesBadge {
-		// Buat badge
-		badge := &model.Badge{
-			Name:           req.BadgeName,
-			ImageURL:       req.BadgeImageURL,
-			Description:    req.BadgeDescription,
-			CreatedAt:      time.Now(),
-		}
-
-		err := s.badgeRepo.Create(ctx, badge)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create badge: %w", err)
+		badge = &model.Badge{
+			Name:        req.BadgeName,
+			ImageURL:    req.BadgeImageURL,
+			Description: req.BadgeDescription,
+			CreatedAt:   time.Now(),
 		}
-
-		badgeID = &badge.ID
-		badgeResponse = s.badgeToDTO(badge)
-	}
-
-	mission := &model.Mission{
-		Title:        req.Title,
-		Description:  req.Description,
-		MissionType:  model.MissionType(req.MissionType),
-		CriteriaType: model.MissionCriteriaType(req.CriteriaType),
-		PointsReward: req.PointsReward,
-		GivesBadge:   req.GivesBadge,
-		TargetValue:  req.TargetValue,
-		CreatedAt:    time.Now(),
 	}
 
-	if badgeID != nil {
-		mission.BadgeID = model.NewNullInt64(*badgeID)
-	}
-
-	if req.ExpiredAt != nil {
-		mission.ExpiredAt = model.NewNullTime(*req.ExpiredAt)
-	}
-
-	err := s.missionRepo.Create(ctx, mission)
-	if err != nil {
+	if err := s.missionRepo.CreateWithBadge(ctx, mission, badge); err != nil {
 		return nil, fmt.Errorf("failed to create mission: %w", err)
 	}
 
@@ -102,13 +114,44 @@ func (s *missionService) CreateMissionWithBadge(ctx context.Context, req *dto.Cr
 		Mission: *s.missionToDTO(mission),
 	}
 
-	if badgeResponse != nil {
-		response.Badge = *badgeResponse
+	if badge != nil {
+		response.Badge = *s.badgeToDTO(badge)
 	}
 
 	return response, nil
 }
 
+// missionRequestFromBadgeDTO bagian misi dari request misi + badge baru
+func missionRequestFromBadgeDTO(req *dto.CreateMissionWithBadgeDTO) *dto.CreateMissionDTO {
+	missionReq := &dto.CreateMissionDTO{
+		Title:            req.Title,
+		Description:      req.Description,
+		MissionType:      req.MissionType,
+		PointsReward:     req.PointsReward,
+		GivesBadge:       req.GivesBadge,
+		TargetValue:      req.TargetValue,
+		ExpiredAt:        req.ExpiredAt,
+		StartsAt:         req.StartsAt,
+		ProgressWindow:   req.ProgressWindow,
+		Recurrence:       req.Recurrence,
+		RecurrenceRule:   req.RecurrenceRule,
+		AutoJoin:         req.AutoJoin,
+		Prerequisites:    req.Prerequisites,
+		PrerequisiteMode: req.PrerequisiteMode,
+		Scope:            req.Scope,
+		MinContribution:  req.MinContribution,
+		Comparator:       req.Comparator,
+		TargetMax:        req.TargetMax,
+		MinActivityLogs:  req.MinActivityLogs,
+		Rule:             req.Rule,
+	}
+	if req.CriteriaType != "" {
+		criteria := req.CriteriaType
+		missionReq.CriteriaType = &criteria
+	}
+	return missionReq
+}
+
 // Tambahkan helper function untuk badge
 func (s *missionService) badgeToDTO(badge *model.Badge) *dto.BadgeResponseDTO {
 	return &dto.BadgeResponseDTO{
@@ -121,7 +164,21 @@ func (s *missionService) badgeToDTO(badge *model.Badge) *dto.BadgeResponseDTO {
 }
 
 func (s *missionService) CreateMission(ctx context.Context, req *dto.CreateMissionDTO) (*dto.MissionResponseDTO, error) {
+	mission, err := buildMission(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.missionRepo.Create(ctx, mission)
+	if err != nil {
+		return nil, err
+	}
 
+	return s.missionToDTO(mission), nil
+}
+
+// buildMission memvalidasi definisi misi dari request tanpa menyimpannya
+func buildMission(req *dto.CreateMissionDTO) (*model.Mission, error) {
 	mission := &model.Mission{
 		Title:        req.Title,
 		Description:  req.Description,
@@ -141,16 +198,47 @@ func (s *missionService) CreateMission(ctx context.Context, req *dto.CreateMissi
 		mission.CarbonReductionG = model.NewNullFloat64(*req.CarbonReductionG)
 	}
 
+	if req.CriteriaType != nil {
+		mission.CriteriaType = model.MissionCriteriaType(*req.CriteriaType)
+	}
+
 	if req.ExpiredAt != nil {
 		mission.ExpiredAt = model.NewNullTime(*req.ExpiredAt)
 	}
 
-	err := s.missionRepo.Create(ctx, mission)
-	if err != nil {
+	if err := applyMissionSchedule(mission, req.StartsAt, req.ProgressWindow); err != nil {
 		return nil, err
 	}
 
-	return s.missionToDTO(mission), nil
+	if err := applyMissionRecurrence(mission, req.Recurrence, req.RecurrenceRule); err != nil {
+		return nil, err
+	}
+
+	if err := applyMissionScope(mission, req.Scope, req.MinContribution); err != nil {
+		return nil, err
+	}
+
+	comparator, targetMax := req.Comparator, req.TargetMax
+	if len(req.Rule) > 0 {
+		missionRule, err := applyMissionRule(mission, req.Rule)
+		if err != nil {
+			return nil, err
+		}
+		comparator, targetMax = string(missionRule.Comparator), missionRule.TargetMax
+	}
+
+	if err := applyMissionComparator(mission, comparator, targetMax, req.MinActivityLogs); err != nil {
+		return nil, err
+	}
+
+	mission.AutoJoin = req.AutoJoin == nil || *req.AutoJoin
+	mission.Prerequisites = req.Prerequisites
+	mission.PrerequisiteMode = model.PrerequisiteAll
+	if req.PrerequisiteMode != "" {
+		mission.PrerequisiteMode = model.PrerequisiteMode(req.PrerequisiteMode)
+	}
+
+	return mission, nil
 }
 
 func (s *missionService) GetMissionByID(ctx context.Context, id int64) (*dto.MissionResponseDTO, error) {
@@ -162,6 +250,13 @@ func (s *missionService) GetMissionByID(ctx context.Context, id int64) (*dto.Mis
 		return nil, errors.New("mission not found")
 	}
 
+	if err := s.attachPrerequisites(ctx, []*model.Mission{mission}); err != nil {
+		return nil, err
+	}
+	if err := s.localizeMissions(ctx, []*model.Mission{mission}); err != nil {
+		return nil, err
+	}
+
 	return s.missionToDTO(mission), nil
 }
 
@@ -177,6 +272,9 @@ func (s *missionService) GetAllMissions(ctx context.Context, page, limit int) ([
 	if err != nil {
 		return nil, err
 	}
+	if err := s.localizeMissions(ctx, missions); err != nil {
+		return nil, err
+	}
 
 	var result []*dto.MissionResponseDTO
 	for _, mission := range missions {
@@ -186,26 +284,76 @@ func (s *missionService) GetAllMissions(ctx context.Context, page, limit int) ([
 	return result, nil
 }
 
-func (s *missionService) GetActiveMissions(ctx context.Context) ([]*dto.MissionResponseDTO, error) {
+// GetActiveMissions misi aktif; kalau userID ada, misi yang prasyaratnya belum
+// terpenuhi ditandai locked
+func (s *missionService) GetActiveMissions(ctx context.Context, userID *int64) ([]*dto.MissionResponseDTO, error) {
 	missions, err := s.missionRepo.FindActiveMissions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.attachPrerequisites(ctx, missions); err != nil {
+		return nil, err
+	}
+	if err := s.localizeMissions(ctx, missions); err != nil {
+		return nil, err
+	}
+
+	var completed map[int64]bool
+	if userID != nil {
+		if completed, err = s.missionRepo.FindCompletedMissionIDs(ctx, *userID); err != nil {
+			return nil, err
+		}
+	}
+
 	var result []*dto.MissionResponseDTO
 	for _, mission := range missions {
-		result = append(result, s.missionToDTO(mission))
+		missionDTO := s.missionToDTO(mission)
+		missionDTO.Locked = userID != nil && !mission.PrerequisitesMet(completed)
+		result = append(result, missionDTO)
 	}
 
 	return result, nil
 }
 
+// localizeMissions mengganti judul dan deskripsi misi dengan terjemahan locale request
+func (s *missionService) localizeMissions(ctx context.Context, missions []*model.Mission) error {
+	ids := make([]int64, len(missions))
+	for i, mission := range missions {
+		ids[i] = mission.ID
+	}
+	return localize(ctx, s.translationRepo, model.TranslationMission, ids, func(i int, tr *model.Translation) {
+		missions[i].Title = tr.Name
+		missions[i].Description = localizedText(missions[i].Description, tr.Description)
+	})
+}
+
+func (s *missionService) attachPrerequisites(ctx context.Context, missions []*model.Mission) error {
+	prerequisites, err := s.missionRepo.FindAllPrerequisites(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mission := range missions {
+		mission.Prerequisites = prerequisites[mission.ID]
+	}
+	return nil
+}
+
 func (s *missionService) GetUserMissions(ctx context.Context, userID int64) ([]*dto.UserMissionResponseDTO, error) {
+	// Hanya membaca; misi yang lewat deadline dinilai dan ditutup oleh scheduler misi
 	userMissions, err := s.userMissionRepo.FindUserMissions(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	missions := make([]*model.Mission, 0, len(userMissions))
+	for _, userMission := range userMissions {
+		missions = append(missions, userMission.Mission)
+	}
+	if err := s.localizeMissions(ctx, missions); err != nil {
+		return nil, err
+	}
+
 	var result []*dto.UserMissionResponseDTO
 	for _, userMission := range userMissions {
 		missionDTO := s.missionToDTO(userMission.Mission)
@@ -216,11 +364,15 @@ func (s *missionService) GetUserMissions(ctx context.Context, userID int64) ([]*
 		}
 
 		result = append(result, &dto.UserMissionResponseDTO{
-			ID:          userMission.ID,
-			UserID:      userMission.UserID,
-			Mission:     *missionDTO,
-			CompletedAt: completedAt,
-			CreatedAt:   userMission.CreatedAt,
+			ID:              userMission.ID,
+			UserID:          userMission.UserID,
+			Mission:         *missionDTO,
+			Status:          string(userMission.Status),
+			JoinedVia:       string(userMission.JoinedVia),
+			StatusChangedAt: userMission.StatusChangedAt,
+			EnrolledAt:      userMission.EnrolledAt,
+			CompletedAt:     completedAt,
+			CreatedAt:       userMission.CreatedAt,
 		})
 	}
 
@@ -236,16 +388,362 @@ func (s *missionService) CheckMissionCompletion(ctx context.Context, userID, mis
 	return userMission != nil && userMission.CompletedAt.Valid, nil
 }
 
+// GetAvailableMissions misi aktif yang belum diikuti user (atau pernah ditinggalkan)
+func (s *missionService) GetAvailableMissions(ctx context.Context, userID int64) ([]*dto.MissionResponseDTO, error) {
+	missions, err := s.missionRepo.FindActiveMissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userMissions, err := s.userMissionRepo.FindUserMissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachPrerequisites(ctx, missions); err != nil {
+		return nil, err
+	}
+	if err := s.localizeMissions(ctx, missions); err != nil {
+		return nil, err
+	}
+
+	joined := make(map[int64]bool, len(userMissions))
+	completed := make(map[int64]bool, len(userMissions))
+	for _, um := range userMissions {
+		joined[um.MissionID] = !um.Status.CanTransition(model.UserMissionActive)
+		completed[um.MissionID] = um.CompletedAt.Valid
+	}
+
+	result := []*dto.MissionResponseDTO{}
+	for _, mission := range missions {
+		if !joined[mission.ID] {
+			missionDTO := s.missionToDTO(mission)
+			missionDTO.Locked = !mission.PrerequisitesMet(completed)
+			result = append(result, missionDTO)
+		}
+	}
+	return result, nil
+}
+
+func (s *missionService) JoinMission(ctx context.Context, userID, missionID int64) (*dto.UserMissionResponseDTO, error) {
+	mission, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, ErrMissionNotFound
+	}
+
+	now := time.Now()
+	if mission.ArchivedAt.Valid || (mission.ExpiredAt.Valid && !mission.ExpiredAt.Time.After(now)) || (mission.StartsAt.Valid && mission.StartsAt.Time.After(now)) {
+		return nil, repository.ErrMissionClosed
+	}
+
+	userMission, err := s.checkRepo.JoinMission(ctx, userID, missionID, maxActiveMissions(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Progres awal dihitung langsung supaya aktivitas hari ini ikut terhitung
+	if _, err := s.checkRepo.CheckMission(ctx, userID, mission); err != nil {
+		fmt.Printf("Gagal evaluasi mission %d setelah join: %v\n", missionID, err)
+	}
+
+	return &dto.UserMissionResponseDTO{
+		ID:              userMission.ID,
+		UserID:          userID,
+		Mission:         *s.missionToDTO(mission),
+		Status:          string(userMission.Status),
+		JoinedVia:       string(userMission.JoinedVia),
+		StatusChangedAt: userMission.StatusChangedAt,
+		EnrolledAt:      userMission.EnrolledAt,
+		CreatedAt:       userMission.CreatedAt,
+	}, nil
+}
+
+func (s *missionService) LeaveMission(ctx context.Context, userID, missionID int64) error {
+	return s.checkRepo.LeaveMission(ctx, userID, missionID, time.Now())
+}
+
+// GetCollectiveProgress progress bar bersama untuk setiap grup user (komunitas atau tim-timnya)
+func (s *missionService) GetCollectiveProgress(ctx context.Context, userID, missionID int64) ([]*dto.CollectiveProgressDTO, error) {
+	mission, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, ErrMissionNotFound
+	}
+	if !mission.IsCollective() {
+		return nil, ErrMissionNotCollective
+	}
+
+	standings, err := s.checkRepo.FindCollectiveStandings(ctx, userID, mission, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.CollectiveProgressDTO, 0, len(standings))
+	for _, standing := range standings {
+		item := &dto.CollectiveProgressDTO{
+			MissionID:       mission.ID,
+			Scope:           string(mission.Scope),
+			TeamName:        standing.TeamName,
+			TargetValue:     mission.TargetValue,
+			Progress:        standing.Progress.ProgressValue,
+			MinContribution: mission.MinContribution,
+			MyContribution:  standing.MyContribution,
+			Contributors:    []dto.MissionContributorDTO{},
+		}
+		if standing.Progress.TeamID.Valid {
+			teamID := standing.Progress.TeamID.Int64
+			item.TeamID = &teamID
+		}
+		if mission.TargetValue > 0 {
+			item.Percent = math.Min(100, standing.Progress.ProgressValue/mission.TargetValue*100)
+		}
+		if standing.Progress.CompletedAt.Valid {
+			completedAt := standing.Progress.CompletedAt.Time
+			item.CompletedAt = &completedAt
+		}
+		for _, c := range standing.Contributors {
+			item.Contributors = append(item.Contributors, dto.MissionContributorDTO{
+				UserID:       c.UserID,
+				Username:     c.Username,
+				Contribution: c.Contribution,
+				Qualifies:    c.Contribution >= mission.MinContribution,
+			})
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// GetProgressTimeline riwayat perubahan progres user di satu misi beserta log pemicunya
+func (s *missionService) GetProgressTimeline(ctx context.Context, userID, missionID int64, limit int) (*dto.MissionTimelineDTO, error) {
+	mission, err := s.missionRepo.FindByID(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission == nil {
+		return nil, ErrMissionNotFound
+	}
+	if err := s.localizeMissions(ctx, []*model.Mission{mission}); err != nil {
+		return nil, err
+	}
+
+	current, err := s.checkRepo.GetMissionProgress(ctx, userID, missionID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.checkRepo.FindProgressHistory(ctx, userID, missionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.MissionTimelineDTO{
+		MissionID:       mission.ID,
+		UserID:          userID,
+		Title:           mission.Title,
+		TargetValue:     mission.TargetValue,
+		CurrentProgress: current,
+		Entries:         make([]dto.MissionProgressEntryDTO, 0, len(snapshots)),
+	}
+	for _, snap := range snapshots {
+		entry := dto.MissionProgressEntryDTO{
+			ProgressValue: snap.ProgressValue,
+			Delta:         snap.Delta,
+			Trigger:       string(snap.Trigger),
+			EventType:     snap.EventType.String,
+			SourceType:    snap.SourceType.String,
+			RecordedAt:    snap.RecordedAt,
+		}
+		if snap.SourceID.Valid {
+			sourceID := snap.SourceID.Int64
+			entry.SourceID = &sourceID
+		}
+		if snap.PeriodStart.Valid {
+			periodStart := snap.PeriodStart.Time
+			entry.PeriodStart = &periodStart
+		}
+		res.Entries = append(res.Entries, entry)
+	}
+	return res, nil
+}
+
+// maxActiveMissions batas misi aktif yang diikuti manual dari MISSION_MAX_ACTIVE (0 = tanpa batas)
+func maxActiveMissions() int {
+	n, err := strconv.Atoi(os.Getenv("MISSION_MAX_ACTIVE"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// applyMissionSchedule mengisi tanggal mulai dan window progres misi
+func applyMissionSchedule(mission *model.Mission, startsAt *time.Time, window string) error {
+	if startsAt != nil {
+		if mission.ExpiredAt.Valid && !startsAt.Before(mission.ExpiredAt.Time) {
+			return errors.New("starts_at must be before expired_at")
+		}
+		mission.StartsAt = model.NewNullTime(*startsAt)
+	}
+
+	mission.ProgressWindow = model.ProgressWindowEnrollment
+	if window != "" {
+		mission.ProgressWindow = model.ProgressWindow(window)
+	}
+	return nil
+}
+
+// applyMissionRecurrence mengisi aturan pengulangan; custom wajib punya ekspresi cron valid
+func applyMissionRecurrence(mission *model.Mission, recurrence, rule string) error {
+	mission.Recurrence = model.RecurrenceNone
+	if recurrence != "" {
+		mission.Recurrence = model.Recurrence(recurrence)
+	}
+
+	if mission.Recurrence != model.RecurrenceCustom {
+		if rule != "" {
+			return errors.New("recurrence_rule only applies to custom recurrence")
+		}
+		return nil
+	}
+
+	if _, err := model.ParseCron(rule); err != nil {
+		return err
+	}
+	mission.RecurrenceRule = rule
+
+	if _, ok := mission.Period(time.Now()); !ok {
+		return errors.New("recurrence_rule never matches a date")
+	}
+	return nil
+}
+
+// applyMissionScope misi kolektif tidak bisa berulang karena total progres grup tidak direset per periode
+func applyMissionScope(mission *model.Mission, scope string, minContribution float64) error {
+	mission.Scope = model.MissionScopeIndividual
+	if scope != "" {
+		mission.Scope = model.MissionScope(scope)
+	}
+
+	if !mission.IsCollective() {
+		if minContribution > 0 {
+			return errors.New("min_contribution only applies to team or community missions")
+		}
+		return nil
+	}
+
+	if mission.IsRecurring() {
+		return errors.New("team and community missions cannot be recurring")
+	}
+	mission.MinContribution = minContribution
+	return nil
+}
+
+// applyMissionRule memvalidasi aturan JSON lalu menyalin target, window dan comparator-nya
+// ke kolom misi, supaya penilaian (termasuk settlement lte/between) tetap sama
+func applyMissionRule(mission *model.Mission, raw json.RawMessage) (*rule.Rule, error) {
+	// Progres misi proof hanya dari bukti yang disetujui, misi quiz dari kuis yang lulus
+	if mission.MissionType == model.MissionTypeProof || mission.MissionType == model.MissionTypeQuiz {
+		return nil, fmt.Errorf("%s missions cannot use a rule", mission.MissionType)
+	}
+
+	missionRule, err := rule.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := missionRule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rule: %w", err)
+	}
+
+	normalized, err := json.Marshal(missionRule)
+	if err != nil {
+		return nil, err
+	}
+	mission.Rule = model.NewNullString(string(normalized))
+	mission.TargetValue = missionRule.Target
+	if missionRule.Window != "" {
+		mission.ProgressWindow = missionRule.Window
+	}
+	return missionRule, nil
+}
+
+// applyMissionComparator misi lte/between butuh window yang bisa ditutup (expired_at atau
+// pengulangan) karena baru dinilai di akhir window
+func applyMissionComparator(mission *model.Mission, comparator string, targetMax *float64, minActivityLogs int) error {
+	mission.Comparator = model.ComparatorAtLeast
+	if comparator != "" {
+		mission.Comparator = model.MissionComparator(comparator)
+	}
+
+	if mission.Comparator == model.ComparatorBetween {
+		if targetMax == nil || *targetMax < mission.TargetValue {
+			return errors.New("between comparator requires target_max >= target_value")
+		}
+		mission.TargetMax = model.NewNullFloat64(*targetMax)
+	} else if targetMax != nil {
+		return errors.New("target_max only applies to between comparator")
+	}
+
+	if !mission.SettlesAtWindowEnd() {
+		return nil
+	}
+	if !mission.ExpiredAt.Valid && !mission.IsRecurring() {
+		return errors.New("lte and between missions need expired_at or a recurrence")
+	}
+	if mission.IsCollective() {
+		return errors.New("lte and between comparators only apply to individual missions")
+	}
+	if mission.MissionType == model.MissionTypeStreak || mission.MissionType == model.MissionTypeProof ||
+		mission.MissionType == model.MissionTypeQuiz || mission.CriteriaType == model.CriteriaBaselineReduction {
+		return errors.New("lte and between comparators are not supported for streak, proof, quiz or baseline missions")
+	}
+	mission.MinActivityLogs = minActivityLogs
+	return nil
+}
+
 func (s *missionService) missionToDTO(mission *model.Mission) *dto.MissionResponseDTO {
+	var criteriaType *dto.CriteriaType
+	if mission.CriteriaType != "" {
+		ct := dto.CriteriaType(mission.CriteriaType)
+		criteriaType = &ct
+	}
+
 	dto := &dto.MissionResponseDTO{
-		ID:           mission.ID,
-		Title:        mission.Title,
-		Description:  mission.Description,
-		MissionType:  dto.MissionType(mission.MissionType),
-		PointsReward: mission.PointsReward,
-		GivesBadge:   mission.GivesBadge,
-		TargetValue:  mission.TargetValue,
-		CreatedAt:    mission.CreatedAt,
+		ID:               mission.ID,
+		Title:            mission.Title,
+		Description:      mission.Description,
+		MissionType:      dto.MissionType(mission.MissionType),
+		CriteriaType:     criteriaType,
+		PointsReward:     mission.PointsReward,
+		GivesBadge:       mission.GivesBadge,
+		TargetValue:      mission.TargetValue,
+		CreatedAt:        mission.CreatedAt,
+		ProgressWindow:   string(mission.ProgressWindow),
+		Recurrence:       string(mission.Recurrence),
+		RecurrenceRule:   mission.RecurrenceRule,
+		AutoJoin:         mission.AutoJoin,
+		PrerequisiteMode: string(mission.PrerequisiteMode),
+		Prerequisites:    mission.Prerequisites,
+		Scope:            string(mission.Scope),
+		MinContribution:  mission.MinContribution,
+		Comparator:       string(mission.Comparator),
+		MinActivityLogs:  mission.MinActivityLogs,
+	}
+
+	if mission.Rule.Valid {
+		dto.Rule = json.RawMessage(mission.Rule.String)
+	}
+
+	if mission.ArchivedAt.Valid {
+		dto.ArchivedAt = &mission.ArchivedAt.Time
+	}
+
+	if mission.TargetMax.Valid {
+		dto.TargetMax = &mission.TargetMax.Float64
 	}
 
 	if mission.BadgeID.Valid {
@@ -260,5 +758,9 @@ func (s *missionService) missionToDTO(mission *model.Mission) *dto.MissionRespon
 		dto.ExpiredAt = &mission.ExpiredAt.Time
 	}
 
+	if mission.StartsAt.Valid {
+		dto.StartsAt = &mission.StartsAt.Time
+	}
+
 	return dto
 }
@@ -33,6 +33,8 @@ type storeService struct {
 	pointsRepo   repository.PointsRepositoryInterface
 	activityRepo repository.ActivityRepositoryInterface
 	notificationRepo repository.NotificationRepository
+	missionRepo      repository.CheckMissionRepositoryInterface
+	translationRepo  repository.TranslationRepositoryInterface
 }
 
 func NewStoreService(
@@ -40,12 +42,16 @@ func NewStoreService(
 	pointsRepo repository.PointsRepositoryInterface,
 	activityRepo repository.ActivityRepositoryInterface,
 	notificationRepo repository.NotificationRepository,
+	missionRepo repository.CheckMissionRepositoryInterface,
+	translationRepo repository.TranslationRepositoryInterface,
 ) StoreServiceInterface {
 	return &storeService{
 		storeRepo:    storeRepo,
 		pointsRepo:   pointsRepo,
 		activityRepo: activityRepo,
 		notificationRepo: notificationRepo,
+		missionRepo:      missionRepo,
+		translationRepo:  translationRepo,
 	}
 }
 
@@ -68,6 +74,9 @@ func (s *storeService) GetAllStoreItems(ctx context.Context, status string) ([]d
 			CreatedAt:   item.CreatedAt,
 		})
 	}
+	if err := s.localizeStoreItems(ctx, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -80,7 +89,7 @@ func (s *storeService) GetStoreItemByID(ctx context.Context, id int64) (*dto.Sto
 		return nil, errors.New("store item not found")
 	}
 
-	return &dto.StoreItemDTO{
+	result := []dto.StoreItemDTO{{
 		ID:          item.ID,
 		Name:        item.Name,
 		Description: item.Description,
@@ -89,7 +98,23 @@ func (s *storeService) GetStoreItemByID(ctx context.Context, id int64) (*dto.Sto
 		Status:      item.Status,
 		ImageURL:    item.ImageURL,
 		CreatedAt:   item.CreatedAt,
-	}, nil
+	}}
+	if err := s.localizeStoreItems(ctx, result); err != nil {
+		return nil, err
+	}
+	return &result[0], nil
+}
+
+// localizeStoreItems mengganti nama dan deskripsi item dengan terjemahan locale request
+func (s *storeService) localizeStoreItems(ctx context.Context, items []dto.StoreItemDTO) error {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return localize(ctx, s.translationRepo, model.TranslationStoreItem, ids, func(i int, tr *model.Translation) {
+		items[i].Name = tr.Name
+		items[i].Description = localizedText(items[i].Description, tr.Description)
+	})
 }
 
 func (s *storeService) CreateStoreItem(ctx context.Context, req *dto.CreateStoreItemDTO) (*dto.StoreItemDTO, error) {
@@ -274,6 +299,7 @@ func (s *storeService) CreateOrder(ctx context.Context, userID int64, qty int64,
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.publishOrderPlaced(ctx, userID)
 
 	// Get remaining points
 	remainingPoints, err := s.pointsRepo.GetUserPoints(ctx, userID)
@@ -434,7 +460,32 @@ func (s *storeService) CancelOrder(ctx context.Context, userID, orderID int64) e
 	}
 
 	// Commit transaction
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if err := s.missionRepo.Publish(ctx, model.MissionEvent{
+		Type:       model.EventPointsEarned,
+		UserID:     userID,
+		Points:     float64(order.TotalPoints),
+		SourceType: "orders",
+		SourceID:   orderID,
+		OccurredAt: time.Now(),
+	}); err != nil {
+		fmt.Printf("Failed to evaluate missions: %v\n", err)
+	}
+	return nil
+}
+
+// publishOrderPlaced memicu evaluasi misi yang bergantung pada activity log order
+func (s *storeService) publishOrderPlaced(ctx context.Context, userID int64) {
+	if err := s.missionRepo.Publish(ctx, model.MissionEvent{
+		Type:       model.EventOrderPlaced,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+	}); err != nil {
+		fmt.Printf("Failed to evaluate missions: %v\n", err)
+	}
 }
 
 func (s *storeService) CreateOrderByItemID(ctx context.Context, userID, itemID int64, qty int) (*dto.OrderResponseDTO, error) {
@@ -526,6 +577,7 @@ func (s *storeService) CreateOrderByItemID(ctx context.Context, userID, itemID i
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	s.publishOrderPlaced(ctx, userID)
 
 	// Setelah order berhasil dan commit tx
 	notif := &model.Notification{
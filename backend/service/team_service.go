@@ -0,0 +1,125 @@
+// service/team_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+)
+
+var (
+	ErrTeamNotFound      = errors.New("team not found")
+	ErrTeamAlreadyMember = errors.New("already a member of this team")
+	ErrTeamNotMember     = errors.New("not a member of this team")
+)
+
+type TeamServiceInterface interface {
+	CreateTeam(ctx context.Context, userID int64, req *dto.CreateTeamDTO) (*dto.TeamResponseDTO, error)
+	JoinTeam(ctx context.Context, userID int64, req *dto.JoinTeamDTO) (*dto.TeamResponseDTO, error)
+	LeaveTeam(ctx context.Context, userID, teamID int64) error
+	GetMyTeams(ctx context.Context, userID int64) ([]*dto.TeamResponseDTO, error)
+}
+
+type teamService struct {
+	teamRepo repository.TeamRepositoryInterface
+}
+
+func NewTeamService(teamRepo repository.TeamRepositoryInterface) TeamServiceInterface {
+	return &teamService{teamRepo: teamRepo}
+}
+
+func (s *teamService) CreateTeam(ctx context.Context, userID int64, req *dto.CreateTeamDTO) (*dto.TeamResponseDTO, error) {
+	team := &model.Team{
+		Name:      strings.TrimSpace(req.Name),
+		Kind:      model.TeamKindTeam,
+		JoinCode:  strings.ToUpper(helpers.GenerateRandomToken(4)),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if req.Kind != "" {
+		team.Kind = model.TeamKind(req.Kind)
+	}
+
+	if err := s.teamRepo.Create(ctx, team); err != nil {
+		return nil, err
+	}
+	return teamToDTO(team, 1), nil
+}
+
+func (s *teamService) JoinTeam(ctx context.Context, userID int64, req *dto.JoinTeamDTO) (*dto.TeamResponseDTO, error) {
+	team, err := s.teamRepo.FindByJoinCode(ctx, strings.ToUpper(strings.TrimSpace(req.JoinCode)))
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+
+	added, err := s.teamRepo.AddMember(ctx, &model.TeamMember{
+		TeamID: team.ID,
+		UserID: userID,
+		Role:   model.TeamRoleMember,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrTeamAlreadyMember
+	}
+
+	return s.withMemberCount(ctx, team)
+}
+
+func (s *teamService) LeaveTeam(ctx context.Context, userID, teamID int64) error {
+	removed, err := s.teamRepo.RemoveMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrTeamNotMember
+	}
+	return nil
+}
+
+func (s *teamService) GetMyTeams(ctx context.Context, userID int64) ([]*dto.TeamResponseDTO, error) {
+	teams, err := s.teamRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.TeamResponseDTO, 0, len(teams))
+	for _, team := range teams {
+		teamDTO, err := s.withMemberCount(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, teamDTO)
+	}
+	return res, nil
+}
+
+func (s *teamService) withMemberCount(ctx context.Context, team *model.Team) (*dto.TeamResponseDTO, error) {
+	count, err := s.teamRepo.CountMembers(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	return teamToDTO(team, count), nil
+}
+
+func teamToDTO(team *model.Team, memberCount int) *dto.TeamResponseDTO {
+	return &dto.TeamResponseDTO{
+		ID:          team.ID,
+		Name:        team.Name,
+		Kind:        string(team.Kind),
+		JoinCode:    team.JoinCode,
+		CreatedBy:   team.CreatedBy,
+		MemberCount: memberCount,
+		CreatedAt:   team.CreatedAt,
+	}
+}
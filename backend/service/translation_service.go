@@ -0,0 +1,144 @@
+// service/translation_service.go
+package service
+
+import (
+	"context"
+	"errors"
+
+	dto "github.com/Qodarrz/fiber-app/dto"
+	helpers "github.com/Qodarrz/fiber-app/helper"
+	model "github.com/Qodarrz/fiber-app/model"
+	"github.com/Qodarrz/fiber-app/repository"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("translation not found")
+	ErrContentNotFound     = errors.New("content not found")
+	ErrUnsupportedLocale   = errors.New("unsupported locale, use id or en")
+)
+
+type TranslationServiceInterface interface {
+	GetTranslations(ctx context.Context, entity model.TranslationEntity, id int64) (*dto.ContentTranslationsDTO, error)
+	UpsertTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string, req *dto.UpsertTranslationDTO) (*dto.TranslationDTO, error)
+	DeleteTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string) error
+}
+
+type translationService struct {
+	translationRepo repository.TranslationRepositoryInterface
+}
+
+func NewTranslationService(translationRepo repository.TranslationRepositoryInterface) TranslationServiceInterface {
+	return &translationService{translationRepo: translationRepo}
+}
+
+func (s *translationService) GetTranslations(ctx context.Context, entity model.TranslationEntity, id int64) (*dto.ContentTranslationsDTO, error) {
+	if err := s.ensureEntity(ctx, entity, id); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.FindAllTranslations(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ContentTranslationsDTO{
+		Entity:           string(entity),
+		ID:               id,
+		FallbackLocale:   helpers.DefaultLocale(),
+		SupportedLocales: helpers.SupportedLocales,
+		Translations:     make([]dto.TranslationDTO, 0, len(translations)),
+	}
+	for _, tr := range translations {
+		res.Translations = append(res.Translations, translationToDTO(tr))
+	}
+	return res, nil
+}
+
+func (s *translationService) UpsertTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string, req *dto.UpsertTranslationDTO) (*dto.TranslationDTO, error) {
+	normalized, ok := helpers.NormalizeLocale(locale)
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+	if err := s.ensureEntity(ctx, entity, id); err != nil {
+		return nil, err
+	}
+
+	tr := &model.Translation{EntityID: id, Locale: normalized, Name: req.Name, Description: req.Description}
+	if err := s.translationRepo.UpsertTranslation(ctx, entity, tr); err != nil {
+		return nil, err
+	}
+	res := translationToDTO(tr)
+	return &res, nil
+}
+
+func (s *translationService) DeleteTranslation(ctx context.Context, entity model.TranslationEntity, id int64, locale string) error {
+	normalized, ok := helpers.NormalizeLocale(locale)
+	if !ok {
+		return ErrUnsupportedLocale
+	}
+
+	deleted, err := s.translationRepo.DeleteTranslation(ctx, entity, id, normalized)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+func (s *translationService) ensureEntity(ctx context.Context, entity model.TranslationEntity, id int64) error {
+	exists, err := s.translationRepo.EntityExists(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrContentNotFound
+	}
+	return nil
+}
+
+func translationToDTO(tr *model.Translation) dto.TranslationDTO {
+	return dto.TranslationDTO{
+		Locale:      tr.Locale,
+		Name:        tr.Name,
+		Description: tr.Description,
+		UpdatedAt:   tr.UpdatedAt,
+	}
+}
+
+// contentLocales urutan locale yang dicari: locale request lalu locale fallback
+func contentLocales(ctx context.Context) []string {
+	requested, fallback := helpers.LocaleFromContext(ctx), helpers.DefaultLocale()
+	if requested == fallback {
+		return []string{requested}
+	}
+	return []string{requested, fallback}
+}
+
+// localize mengganti teks konten dengan terjemahan locale request (lalu locale fallback).
+// Tanpa terjemahan teks asli tetap dipakai; apply dipanggil per indeks ids yang punya terjemahan.
+func localize(ctx context.Context, repo repository.TranslationRepositoryInterface, entity model.TranslationEntity, ids []int64, apply func(i int, tr *model.Translation)) error {
+	if repo == nil || len(ids) == 0 {
+		return nil
+	}
+
+	translations, err := repo.FindTranslations(ctx, entity, ids, contentLocales(ctx))
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if tr, ok := translations[id]; ok {
+			apply(i, tr)
+		}
+	}
+	return nil
+}
+
+// localizedText deskripsi terjemahan yang kosong tidak menimpa teks asli
+func localizedText(original, translated string) string {
+	if translated == "" {
+		return original
+	}
+	return translated
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	dto "github.com/Qodarrz/fiber-app/dto"
 	models "github.com/Qodarrz/fiber-app/model"
@@ -15,23 +16,28 @@ import (
 type UserProfileServiceInterface interface {
 	GetProfile(ctx context.Context, userID int64) (*dto.UserWithProfileResponseDTO, error)
 	UpdateProfile(ctx context.Context, userID int64, req *dto.UserProfileUpdateDTO) (*dto.UserWithProfileResponseDTO, error)
+	GetStreak(ctx context.Context, userID int64) (*dto.StreakResponseDTO, error)
+	UpdateStreakTimezone(ctx context.Context, userID int64, req *dto.UpdateStreakTimezoneDTO) (*dto.StreakResponseDTO, error)
 }
 
 type UserProfileService struct {
 	userRepo        repository.UserRepositoryInterface
 	userProfileRepo repository.UserProfileRepositoryInterface
 	activityRepo    repository.ActivityRepositoryInterface
+	streakRepo      repository.StreakRepositoryInterface
 }
 
 func NewUserProfileService(
 	userProfileRepo repository.UserProfileRepositoryInterface,
 	activityRepo repository.ActivityRepositoryInterface,
 	userRepo repository.UserRepositoryInterface,
+	streakRepo repository.StreakRepositoryInterface,
 ) *UserProfileService {
 	return &UserProfileService{
 		userProfileRepo: userProfileRepo,
 		activityRepo:    activityRepo,
 		userRepo:       userRepo,
+		streakRepo:      streakRepo,
 	}
 }
 
@@ -62,10 +68,17 @@ func (s *UserProfileService) GetProfile(ctx context.Context, userID int64) (*dto
 			AvatarURL: profile.AvatarURL,
 			Birthdate: profile.Birthdate,
 			Gender:    profile.Gender,
+			Language:  profile.Language,
 			CreatedAt: profile.CreatedAt,
 		},
 	}
 
+	streak, err := s.GetStreak(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil streak: %w", err)
+	}
+	response.Streak = *streak
+
 	return response, nil
 
 }
@@ -113,6 +126,9 @@ func (s *UserProfileService) UpdateProfile(ctx context.Context, userID int64, re
 	if req.Gender != nil {
 		profile.Gender = req.Gender // Dereference jika perlu
 	}
+	if req.Language != nil {
+		profile.Language = req.Language
+	}
 
 	// Update profile di repo
 	if err := s.userProfileRepo.Update(ctx, profile); err != nil {
@@ -137,9 +153,57 @@ func (s *UserProfileService) UpdateProfile(ctx context.Context, userID int64, re
 			AvatarURL: profile.AvatarURL,
 			Birthdate: profile.Birthdate,
 			Gender:    profile.Gender,
+			Language:  profile.Language,
 			CreatedAt: profile.CreatedAt,
 		},
 	}
 
+	streak, err := s.GetStreak(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil streak: %w", err)
+	}
+	response.Streak = *streak
+
 	return response, nil
 }
+
+func (s *UserProfileService) GetStreak(ctx context.Context, userID int64) (*dto.StreakResponseDTO, error) {
+	streak, err := s.streakRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &models.UserStreak{UserID: userID, Timezone: models.DefaultStreakTimezone}
+	}
+	return streakToDTO(streak, time.Now()), nil
+}
+
+func (s *UserProfileService) UpdateStreakTimezone(ctx context.Context, userID int64, req *dto.UpdateStreakTimezoneDTO) (*dto.StreakResponseDTO, error) {
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("timezone tidak dikenal: %s", req.Timezone)
+	}
+
+	if err := s.streakRepo.SetTimezone(ctx, userID, req.Timezone); err != nil {
+		return nil, err
+	}
+	return s.GetStreak(ctx, userID)
+}
+
+// streakToDTO pakai streak efektif hari ini, jadi streak yang sudah putus tampil 0
+func streakToDTO(streak *models.UserStreak, now time.Time) *dto.StreakResponseDTO {
+	today := models.LocalDate(now, streak.Location())
+
+	res := &dto.StreakResponseDTO{
+		Timezone:         streak.Timezone,
+		CurrentStreak:    streak.CurrentAt(today),
+		LongestStreak:    streak.LongestStreak,
+		FreezesAvailable: streak.FreezesAvailable,
+		FreezesUsed:      streak.FreezesUsed,
+	}
+	if streak.LastActiveDate.Valid {
+		last := streak.LastActiveDate.Time
+		res.LastActiveDate = &last
+		res.ActiveToday = last.Equal(today)
+	}
+	return res
+}